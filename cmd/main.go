@@ -1,13 +1,46 @@
 package main
 
 import (
+	"context"
+	"github.com/emiliocc5/online-store-api/internal/app"
+	"github.com/emiliocc5/online-store-api/internal/config"
+	"github.com/emiliocc5/online-store-api/internal/repository"
 	"github.com/emiliocc5/online-store-api/internal/server"
+	"github.com/emiliocc5/online-store-api/internal/utils"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	s := server.New()
+	logger := utils.GetLogger()
+
+	cfg, err := config.Load()
+	if err != nil {
+		logger.Fatalf("unable to load the configuration: %v", err)
+	}
+	if err := utils.SetLogLevel(cfg.Log.Level); err != nil {
+		logger.Fatalf("unable to set the log level: %v", err)
+	}
+
+	repositories, err := repository.Open(cfg.Database, cfg.Features.AutoMigrate)
+	if err != nil {
+		logger.Fatalf("unable to open the storage: %v", err)
+	}
+
+	application, err := app.New(cfg, repositories)
+	if err != nil {
+		logger.Fatalf("unable to build the application: %v", err)
+	}
+
+	s := server.New(cfg, application)
 
 	s.ConfigureRoutes()
 
-	s.Run()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s.StartBackgroundJobs(ctx)
+
+	s.Run(ctx)
 }
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/config"
+	"github.com/emiliocc5/online-store-api/internal/downloads"
+	"github.com/emiliocc5/online-store-api/internal/payment"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/emiliocc5/online-store-api/internal/shipping"
+	"github.com/emiliocc5/online-store-api/internal/utils"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+)
+
+// App holds everything the routes and the background jobs are served by.
+type App struct {
+	CartHandler         handler.CartHandler
+	OrderHandler        handler.OrderHandler
+	ProductHandler      handler.ProductHandler
+	CategoryHandler     handler.CategoryHandler
+	AdminProductHandler handler.AdminProductHandler
+	DownloadHandler     handler.DownloadHandler
+	AddressHandler      handler.AddressHandler
+	ClientHandler       handler.ClientHandler
+	AuthHandler         handler.AuthHandler
+	ApiKeyHandler       handler.ApiKeyHandler
+
+	Authenticate  gin.HandlerFunc
+	Tokens        *auth.TokenIssuer
+	ApiKeyService services.ApiKeyService
+	OrderService  services.OrderService
+}
+
+// New builds the services and handlers of the store on top of the given
+// repositories.
+func New(cfg config.Config, repos repository.Repositories) (*App, error) {
+	logger := utils.GetLogger()
+
+	shippingCalculator, err := shipping.NewCalculator(cfg.Shipping.RateTablesOrDefault(), cfg.Catalog.Currencies)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to load the shipping rates: %v", err))
+	}
+	shippingZones := cfg.Shipping.ZonesOrDefault()
+	if err := shippingCalculator.CheckZones(shippingZones); err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to load the shipping zones: %v", err))
+	}
+	shippingService := &services.ShippingServiceImpl{
+		CartRepository:    repos.Cart,
+		ClientRepository:  repos.Client,
+		AddressRepository: repos.Address,
+		Calculator:        shippingCalculator,
+		Zones:             shippingZones,
+	}
+
+	authSecret := []byte(cfg.Auth.SigningSecret)
+	if len(authSecret) == 0 {
+		logger.Warn("auth.signingSecret is not set, access tokens will stop working on restart")
+		authSecret = auth.RandomSecret()
+	}
+	tokens, err := auth.NewTokenIssuer(authSecret, services.AccessTokenLifetime)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to create the token issuer: %v", err))
+	}
+
+	downloadSecret := []byte(cfg.Downloads.SigningSecret)
+	if len(downloadSecret) == 0 {
+		logger.Warn("downloads.signingSecret is not set, download links will stop working on restart")
+		downloadSecret = downloads.RandomSecret()
+	}
+	downloadSigner, err := downloads.NewSigner(downloadSecret, services.DownloadLinkLifetime)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to create the download signer: %v", err))
+	}
+
+	paymentProvider, err := payment.NewFakeProvider(cfg.Payment.FakeBehavior)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("unable to create the payment provider: %v", err))
+	}
+	paymentService := &services.PaymentServiceImpl{
+		OrderRepository:   repos.Order,
+		PaymentRepository: repos.Payment,
+		PaymentProvider:   paymentProvider,
+	}
+	orderService := &services.OrderServiceImpl{
+		OrderRepository: repos.Order,
+		PaymentService:  paymentService,
+		ShippingService: shippingService,
+	}
+	apiKeyService := &services.ApiKeyServiceImpl{
+		ApiKeyRepository: repos.ApiKey,
+		BootstrapKey:     cfg.Auth.AdminApiKey,
+	}
+	catalogService := &services.CatalogServiceImpl{
+		ProductRepository: repos.Product,
+	}
+
+	return &App{
+		CartHandler: &handler.CartHandlerImpl{
+			CartService: &services.CartServiceImpl{
+				CartRepository:    repos.Cart,
+				ClientRepository:  repos.Client,
+				ProductRepository: repos.Product,
+				StockRepository:   repos.Stock,
+			},
+			ShippingService: shippingService,
+		},
+		OrderHandler: &handler.OrderHandlerImpl{
+			OrderService:   orderService,
+			PaymentService: paymentService,
+		},
+		ProductHandler: &handler.ProductHandlerImpl{
+			CatalogService: catalogService,
+		},
+		CategoryHandler: &handler.CategoryHandlerImpl{
+			CategoryService: &services.CategoryServiceImpl{
+				CategoryRepository: repos.Category,
+				CatalogService:     catalogService,
+			},
+		},
+		AdminProductHandler: &handler.AdminProductHandlerImpl{
+			ProductAdminService: &services.ProductAdminServiceImpl{
+				ProductRepository:  repos.Product,
+				CategoryRepository: repos.Category,
+				Currencies:         cfg.Catalog.Currencies,
+			},
+			InventoryService: &services.InventoryServiceImpl{
+				ProductRepository: repos.Product,
+				StockRepository:   repos.Stock,
+			},
+		},
+		DownloadHandler: &handler.DownloadHandlerImpl{
+			DownloadService: &services.DownloadServiceImpl{
+				OrderRepository:    repos.Order,
+				ProductRepository:  repos.Product,
+				DownloadRepository: repos.Download,
+				Signer:             downloadSigner,
+			},
+		},
+		AddressHandler: &handler.AddressHandlerImpl{
+			AddressService: &services.AddressServiceImpl{
+				AddressRepository: repos.Address,
+				ClientRepository:  repos.Client,
+			},
+		},
+		ClientHandler: &handler.ClientHandlerImpl{
+			ClientService: &services.ClientServiceImpl{
+				ClientRepository: repos.Client,
+			},
+		},
+		AuthHandler: &handler.AuthHandlerImpl{
+			AuthService: &services.AuthServiceImpl{
+				ClientRepository: repos.Client,
+				TokenRepository:  repos.Token,
+				Tokens:           tokens,
+			},
+		},
+		ApiKeyHandler: &handler.ApiKeyHandlerImpl{
+			ApiKeyService: apiKeyService,
+		},
+
+		Authenticate:  handler.Authenticate(tokens),
+		Tokens:        tokens,
+		ApiKeyService: apiKeyService,
+		OrderService:  orderService,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	apiKeyLabel       = "osk"
+	apiKeyPrefixBytes = 4
+	apiKeySecretBytes = 24
+)
+
+var (
+	ErrInvalidApiKey = errors.New("invalid or revoked api key")
+	ErrMissingScope  = errors.New("missing the required scope")
+)
+
+// NewApiKey returns a key shaped osk_<prefix>_<secret>, the prefix that
+// identifies it and the hash to store in its place. The key itself is only
+// shown once.
+func NewApiKey() (string, string, string) {
+	prefix := randomHex(apiKeyPrefixBytes)
+	key := apiKeyLabel + "_" + prefix + "_" + randomHex(apiKeySecretBytes)
+	return key, prefix, HashApiKey(key)
+}
+
+// ApiKeyPrefix extracts the prefix of a key so it can be looked up.
+func ApiKeyPrefix(key string) (string, error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != apiKeyLabel || len(parts[1]) != 2*apiKeyPrefixBytes || parts[2] == "" {
+		return "", ErrInvalidApiKey
+	}
+	return parts[1], nil
+}
+
+// HashApiKey needs no salt or stretching: keys are random, not chosen by
+// people, so there is nothing to guess.
+func HashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) string {
+	raw := make([]byte, n)
+	_, _ = rand.Read(raw)
+	return hex.EncodeToString(raw)
+}
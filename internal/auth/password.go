@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	MinPasswordLength = 8
+	// MaxPasswordLength is the most bcrypt looks at; longer passwords would be
+	// silently truncated.
+	MaxPasswordLength = 72
+)
+
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return "", errors.New(fmt.Sprintf("the password must have between %v and %v characters", MinPasswordLength, MaxPasswordLength))
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
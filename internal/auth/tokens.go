@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/golang-jwt/jwt/v4"
+	"strconv"
+	"time"
+)
+
+const (
+	minSecretLength = 32
+	tokenIssuer     = "online-store-api"
+)
+
+var (
+	ErrInvalidToken        = errors.New("invalid or expired token")
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	errSecretTooShort      = errors.New(fmt.Sprintf("the token signing secret must have at least %v bytes", minSecretLength))
+	errNonPositiveLifetime = errors.New("the access token lifetime must be positive")
+)
+
+type (
+	// TokenIssuer signs the short lived access tokens sent on every request.
+	// They are HS256 JWTs whose subject is the id of the client. The role is
+	// carried along, so a role change applies once the token is refreshed.
+	TokenIssuer struct {
+		secret   []byte
+		lifetime time.Duration
+	}
+	accessClaims struct {
+		Role models.Role `json:"role"`
+		jwt.RegisteredClaims
+	}
+)
+
+func NewTokenIssuer(secret []byte, lifetime time.Duration) (*TokenIssuer, error) {
+	if len(secret) < minSecretLength {
+		return nil, errSecretTooShort
+	}
+	if lifetime <= 0 {
+		return nil, errNonPositiveLifetime
+	}
+	return &TokenIssuer{secret: secret, lifetime: lifetime}, nil
+}
+
+// RandomSecret returns a secret for when none is configured. Tokens signed
+// with it stop working once the process restarts.
+func RandomSecret() []byte {
+	secret := make([]byte, minSecretLength)
+	_, _ = rand.Read(secret)
+	return secret
+}
+
+func (ti *TokenIssuer) IssueAccessToken(clientId int, role models.Role, issuedAt time.Time) (string, time.Time, error) {
+	issuedAt = issuedAt.Truncate(time.Second)
+	expiresAt := issuedAt.Add(ti.lifetime)
+	claims := accessClaims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    tokenIssuer,
+			Subject:   strconv.Itoa(clientId),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ti.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// ParseAccessToken checks the signature and expiry of the token and returns
+// the id and role of the client it was issued to.
+func (ti *TokenIssuer) ParseAccessToken(token string) (int, models.Role, error) {
+	claims := accessClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	_, err := parser.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return ti.secret, nil
+	})
+	if err != nil || !claims.VerifyIssuer(tokenIssuer, true) {
+		return 0, "", ErrInvalidToken
+	}
+
+	clientId, err := strconv.Atoi(claims.Subject)
+	if err != nil || clientId <= 0 || !claims.Role.IsValid() {
+		return 0, "", ErrInvalidToken
+	}
+	return clientId, claims.Role, nil
+}
+
+// NewRefreshToken returns an opaque token for the client to keep and the hash
+// to store in its place, so a leaked table cannot be replayed.
+func NewRefreshToken() (string, string) {
+	raw := make([]byte, 32)
+	_, _ = rand.Read(raw)
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashRefreshToken(token)
+}
+
+// NewPasswordResetToken returns a single-use token and its hash, built like
+// refresh tokens.
+func NewPasswordResetToken() (string, string) {
+	return NewRefreshToken()
+}
+
+func HashPasswordResetToken(token string) string {
+	return HashRefreshToken(token)
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
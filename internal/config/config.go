@@ -0,0 +1,271 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/payment"
+	"github.com/emiliocc5/online-store-api/internal/shipping"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"time"
+)
+
+const (
+	DriverPostgres = "postgres"
+	// DriverSqlite keeps the database in a single file, so no server is
+	// needed. It is meant for development and CI.
+	DriverSqlite = "sqlite"
+	// DriverMemory keeps everything in memory, which is lost on restart. It is
+	// meant for demos and tests.
+	DriverMemory = "memory"
+
+	// The minimum lengths match what the token issuer, the api key service
+	// and the download signer accept.
+	minAuthSecretLength     = 32
+	minAdminApiKeyLength    = 32
+	minDownloadSecretLength = 16
+)
+
+var (
+	drivers = map[string]bool{
+		DriverPostgres: true,
+		DriverSqlite:   true,
+		DriverMemory:   true,
+	}
+	sslModes = map[string]bool{
+		"disable":     true,
+		"allow":       true,
+		"prefer":      true,
+		"require":     true,
+		"verify-ca":   true,
+		"verify-full": true,
+	}
+	fakePaymentBehaviors = map[string]bool{
+		payment.FakeBehaviorApprove:             true,
+		payment.FakeBehaviorDecline:             true,
+		payment.FakeBehaviorTimeout:             true,
+		payment.FakeBehaviorRequireConfirmation: true,
+	}
+)
+
+type (
+	// Config holds every setting of the service. Each field can be set from
+	// the YAML file under its yaml key or from the environment, or the .env
+	// file, under its env key.
+	Config struct {
+		Server    ServerConfig    `yaml:"server"`
+		Database  DatabaseConfig  `yaml:"database"`
+		Log       LogConfig       `yaml:"log"`
+		Auth      AuthConfig      `yaml:"auth"`
+		Downloads DownloadsConfig `yaml:"downloads"`
+		Payment   PaymentConfig   `yaml:"payment"`
+		Catalog   CatalogConfig   `yaml:"catalog"`
+		Shipping  ShippingConfig  `yaml:"shipping"`
+		Features  FeaturesConfig  `yaml:"features"`
+	}
+	ServerConfig struct {
+		Port         int           `yaml:"port" env:"PORT"`
+		ReadTimeout  time.Duration `yaml:"readTimeout" env:"HTTP_READ_TIMEOUT"`
+		WriteTimeout time.Duration `yaml:"writeTimeout" env:"HTTP_WRITE_TIMEOUT"`
+		IdleTimeout  time.Duration `yaml:"idleTimeout" env:"HTTP_IDLE_TIMEOUT"`
+	}
+	DatabaseConfig struct {
+		Driver         string        `yaml:"driver" env:"DB_DRIVER"`
+		Host           string        `yaml:"host" env:"DB_HOST"`
+		Port           int           `yaml:"port" env:"DB_PORT"`
+		User           string        `yaml:"user" env:"DB_USER"`
+		Password       string        `yaml:"password" env:"DB_PASSWORD"`
+		Name           string        `yaml:"name" env:"DB_NAME"`
+		SSLMode        string        `yaml:"sslMode" env:"DB_SSLMODE"`
+		ConnectTimeout time.Duration `yaml:"connectTimeout" env:"DB_CONNECT_TIMEOUT"`
+		// Path is the database file of the sqlite driver.
+		Path string `yaml:"path" env:"DB_PATH"`
+	}
+	LogConfig struct {
+		Level string `yaml:"level" env:"LOG_LEVEL"`
+	}
+	AuthConfig struct {
+		// SigningSecret signs the access tokens. When empty a random one is
+		// used, so tokens stop working on restart.
+		SigningSecret string `yaml:"signingSecret" env:"AUTH_SIGNING_SECRET"`
+		// AdminApiKey is a bootstrap api key holding every scope, meant to
+		// create the first real keys.
+		AdminApiKey string `yaml:"adminApiKey" env:"ADMIN_API_KEY"`
+	}
+	DownloadsConfig struct {
+		// SigningSecret signs the download links. When empty a random one is
+		// used, so links stop working on restart.
+		SigningSecret string `yaml:"signingSecret" env:"DOWNLOAD_SIGNING_SECRET"`
+	}
+	PaymentConfig struct {
+		FakeBehavior string `yaml:"fakeBehavior" env:"PAYMENT_FAKE_BEHAVIOR"`
+	}
+	// CatalogConfig is only read from the YAML file.
+	CatalogConfig struct {
+		// Currencies lists the ISO 4217 codes products and shipping rates can
+		// be priced in.
+		Currencies []string `yaml:"currencies"`
+	}
+	// ShippingConfig prices the shipping options. It is only read from the
+	// YAML file; when a part is left empty the built-in rates of a store
+	// shipping from the US are used for it.
+	ShippingConfig struct {
+		RateTables []shipping.RateTable   `yaml:"rateTables"`
+		Zones      *shipping.CountryZones `yaml:"zones"`
+	}
+	FeaturesConfig struct {
+		// AutoMigrate creates and updates the tables on startup.
+		AutoMigrate bool `yaml:"autoMigrate" env:"FEATURE_AUTO_MIGRATE"`
+		// BackgroundJobs runs the periodic maintenance tasks, such as giving
+		// back the stock of abandoned orders.
+		BackgroundJobs bool `yaml:"backgroundJobs" env:"FEATURE_BACKGROUND_JOBS"`
+	}
+)
+
+// Default returns the settings used for anything left unset.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:         8080,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 30 * time.Second,
+			IdleTimeout:  time.Minute,
+		},
+		Database: DatabaseConfig{
+			Driver:         DriverPostgres,
+			Host:           "localhost",
+			Port:           5432,
+			User:           "goApiUser",
+			Name:           "OnlineStore",
+			SSLMode:        "disable",
+			ConnectTimeout: 5 * time.Second,
+			Path:           "online-store.db",
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Payment: PaymentConfig{
+			FakeBehavior: payment.FakeBehaviorApprove,
+		},
+		Catalog: CatalogConfig{
+			Currencies: []string{"USD"},
+		},
+		Features: FeaturesConfig{
+			AutoMigrate:    true,
+			BackgroundJobs: true,
+		},
+	}
+}
+
+// Validate reports every invalid setting at once.
+func (c Config) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(isValidPort(c.Server.Port), "server.port must be between 1 and 65535")
+	check(c.Server.ReadTimeout > 0, "server.readTimeout must be positive")
+	check(c.Server.WriteTimeout > 0, "server.writeTimeout must be positive")
+	check(c.Server.IdleTimeout > 0, "server.idleTimeout must be positive")
+
+	check(drivers[c.Database.Driver], "unsupported database.driver: %v", c.Database.Driver)
+	switch c.Database.Driver {
+	case DriverPostgres:
+		check(strings.TrimSpace(c.Database.Host) != "", "database.host is required")
+		check(isValidPort(c.Database.Port), "database.port must be between 1 and 65535")
+		check(strings.TrimSpace(c.Database.User) != "", "database.user is required")
+		check(strings.TrimSpace(c.Database.Name) != "", "database.name is required")
+		check(sslModes[c.Database.SSLMode], "unsupported database.sslMode: %v", c.Database.SSLMode)
+		check(c.Database.ConnectTimeout >= time.Second, "database.connectTimeout must be at least 1s")
+	case DriverSqlite:
+		check(strings.TrimSpace(c.Database.Path) != "", "database.path is required")
+	}
+
+	_, err := logrus.ParseLevel(c.Log.Level)
+	check(err == nil, "unsupported log.level: %v", c.Log.Level)
+
+	check(c.Auth.SigningSecret == "" || len(c.Auth.SigningSecret) >= minAuthSecretLength,
+		"auth.signingSecret must have at least %v characters", minAuthSecretLength)
+	check(c.Auth.AdminApiKey == "" || len(c.Auth.AdminApiKey) >= minAdminApiKeyLength,
+		"auth.adminApiKey must have at least %v characters", minAdminApiKeyLength)
+	check(c.Downloads.SigningSecret == "" || len(c.Downloads.SigningSecret) >= minDownloadSecretLength,
+		"downloads.signingSecret must have at least %v characters", minDownloadSecretLength)
+
+	check(fakePaymentBehaviors[c.Payment.FakeBehavior], "unsupported payment.fakeBehavior: %v", c.Payment.FakeBehavior)
+
+	check(len(c.Catalog.Currencies) > 0, "catalog.currencies needs at least one currency")
+	for _, currency := range c.Catalog.Currencies {
+		_, err := models.NewMoney(0, currency)
+		check(err == nil, "unsupported catalog.currencies entry: %v", currency)
+	}
+
+	if len(problems) > 0 {
+		return errors.New(fmt.Sprintf("invalid configuration: %v", strings.Join(problems, "; ")))
+	}
+	return nil
+}
+
+// normalize upper-cases the codes read from the YAML file, which are looked up
+// upper-cased, and rejects the zone countries listed twice.
+func (c *Config) normalize() error {
+	for i, currency := range c.Catalog.Currencies {
+		c.Catalog.Currencies[i] = strings.ToUpper(strings.TrimSpace(currency))
+	}
+	if c.Shipping.Zones != nil {
+		zones, err := c.Shipping.Zones.Normalized()
+		if err != nil {
+			return err
+		}
+		c.Shipping.Zones = &zones
+	}
+	return nil
+}
+
+// DSN builds the connection string of the configured driver.
+func (dc DatabaseConfig) DSN() string {
+	if dc.Driver == DriverSqlite {
+		// Foreign keys are enforced as they are on Postgres, and a write from
+		// another process is waited for instead of failing right away.
+		return fmt.Sprintf("file:%v?_foreign_keys=1&_busy_timeout=%v", dc.Path, dc.ConnectTimeout.Milliseconds())
+	}
+	return fmt.Sprintf("host=%v port=%v user=%v password=%v dbname=%v sslmode=%v connect_timeout=%v",
+		quoteDSNValue(dc.Host), dc.Port, quoteDSNValue(dc.User), quoteDSNValue(dc.Password),
+		quoteDSNValue(dc.Name), dc.SSLMode, int(dc.ConnectTimeout.Seconds()))
+}
+
+// quoteDSNValue quotes values that would otherwise break the key=value
+// format, such as passwords with spaces.
+func quoteDSNValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+func isValidPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+// RateTablesOrDefault returns the configured rate tables, or the built-in
+// ones when none are set.
+func (sc ShippingConfig) RateTablesOrDefault() []shipping.RateTable {
+	if len(sc.RateTables) == 0 {
+		return shipping.DefaultRateTables()
+	}
+	return sc.RateTables
+}
+
+// ZonesOrDefault returns the configured country zones, or the built-in ones
+// when none are set.
+func (sc ShippingConfig) ZonesOrDefault() shipping.CountryZones {
+	if sc.Zones == nil {
+		return shipping.DefaultCountryZones()
+	}
+	return *sc.Zones
+}
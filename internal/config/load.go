@@ -0,0 +1,182 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DotEnvFile = ".env"
+
+	// ConfigFileEnv names the optional YAML file to read. It can be set in the
+	// environment or in the .env file.
+	ConfigFileEnv = "CONFIG_FILE"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Load reads the configuration from the defaults, the YAML file named by
+// CONFIG_FILE, the .env file of the working directory and the environment,
+// each one overriding the previous.
+func Load() (Config, error) {
+	return LoadFrom(DotEnvFile)
+}
+
+// LoadFrom works like Load but reads the given .env file, which may not
+// exist.
+func LoadFrom(dotEnvPath string) (Config, error) {
+	cfg := Default()
+
+	dotEnv, err := readDotEnv(dotEnvPath)
+	if err != nil {
+		return cfg, err
+	}
+	lookup := func(key string) (string, bool) {
+		if value, ok := os.LookupEnv(key); ok {
+			return value, true
+		}
+		value, ok := dotEnv[key]
+		return value, ok
+	}
+
+	if path, ok := lookup(ConfigFileEnv); ok && path != "" {
+		if err := readYaml(path, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(&cfg).Elem(), lookup); err != nil {
+		return cfg, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// readDotEnv parses KEY=VALUE lines, skipping blank lines and comments. A
+// missing file is not an error.
+func readDotEnv(path string) (map[string]string, error) {
+	values := map[string]string{}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, errors.New(fmt.Sprintf("%v:%v: expected KEY=VALUE", path, lineNumber))
+		}
+		values[key] = unquote(strings.TrimSpace(value))
+	}
+	return values, scanner.Err()
+}
+
+func readYaml(path string, cfg *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.New(fmt.Sprintf("unable to read the config file: %v", err))
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil {
+		return errors.New(fmt.Sprintf("unable to parse the config file %v: %v", path, err))
+	}
+	if err := cfg.normalize(); err != nil {
+		return errors.New(fmt.Sprintf("invalid config file %v: %v", path, err))
+	}
+	return nil
+}
+
+// applyEnv sets every field tagged with env whose variable is defined.
+func applyEnv(value reflect.Value, lookup func(string) (string, bool)) error {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := value.Type().Field(i).Tag.Get("env")
+		if key == "" {
+			continue
+		}
+		raw, ok := lookup(key)
+		if !ok {
+			continue
+		}
+		if err := setField(field, strings.TrimSpace(raw)); err != nil {
+			return errors.New(fmt.Sprintf("invalid value for %v: %v", key, raw))
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, raw string) error {
+	switch {
+	case field.Type() == durationType:
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+	case field.Kind() == reflect.Int:
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(number))
+	case field.Kind() == reflect.Bool:
+		flag, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(flag)
+	case field.Kind() == reflect.String:
+		field.SetString(raw)
+	default:
+		return errors.New(fmt.Sprintf("unsupported setting type: %v", field.Type()))
+	}
+	return nil
+}
+
+// cut is strings.Cut, which is not available in go 1.17.
+func cut(s, sep string) (string, string, bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
@@ -0,0 +1,83 @@
+package downloads
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	minSecretLength = 16
+)
+
+var (
+	ErrInvalidLink         = errors.New("invalid download link")
+	ErrDownloadNotAllowed  = errors.New("download not allowed")
+	errSecretTooShort      = errors.New(fmt.Sprintf("the download signing secret must have at least %v bytes", minSecretLength))
+	errNonPositiveLifetime = errors.New("the download link lifetime must be positive")
+)
+
+type (
+	// Signer issues download links that stop working after a while. The
+	// signature covers the order, the product and the expiry, so changing any
+	// of them invalidates the link.
+	Signer struct {
+		secret   []byte
+		lifetime time.Duration
+	}
+	SignedLink struct {
+		OrderId   int
+		ProductId int
+		ExpiresAt time.Time
+		Signature string
+	}
+)
+
+func NewSigner(secret []byte, lifetime time.Duration) (*Signer, error) {
+	if len(secret) < minSecretLength {
+		return nil, errSecretTooShort
+	}
+	if lifetime <= 0 {
+		return nil, errNonPositiveLifetime
+	}
+	return &Signer{secret: secret, lifetime: lifetime}, nil
+}
+
+// RandomSecret returns a secret for when none is configured. Links signed
+// with it stop working once the process restarts.
+func RandomSecret() []byte {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+	return secret
+}
+
+func (s *Signer) Sign(orderId, productId int, issuedAt time.Time) SignedLink {
+	expiresAt := issuedAt.Add(s.lifetime).Truncate(time.Second)
+	return SignedLink{
+		OrderId:   orderId,
+		ProductId: productId,
+		ExpiresAt: expiresAt,
+		Signature: s.signature(orderId, productId, expiresAt.Unix()),
+	}
+}
+
+func (s *Signer) Verify(link SignedLink, now time.Time) error {
+	expected := s.signature(link.OrderId, link.ProductId, link.ExpiresAt.Unix())
+	if !hmac.Equal([]byte(expected), []byte(link.Signature)) {
+		return fmt.Errorf("%w: the signature does not match", ErrInvalidLink)
+	}
+	if !now.Before(link.ExpiresAt) {
+		return fmt.Errorf("%w: the link expired at %v", ErrInvalidLink, link.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (s *Signer) signature(orderId, productId int, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(fmt.Sprintf("%v:%v:%v", orderId, productId, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// PostalAddress is where a parcel goes. Orders keep their own copy, so editing
+// the address book does not rewrite past orders.
+type PostalAddress struct {
+	Recipient  string
+	Line1      string
+	Line2      string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string `gorm:"size:2"`
+}
+
+// Address is an entry of the address book of a client. At most one address
+// per client is flagged as the default for shipping and one for billing.
+type Address struct {
+	Id       int `gorm:"primarykey"`
+	ClientId int `gorm:"index"`
+	PostalAddress
+	DefaultShipping bool `gorm:"not null;default:false"`
+	DefaultBilling  bool `gorm:"not null;default:false"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
@@ -1,6 +1,7 @@
 package models
 
 type Category struct {
-	Id    int `gorm:"primarykey"`
-	Label string
+	Id       int `gorm:"primarykey"`
+	Label    string
+	ParentId *int `gorm:"index"`
 }
@@ -1,6 +1,31 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ErrEmailTaken = errors.New("email already registered")
+)
+
+// Client is an account of the store, customers and staff alike; Role tells
+// what it may do. Deleting an account only flags it, so the orders it placed
+// keep resolving; the email is freed for a new signup.
 type Client struct {
-	Id   int `gorm:"primarykey"`
-	Name string
+	Id           int `gorm:"primarykey"`
+	Name         string
+	Email        string `gorm:"uniqueIndex:idx_clients_email,where:deleted_at IS NULL"`
+	Phone        string
+	PasswordHash string
+	Role         Role `gorm:"not null;default:customer"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+}
+
+func NewEmailTakenError(email string) error {
+	return fmt.Errorf("%w: %v", ErrEmailTaken, email)
 }
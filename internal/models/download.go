@@ -0,0 +1,20 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrDownloadLimitReached = errors.New("download limit reached")
+)
+
+// Download is the audit record of a digital product handed out to a client.
+type Download struct {
+	Id        int `gorm:"primarykey"`
+	OrderId   int `gorm:"index"`
+	ProductId int
+	IpAddress string
+	UserAgent string
+	CreatedAt time.Time
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("unable to operate on amounts with different currencies")
+
+	currencyCodePattern = regexp.MustCompile("^[A-Z]{3}$")
+
+	// minorUnitsByCurrency lists the ISO 4217 currencies whose minor unit is not
+	// cents; any other currency is assumed to have two decimal places.
+	minorUnitsByCurrency = map[string]int{
+		"BHD": 3,
+		"CLP": 0,
+		"ISK": 0,
+		"JPY": 0,
+		"KRW": 0,
+		"KWD": 3,
+		"OMR": 3,
+		"PYG": 0,
+		"TND": 3,
+		"VND": 0,
+	}
+)
+
+// Money is an amount expressed in the minor units of an ISO 4217 currency
+// (e.g. cents for USD), so arithmetic never goes through floating point.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency" gorm:"size:3"`
+}
+
+func NewMoney(amount int64, currency string) (Money, error) {
+	currency = strings.ToUpper(currency)
+	if !currencyCodePattern.MatchString(currency) {
+		return Money{}, errors.New(fmt.Sprintf("invalid currency code: %v", currency))
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add sums two amounts. A zero amount without currency is treated as the
+// neutral element so totals can start from Money{}.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency == "" && m.IsZero() {
+		return other, nil
+	}
+	if other.Currency == "" && other.IsZero() {
+		return m, nil
+	}
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Multiply(quantity int) Money {
+	return Money{Amount: m.Amount * int64(quantity), Currency: m.Currency}
+}
+
+// String formats the amount with the currency's decimal places, e.g. "12.50 USD".
+func (m Money) String() string {
+	digits, ok := minorUnitsByCurrency[m.Currency]
+	if !ok {
+		digits = 2
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	formatted := fmt.Sprintf("%v", amount)
+	if digits > 0 {
+		divisor := int64(1)
+		for i := 0; i < digits; i++ {
+			divisor *= 10
+		}
+		formatted = fmt.Sprintf("%d.%0*d", amount/divisor, digits, amount%divisor)
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%v%v %v", sign, formatted, m.Currency))
+}
@@ -1,9 +1,46 @@
 package models
 
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrStaleShippingQuote = errors.New("the cart changed since the shipping was quoted")
+)
+
+// Order is a placed cart. CancellationReason explains why a cancelled order was
+// cancelled and RefundPending flags one whose payment was already collected.
+// Total includes the shipping cost.
 type Order struct {
-	Id       int `gorm:"primarykey"`
-	ClientId int
-	Client   Client
-	CartId   int
-	Cart     Cart //TODO Check if this its neccessary
+	Id                 int `gorm:"primarykey"`
+	ClientId           int
+	Client             Client
+	Items              []OrderItem
+	Total              Money         `gorm:"embedded;embeddedPrefix:total_"`
+	Shipping           OrderShipping `gorm:"embedded;embeddedPrefix:shipping_"`
+	Status             OrderStatus   `gorm:"not null;default:pending;index"`
+	CancellationReason string
+	RefundPending      bool `gorm:"not null;default:false"`
+	CreatedAt          time.Time
+}
+
+// OrderShipping is the shipping option and address chosen at checkout. Orders
+// without physical products leave it empty. Weight is the parcel weight, in
+// kilograms, the cost was quoted for.
+type OrderShipping struct {
+	Option  string
+	Zone    string
+	Weight  float64       `gorm:"not null;default:0"`
+	Cost    Money         `gorm:"embedded;embeddedPrefix:cost_"`
+	Address PostalAddress `gorm:"embedded;embeddedPrefix:address_"`
+}
+
+func (s OrderShipping) IsEmpty() bool {
+	return s.Option == ""
+}
+
+type OrderSummary struct {
+	Order     Order
+	ItemCount int
 }
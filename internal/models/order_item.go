@@ -0,0 +1,12 @@
+package models
+
+type OrderItem struct {
+	Id            int `gorm:"primarykey"`
+	OrderId       int `gorm:"index"`
+	ProductId     int
+	Product       Product
+	UnitPrice     Money `gorm:"embedded;embeddedPrefix:unit_price_"`
+	Quantity      int   `gorm:"not null;default:1"`
+	StockReserved bool  `gorm:"not null;default:false"`
+	Downloads     int   `gorm:"not null;default:0"`
+}
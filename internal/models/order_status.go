@@ -0,0 +1,59 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusFulfilled OrderStatus = "fulfilled"
+	OrderStatusShipped   OrderStatus = "shipped"
+	OrderStatusDelivered OrderStatus = "delivered"
+	OrderStatusCancelled OrderStatus = "cancelled"
+	OrderStatusRefunded  OrderStatus = "refunded"
+)
+
+var (
+	ErrInvalidOrderTransition = errors.New("invalid order transition")
+
+	// orderTransitions lists, for every status, the statuses an order can move
+	// to next. Once shipped an order can no longer be cancelled, only refunded
+	// after delivery.
+	orderTransitions = map[OrderStatus][]OrderStatus{
+		OrderStatusPending:   {OrderStatusPaid, OrderStatusCancelled},
+		OrderStatusPaid:      {OrderStatusFulfilled, OrderStatusCancelled},
+		OrderStatusFulfilled: {OrderStatusShipped, OrderStatusCancelled},
+		OrderStatusShipped:   {OrderStatusDelivered},
+		OrderStatusDelivered: {OrderStatusRefunded},
+		OrderStatusCancelled: {OrderStatusRefunded},
+		OrderStatusRefunded:  {},
+	}
+)
+
+func (s OrderStatus) IsValid() bool {
+	_, ok := orderTransitions[s]
+	return ok
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCollectedPayment reports whether an order in this status was paid for,
+// so cancelling it owes the customer a refund.
+func (s OrderStatus) HasCollectedPayment() bool {
+	return s == OrderStatusPaid || s == OrderStatusFulfilled || s == OrderStatusShipped || s == OrderStatusDelivered
+}
+
+func NewInvalidOrderTransitionError(from, to OrderStatus) error {
+	return fmt.Errorf("%w: an order cannot move from %v to %v", ErrInvalidOrderTransition, from, to)
+}
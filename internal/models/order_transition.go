@@ -0,0 +1,32 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// SystemActor is recorded on transitions the store makes on its own, such as
+// cancelling orders that were never paid.
+const SystemActor = "system"
+
+// StaffActor names the signed-in client behind a transition along with the
+// role they acted with.
+func StaffActor(clientId int, role Role) string {
+	return fmt.Sprintf("client:%v (%v)", clientId, role)
+}
+
+// ApiKeyActor names the integration behind a transition by the prefix of its
+// key, which is safe to show.
+func ApiKeyActor(prefix string) string {
+	return fmt.Sprintf("api-key:%v", prefix)
+}
+
+// OrderTransition records a status change of an order and who made it.
+type OrderTransition struct {
+	Id         int `gorm:"primarykey"`
+	OrderId    int `gorm:"index"`
+	FromStatus OrderStatus
+	ToStatus   OrderStatus
+	Actor      string
+	CreatedAt  time.Time
+}
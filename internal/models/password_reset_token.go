@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// PasswordResetToken lets a client choose a new password without knowing the
+// current one, which is how accounts created before passwords existed get
+// one. Staff issue it and hand it to the client; only its hash is stored and
+// it works once.
+type PasswordResetToken struct {
+	Id        int    `gorm:"primarykey"`
+	ClientId  int    `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (prt PasswordResetToken) IsActive(now time.Time) bool {
+	return prt.UsedAt == nil && now.Before(prt.ExpiresAt)
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type PaymentStatus string
+
+const (
+	PaymentStatusRequiresConfirmation PaymentStatus = "requires_confirmation"
+	PaymentStatusAuthorized           PaymentStatus = "authorized"
+	PaymentStatusCaptured             PaymentStatus = "captured"
+	PaymentStatusVoided               PaymentStatus = "voided"
+	PaymentStatusRefunded             PaymentStatus = "refunded"
+	PaymentStatusDeclined             PaymentStatus = "declined"
+	PaymentStatusFailed               PaymentStatus = "failed"
+)
+
+// Payment tracks the charge of an order at the payment provider. An order has
+// at most one payment; retrying a failed one overwrites it.
+type Payment struct {
+	Id              int `gorm:"primarykey"`
+	OrderId         int `gorm:"uniqueIndex"`
+	Provider        string
+	AuthorizationId string
+	Status          PaymentStatus
+	Amount          Money `gorm:"embedded;embeddedPrefix:amount_"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// IsOpen reports whether the provider still holds an authorization for the
+// payment that has to be either captured or voided.
+func (p Payment) IsOpen() bool {
+	return p.Status == PaymentStatusAuthorized || p.Status == PaymentStatusRequiresConfirmation
+}
@@ -1,11 +1,21 @@
 package models
 
+import "time"
+
 type Product struct {
 	Id          int `gorm:"primarykey"`
 	CategoryId  int `json:"categoryId"`
 	Category    Category
-	Label       string  `json:"label"`
-	Type        int     `json:"type"`
-	DownloadUrl string  `json:"downloadUrl"`
-	Weight      float64 `json:"weight"`
+	Label       string      `json:"label"`
+	Type        ProductType `json:"type"`
+	DownloadUrl string      `json:"downloadUrl"`
+	Weight      float64     `json:"weight"`
+	Price       Money       `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	ArchivedAt  *time.Time  `json:"archivedAt" gorm:"index"`
+}
+
+// IsArchived reports whether the product was removed from the catalog. Archived
+// products stay in the table so carts and orders referencing them still resolve.
+func (p Product) IsArchived() bool {
+	return p.ArchivedAt != nil
 }
@@ -1,9 +1,28 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	MaxProductQuantity = 99
+)
+
+var (
+	ErrCartQuantityLimit = errors.New("cart quantity limit reached")
+)
+
+// ProductCart is a line of a cart. A cart has at most one line per product.
 type ProductCart struct {
-	Id        int `gorm:"primarykey"`
-	ProductId int
+	Id        int     `gorm:"primarykey"`
+	ProductId int     `gorm:"uniqueIndex:idx_product_carts_cart_product,priority:2"`
 	Product   Product //TODO Check if this its neccessary
-	CartId    int
-	Cart      Cart //TODO Check if this its neccessary
+	CartId    int     `gorm:"uniqueIndex:idx_product_carts_cart_product,priority:1"`
+	Cart      Cart    //TODO Check if this its neccessary
+	Quantity  int     `gorm:"not null;default:1"`
+}
+
+func NewCartQuantityLimitError(productId int) error {
+	return fmt.Errorf("%w: the cart already holds %v units of the product: %v", ErrCartQuantityLimit, MaxProductQuantity, productId)
 }
@@ -0,0 +1,26 @@
+package models
+
+const (
+	ProductSortById        = "id"
+	ProductSortByLabel     = "label"
+	ProductSortByLabelDesc = "-label"
+	ProductSortByPrice     = "price"
+	ProductSortByPriceDesc = "-price"
+)
+
+type ProductFilter struct {
+	CategoryIds []int
+	Type        *ProductType
+	Label       string
+	Sort        string
+	Page        int
+	PageSize    int
+}
+
+func IsValidProductSort(sort string) bool {
+	switch sort {
+	case ProductSortById, ProductSortByLabel, ProductSortByLabelDesc, ProductSortByPrice, ProductSortByPriceDesc:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ProductType is stored as an integer but travels over the API as its name.
+type ProductType int
+
+const (
+	ProductTypePhysical ProductType = iota + 1
+	ProductTypeDigital
+	ProductTypeService
+	ProductTypeBundle
+)
+
+var productTypeNames = map[ProductType]string{
+	ProductTypePhysical: "physical",
+	ProductTypeDigital:  "digital",
+	ProductTypeService:  "service",
+	ProductTypeBundle:   "bundle",
+}
+
+func ParseProductType(name string) (ProductType, error) {
+	for productType, productTypeName := range productTypeNames {
+		if productTypeName == name {
+			return productType, nil
+		}
+	}
+	return 0, errors.New(fmt.Sprintf("unsupported product type: %v", name))
+}
+
+func (pt ProductType) IsValid() bool {
+	_, ok := productTypeNames[pt]
+	return ok
+}
+
+// IsShippable reports whether products of this type are sent as a parcel and
+// therefore need a weight to be quoted.
+func (pt ProductType) IsShippable() bool {
+	return pt == ProductTypePhysical || pt == ProductTypeBundle
+}
+
+// TracksStock reports whether products of this type are limited by the units
+// held in the warehouse; digital goods and services never run out.
+func (pt ProductType) TracksStock() bool {
+	return pt.IsShippable()
+}
+
+func (pt ProductType) String() string {
+	if name, ok := productTypeNames[pt]; ok {
+		return name
+	}
+	return fmt.Sprintf("ProductType(%d)", int(pt))
+}
+
+func (pt ProductType) MarshalJSON() ([]byte, error) {
+	if !pt.IsValid() {
+		return nil, errors.New(fmt.Sprintf("unsupported product type: %d", int(pt)))
+	}
+	return json.Marshal(pt.String())
+}
+
+func (pt *ProductType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return errors.New("product type must be a string")
+	}
+	productType, err := ParseProductType(name)
+	if err != nil {
+		return err
+	}
+	*pt = productType
+	return nil
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// RefreshToken lets a client get new access tokens without logging in again.
+// Only the hash of the token is stored, and each token is used once: a refresh
+// revokes it and hands out a new one.
+type RefreshToken struct {
+	Id        int    `gorm:"primarykey"`
+	ClientId  int    `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+func (rt RefreshToken) IsActive(now time.Time) bool {
+	return rt.RevokedAt == nil && now.Before(rt.ExpiresAt)
+}
@@ -0,0 +1,15 @@
+package request
+
+// AddressRequest creates or replaces an address. Omitted default flags keep
+// the ones the address has, so editing its fields never moves the defaults.
+type AddressRequest struct {
+	Recipient       string `json:"recipient"`
+	Line1           string `json:"line1"`
+	Line2           string `json:"line2"`
+	City            string `json:"city"`
+	Region          string `json:"region"`
+	PostalCode      string `json:"postalCode"`
+	Country         string `json:"country"`
+	DefaultShipping *bool  `json:"defaultShipping"`
+	DefaultBilling  *bool  `json:"defaultBilling"`
+}
@@ -0,0 +1,6 @@
+package request
+
+type ApiKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
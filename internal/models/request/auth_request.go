@@ -0,0 +1,15 @@
+package request
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type ResetPasswordRequest struct {
+	ResetToken string `json:"resetToken"`
+	Password   string `json:"password"`
+}
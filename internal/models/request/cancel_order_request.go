@@ -0,0 +1,5 @@
+package request
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
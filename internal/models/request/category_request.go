@@ -0,0 +1,6 @@
+package request
+
+type CategoryRequest struct {
+	Label    string `json:"label"`
+	ParentId *int   `json:"parentId"`
+}
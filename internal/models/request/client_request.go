@@ -0,0 +1,18 @@
+package request
+
+import "github.com/emiliocc5/online-store-api/internal/models"
+
+type ClientRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+type SignUpRequest struct {
+	ClientRequest
+	Password string `json:"password"`
+}
+
+type SetRoleRequest struct {
+	Role models.Role `json:"role"`
+}
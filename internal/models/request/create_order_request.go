@@ -0,0 +1,8 @@
+package request
+
+// CreateOrderRequest picks how physical products are shipped. Without
+// ShippingAddressId the default shipping address of the client is used.
+type CreateOrderRequest struct {
+	ShippingAddressId int    `json:"shippingAddressId"`
+	ShippingOption    string `json:"shippingOption"`
+}
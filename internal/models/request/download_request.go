@@ -0,0 +1,12 @@
+package request
+
+// RedeemDownloadRequest is a signed download link being followed, along with
+// who followed it for the audit log.
+type RedeemDownloadRequest struct {
+	OrderId   int
+	ProductId int
+	Expires   int64
+	Signature string
+	IpAddress string
+	UserAgent string
+}
@@ -0,0 +1,9 @@
+package request
+
+import "github.com/emiliocc5/online-store-api/internal/models"
+
+// OrderTransitionRequest carries only the new status; who moved the order is
+// taken from the credentials of the request.
+type OrderTransitionRequest struct {
+	Status models.OrderStatus `json:"status"`
+}
@@ -0,0 +1,26 @@
+package request
+
+import "github.com/emiliocc5/online-store-api/internal/models"
+
+type ProductRequest struct {
+	CategoryId  int                `json:"categoryId"`
+	Label       string             `json:"label"`
+	Type        models.ProductType `json:"type"`
+	DownloadUrl string             `json:"downloadUrl"`
+	Weight      float64            `json:"weight"`
+	Price       MoneyRequest       `json:"price"`
+}
+
+type PatchProductRequest struct {
+	CategoryId  *int                `json:"categoryId"`
+	Label       *string             `json:"label"`
+	Type        *models.ProductType `json:"type"`
+	DownloadUrl *string             `json:"downloadUrl"`
+	Weight      *float64            `json:"weight"`
+	Price       *MoneyRequest       `json:"price"`
+}
+
+type MoneyRequest struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
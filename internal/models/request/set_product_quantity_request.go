@@ -0,0 +1,5 @@
+package request
+
+type SetProductQuantityRequest struct {
+	Quantity int `json:"quantity"`
+}
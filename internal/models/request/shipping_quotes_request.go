@@ -0,0 +1,9 @@
+package request
+
+// ShippingQuotesRequest names the destination to quote, either directly by
+// zone or through an address of the client. Without any of them the default
+// shipping address is used.
+type ShippingQuotesRequest struct {
+	Zone      string `form:"zone"`
+	AddressId int    `form:"addressId"`
+}
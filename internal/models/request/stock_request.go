@@ -0,0 +1,5 @@
+package request
+
+type SetStockRequest struct {
+	OnHand *int `json:"onHand"`
+}
@@ -0,0 +1,18 @@
+package response
+
+type PostalAddressResponse struct {
+	Recipient  string `json:"recipient"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postalCode,omitempty"`
+	Country    string `json:"country"`
+}
+
+type AddressResponse struct {
+	Id int `json:"id"`
+	PostalAddressResponse
+	DefaultShipping bool `json:"defaultShipping"`
+	DefaultBilling  bool `json:"defaultBilling"`
+}
@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+type ApiKeyResponse struct {
+	Id         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+// CreatedApiKeyResponse is the only time the key itself is returned.
+type CreatedApiKeyResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
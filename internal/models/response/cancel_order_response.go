@@ -0,0 +1,8 @@
+package response
+
+type CancelOrderResponse struct {
+	OrderId       int    `json:"orderId"`
+	Status        string `json:"status"`
+	Reason        string `json:"reason"`
+	RefundPending bool   `json:"refundPending"`
+}
@@ -0,0 +1,8 @@
+package response
+
+type CategoryResponse struct {
+	Id       int                `json:"id"`
+	Label    string             `json:"label"`
+	ParentId *int               `json:"parentId,omitempty"`
+	Children []CategoryResponse `json:"children,omitempty"`
+}
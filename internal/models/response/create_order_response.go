@@ -0,0 +1,13 @@
+package response
+
+// CreateOrderResponse describes a placed order. When the payment could not be
+// collected the order stays pending, PaymentError says why and the client can
+// retry it through POST /api/orders/:orderId/payment.
+type CreateOrderResponse struct {
+	OrderId      int                    `json:"orderId"`
+	Status       string                 `json:"status"`
+	Total        MoneyResponse          `json:"total"`
+	Shipping     *OrderShippingResponse `json:"shipping,omitempty"`
+	Payment      *PaymentResponse       `json:"payment,omitempty"`
+	PaymentError string                 `json:"paymentError,omitempty"`
+}
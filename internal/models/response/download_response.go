@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+type DownloadLinkResponse struct {
+	OrderId            int       `json:"orderId"`
+	ProductId          int       `json:"productId"`
+	Url                string    `json:"url"`
+	ExpiresAt          time.Time `json:"expiresAt"`
+	RemainingDownloads int       `json:"remainingDownloads"`
+}
@@ -1,5 +1,6 @@
 package response
 
 type GetCartResponse struct {
-	Products []ProductResponse `json:"products"`
+	Products []ProductLineResponse `json:"products"`
+	Subtotal MoneyResponse         `json:"subtotal"`
 }
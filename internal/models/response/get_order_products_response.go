@@ -1,5 +1,10 @@
 package response
 
 type GetOrderProductsResponse struct {
-	Products []ProductResponse `json:"products"`
+	Status             string                 `json:"status"`
+	CancellationReason string                 `json:"cancellationReason,omitempty"`
+	RefundPending      bool                   `json:"refundPending,omitempty"`
+	Products           []ProductLineResponse  `json:"products"`
+	Shipping           *OrderShippingResponse `json:"shipping,omitempty"`
+	Total              MoneyResponse          `json:"total"`
 }
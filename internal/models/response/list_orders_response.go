@@ -0,0 +1,16 @@
+package response
+
+import "time"
+
+type ListOrdersResponse struct {
+	Orders     []OrderSummaryResponse `json:"orders"`
+	Pagination PaginationResponse     `json:"pagination"`
+}
+
+type OrderSummaryResponse struct {
+	Id        int           `json:"id"`
+	Status    string        `json:"status"`
+	CreatedAt time.Time     `json:"createdAt"`
+	ItemCount int           `json:"itemCount"`
+	Total     MoneyResponse `json:"total"`
+}
@@ -0,0 +1,6 @@
+package response
+
+type ListProductsResponse struct {
+	Products   []ProductResponse  `json:"products"`
+	Pagination PaginationResponse `json:"pagination"`
+}
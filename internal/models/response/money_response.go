@@ -0,0 +1,7 @@
+package response
+
+type MoneyResponse struct {
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	Formatted string `json:"formatted"`
+}
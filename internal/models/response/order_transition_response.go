@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+type OrderTransitionResponse struct {
+	OrderId   int       `json:"orderId"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Actor     string    `json:"actor"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,9 @@
+package response
+
+type PaymentResponse struct {
+	OrderId         int           `json:"orderId"`
+	OrderStatus     string        `json:"orderStatus"`
+	Status          string        `json:"status"`
+	AuthorizationId string        `json:"authorizationId,omitempty"`
+	Amount          MoneyResponse `json:"amount"`
+}
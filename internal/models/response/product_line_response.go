@@ -0,0 +1,7 @@
+package response
+
+type ProductLineResponse struct {
+	ProductResponse
+	Quantity int           `json:"quantity"`
+	Subtotal MoneyResponse `json:"subtotal"`
+}
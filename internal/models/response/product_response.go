@@ -1,10 +1,24 @@
 package response
 
+// ProductResponse carries the details that apply to every product plus a
+// block specific to its type: shippable products expose Shipping and, only
+// for admins, digital products expose Download. Services and other kinds
+// expose neither.
 type ProductResponse struct {
-	Id          int     `json:"id"`
-	Label       string  `json:"label"`
-	Category    string  `json:"category"`
-	Type        int     `json:"type"`
-	DownloadUrl string  `json:"downloadUrl,omitempty"`
-	Weight      float64 `json:"weight,omitempty"`
+	Id       int                      `json:"id"`
+	Label    string                   `json:"label"`
+	Category string                   `json:"category"`
+	Type     string                   `json:"type"`
+	Price    MoneyResponse            `json:"price"`
+	Shipping *ShippingDetailsResponse `json:"shipping,omitempty"`
+	Download *DownloadDetailsResponse `json:"download,omitempty"`
+	Archived bool                     `json:"archived,omitempty"`
+}
+
+type ShippingDetailsResponse struct {
+	Weight float64 `json:"weight"`
+}
+
+type DownloadDetailsResponse struct {
+	Url string `json:"url"`
 }
@@ -0,0 +1,22 @@
+package response
+
+type ShippingQuotesResponse struct {
+	ShippingRequired bool                    `json:"shippingRequired"`
+	Zone             string                  `json:"zone,omitempty"`
+	Weight           float64                 `json:"weight"`
+	Quotes           []ShippingQuoteResponse `json:"quotes"`
+}
+
+type ShippingQuoteResponse struct {
+	Option        string        `json:"option"`
+	Label         string        `json:"label"`
+	EstimatedDays int           `json:"estimatedDays"`
+	Cost          MoneyResponse `json:"cost"`
+}
+
+type OrderShippingResponse struct {
+	Option  string                `json:"option"`
+	Zone    string                `json:"zone"`
+	Cost    MoneyResponse         `json:"cost"`
+	Address PostalAddressResponse `json:"address"`
+}
@@ -0,0 +1,9 @@
+package response
+
+type StockResponse struct {
+	ProductId int  `json:"productId"`
+	Tracked   bool `json:"tracked"`
+	OnHand    int  `json:"onHand"`
+	Reserved  int  `json:"reserved"`
+	Available int  `json:"available"`
+}
@@ -0,0 +1,16 @@
+package response
+
+import "time"
+
+type TokenResponse struct {
+	AccessToken  string `json:"accessToken"`
+	TokenType    string `json:"tokenType"`
+	ExpiresIn    int    `json:"expiresIn"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+type PasswordResetResponse struct {
+	ClientId   int       `json:"clientId"`
+	ResetToken string    `json:"resetToken"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
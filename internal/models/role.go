@@ -0,0 +1,36 @@
+package models
+
+type Role string
+
+const (
+	RoleCustomer     Role = "customer"
+	RoleSupport      Role = "support"
+	RoleCatalogAdmin Role = "catalog-admin"
+	RoleSuperAdmin   Role = "super-admin"
+)
+
+var (
+	// roleScopes is the permission matrix. Every role can shop; support staff
+	// can look at any cart or order without changing them and catalog admins
+	// manage products, categories and stock.
+	roleScopes = map[Role][]Scope{
+		RoleCustomer:     {ScopeShopping},
+		RoleSupport:      {ScopeShopping, ScopeCartsRead, ScopeOrdersRead},
+		RoleCatalogAdmin: {ScopeShopping, ScopeCatalogWrite, ScopeInventoryRead, ScopeInventoryWrite},
+		RoleSuperAdmin:   scopes,
+	}
+)
+
+func (r Role) IsValid() bool {
+	_, ok := roleScopes[r]
+	return ok
+}
+
+func (r Role) Grants(scope Scope) bool {
+	for _, e := range roleScopes[r] {
+		if e == scope {
+			return true
+		}
+	}
+	return false
+}
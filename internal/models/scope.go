@@ -0,0 +1,52 @@
+package models
+
+// Scope is a permission. API keys are granted scopes directly and users get
+// them through their role.
+type Scope string
+
+const (
+	// ScopeShopping covers the own cart, orders, addresses and profile of the
+	// signed-in client.
+	ScopeShopping       Scope = "shopping"
+	ScopeCartsRead      Scope = "carts:read"
+	ScopeCatalogWrite   Scope = "catalog:write"
+	ScopeInventoryRead  Scope = "inventory:read"
+	ScopeInventoryWrite Scope = "inventory:write"
+	ScopeOrdersRead     Scope = "orders:read"
+	ScopeOrdersWrite    Scope = "orders:write"
+	ScopeKeysManage     Scope = "keys:manage"
+	ScopeRolesManage    Scope = "roles:manage"
+	// ScopeClientsManage covers helping clients back into their accounts,
+	// such as issuing password reset tokens.
+	ScopeClientsManage Scope = "clients:manage"
+)
+
+var (
+	scopes = []Scope{ScopeShopping, ScopeCartsRead, ScopeCatalogWrite, ScopeInventoryRead, ScopeInventoryWrite,
+		ScopeOrdersRead, ScopeOrdersWrite, ScopeKeysManage, ScopeRolesManage, ScopeClientsManage}
+)
+
+// ApiKeyScopes lists the scopes an API key can hold. Shopping is left out as
+// it acts on behalf of a signed-in client.
+func ApiKeyScopes() []Scope {
+	var list []Scope
+	for _, e := range scopes {
+		if e.IsGrantableToApiKey() {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+func (s Scope) IsValid() bool {
+	for _, e := range scopes {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Scope) IsGrantableToApiKey() bool {
+	return s.IsValid() && s != ScopeShopping
+}
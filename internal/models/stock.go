@@ -0,0 +1,31 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// Stock is the inventory ledger of a product. OnHand counts the units in the
+// warehouse and Reserved the ones promised to placed orders that have not been
+// shipped yet, so only the difference can still be sold.
+//
+// Products get an empty ledger when they are created. Products created before
+// the ledger existed have none, and their stock is not tracked until an admin
+// sets it, so they keep selling after an upgrade.
+type Stock struct {
+	ProductId int `gorm:"primarykey;autoIncrement:false"`
+	OnHand    int `gorm:"not null;default:0"`
+	Reserved  int `gorm:"not null;default:0"`
+}
+
+func (s Stock) Available() int {
+	return s.OnHand - s.Reserved
+}
+
+func NewInsufficientStockError(productId, available int) error {
+	return fmt.Errorf("%w: the product: %v has %v units available", ErrInsufficientStock, productId, available)
+}
@@ -0,0 +1,151 @@
+package payment
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"sync"
+)
+
+const (
+	FakeBehaviorApprove             = "approve"
+	FakeBehaviorDecline             = "decline"
+	FakeBehaviorTimeout             = "timeout"
+	FakeBehaviorRequireConfirmation = "require_confirmation"
+
+	fakeStateCaptured = "captured"
+	fakeStateVoided   = "voided"
+)
+
+// FakeProvider is an in-memory PaymentProvider for local development and tests.
+// It never reaches the network and behaves the same way on every run: the
+// behavior it was created with decides the outcome of every authorization and
+// authorization ids are sequential.
+type FakeProvider struct {
+	behavior       string
+	mutex          sync.Mutex
+	sequence       int
+	authorizations map[string]*fakeAuthorization
+}
+
+type fakeAuthorization struct {
+	amount   models.Money
+	refunded models.Money
+	state    string
+}
+
+func NewFakeProvider(behavior string) (*FakeProvider, error) {
+	switch behavior {
+	case FakeBehaviorApprove, FakeBehaviorDecline, FakeBehaviorTimeout, FakeBehaviorRequireConfirmation:
+	default:
+		return nil, errors.New(fmt.Sprintf("unsupported fake payment behavior: %v", behavior))
+	}
+	return &FakeProvider{behavior: behavior, authorizations: make(map[string]*fakeAuthorization)}, nil
+}
+
+func (fp *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (fp *FakeProvider) Authorize(req AuthorizationRequest) (Authorization, error) {
+	switch fp.behavior {
+	case FakeBehaviorDecline:
+		return Authorization{}, fmt.Errorf("%w: the card was declined for order: %v", ErrPaymentDeclined, req.OrderId)
+	case FakeBehaviorTimeout:
+		return Authorization{}, fmt.Errorf("%w: no answer while authorizing order: %v", ErrPaymentTimeout, req.OrderId)
+	}
+
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+
+	fp.sequence++
+	authorization := &fakeAuthorization{amount: req.Amount, state: AuthorizationStatusAuthorized}
+	if fp.behavior == FakeBehaviorRequireConfirmation {
+		authorization.state = AuthorizationStatusRequiresConfirmation
+	}
+	id := fmt.Sprintf("fake_auth_%06d", fp.sequence)
+	fp.authorizations[id] = authorization
+
+	return Authorization{Id: id, Status: authorization.state, Amount: req.Amount}, nil
+}
+
+func (fp *FakeProvider) Confirm(authorizationId string) (Authorization, error) {
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+
+	authorization, err := fp.findAuthorization(authorizationId, AuthorizationStatusRequiresConfirmation)
+	if err != nil {
+		return Authorization{}, err
+	}
+	authorization.state = AuthorizationStatusAuthorized
+	return Authorization{Id: authorizationId, Status: AuthorizationStatusAuthorized, Amount: authorization.amount}, nil
+}
+
+func (fp *FakeProvider) Capture(authorizationId string) error {
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+
+	authorization, err := fp.findAuthorization(authorizationId, AuthorizationStatusAuthorized)
+	if err != nil {
+		return err
+	}
+	authorization.state = fakeStateCaptured
+	return nil
+}
+
+func (fp *FakeProvider) Void(authorizationId string) error {
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+
+	authorization, err := fp.findAuthorization(authorizationId, AuthorizationStatusAuthorized, AuthorizationStatusRequiresConfirmation)
+	if err != nil {
+		return err
+	}
+	authorization.state = fakeStateVoided
+	return nil
+}
+
+func (fp *FakeProvider) Refund(authorizationId string, amount models.Money) error {
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+
+	authorization, err := fp.findAuthorization(authorizationId, fakeStateCaptured)
+	if err != nil {
+		return err
+	}
+	refunded, err := authorization.refunded.Add(amount)
+	if err != nil {
+		return err
+	}
+	if refunded.Amount > authorization.amount.Amount {
+		return fmt.Errorf("%w: cannot refund more than the captured %v", ErrInvalidAuthorizationOp, authorization.amount)
+	}
+	authorization.refunded = refunded
+	return nil
+}
+
+// Refunded tells how much of an authorization was given back, for tests to
+// check what the provider was asked to refund.
+func (fp *FakeProvider) Refunded(authorizationId string) models.Money {
+	fp.mutex.Lock()
+	defer fp.mutex.Unlock()
+
+	authorization, ok := fp.authorizations[authorizationId]
+	if !ok {
+		return models.Money{}
+	}
+	return authorization.refunded
+}
+
+func (fp *FakeProvider) findAuthorization(authorizationId string, allowedStates ...string) (*fakeAuthorization, error) {
+	authorization, ok := fp.authorizations[authorizationId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownAuthorization, authorizationId)
+	}
+	for _, state := range allowedStates {
+		if authorization.state == state {
+			return authorization, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v is %v", ErrInvalidAuthorizationOp, authorizationId, authorization.state)
+}
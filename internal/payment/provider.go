@@ -0,0 +1,44 @@
+package payment
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+)
+
+const (
+	AuthorizationStatusAuthorized           = "authorized"
+	AuthorizationStatusRequiresConfirmation = "requires_confirmation"
+)
+
+var (
+	ErrPaymentDeclined        = errors.New("payment declined")
+	ErrPaymentTimeout         = errors.New("payment provider timed out")
+	ErrUnknownAuthorization   = errors.New("unknown authorization")
+	ErrInvalidAuthorizationOp = errors.New("operation not allowed for the authorization")
+)
+
+type (
+	// PaymentProvider is the gateway the order flow charges through. Amounts are
+	// first authorized (held on the customer's method) and then captured; an
+	// authorization that is never captured must be voided, and captured amounts
+	// can only be given back through a refund.
+	PaymentProvider interface {
+		Name() string
+		Authorize(req AuthorizationRequest) (Authorization, error)
+		// Confirm completes an authorization that required the customer to
+		// approve it, like a 3-D Secure challenge.
+		Confirm(authorizationId string) (Authorization, error)
+		Capture(authorizationId string) error
+		Void(authorizationId string) error
+		Refund(authorizationId string, amount models.Money) error
+	}
+	AuthorizationRequest struct {
+		OrderId int
+		Amount  models.Money
+	}
+	Authorization struct {
+		Id     string
+		Status string
+		Amount models.Money
+	}
+)
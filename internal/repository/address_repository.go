@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type (
+	AddressRepository interface {
+		ListAddresses(clientId int) (*[]models.Address, error)
+		FindAddress(clientId, addressId int) (*models.Address, error)
+		// FindDefaultShippingAddress returns nil without error when the client
+		// has not chosen one.
+		FindDefaultShippingAddress(clientId int) (*models.Address, error)
+		SaveAddress(address *models.Address) error
+		DeleteAddress(address *models.Address) error
+	}
+	PgAddressRepository struct {
+		DbClient IAddressRepositoryDbClient
+	}
+	IAddressRepositoryDbClient interface {
+		Find(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		First(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Delete(value interface{}, conds ...interface{}) (tx *gorm.DB)
+		Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) (err error)
+	}
+)
+
+func (ar *PgAddressRepository) ListAddresses(clientId int) (*[]models.Address, error) {
+	var addresses []models.Address
+	addressesResult := ar.DbClient.Find(&addresses, "client_id = ?", clientId)
+	if addressesResult.Error != nil {
+		logger.Errorf("unable to list addresses of client: %v, with error: %v", clientId, addressesResult.Error)
+		return nil, errors.New("unable to retrieve the list of addresses")
+	}
+	return &addresses, nil
+}
+
+func (ar *PgAddressRepository) FindAddress(clientId, addressId int) (*models.Address, error) {
+	address := models.Address{}
+	addressResult := ar.DbClient.First(&address, "id = ? AND client_id = ?", addressId, clientId)
+	if addressResult.Error != nil {
+		return nil, errors.New(fmt.Sprintf("address with id: %v not found", addressId))
+	}
+	return &address, nil
+}
+
+func (ar *PgAddressRepository) FindDefaultShippingAddress(clientId int) (*models.Address, error) {
+	address := models.Address{}
+	addressResult := ar.DbClient.Find(&address, "client_id = ? AND default_shipping = ?", clientId, true)
+	if addressResult.Error != nil {
+		logger.Errorf("unable to get default shipping address of client: %v, with error: %v", clientId, addressResult.Error)
+		return nil, errors.New("unable to retrieve the default shipping address")
+	}
+	if addressResult.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &address, nil
+}
+
+// SaveAddress creates or updates the address. Flagging it as a default takes
+// the flag away from the other addresses of the client in the same
+// transaction.
+func (ar *PgAddressRepository) SaveAddress(address *models.Address) error {
+	return ar.DbClient.Transaction(func(tx *gorm.DB) error {
+		if address.DefaultShipping {
+			clearResult := tx.Model(&models.Address{}).
+				Where("client_id = ? AND id <> ?", address.ClientId, address.Id).
+				Update("default_shipping", false)
+			if clearResult.Error != nil {
+				logger.Errorf("unable to clear default shipping address of client: %v, with error: %v", address.ClientId, clearResult.Error)
+				return errors.New("unable to save the address")
+			}
+		}
+		if address.DefaultBilling {
+			clearResult := tx.Model(&models.Address{}).
+				Where("client_id = ? AND id <> ?", address.ClientId, address.Id).
+				Update("default_billing", false)
+			if clearResult.Error != nil {
+				logger.Errorf("unable to clear default billing address of client: %v, with error: %v", address.ClientId, clearResult.Error)
+				return errors.New("unable to save the address")
+			}
+		}
+
+		saveResult := tx.Save(address)
+		if saveResult.Error != nil {
+			logger.Errorf("unable to save address of client: %v, with error: %v", address.ClientId, saveResult.Error)
+			return errors.New("unable to save the address")
+		}
+		return nil
+	})
+}
+
+// DeleteAddress removes the address. When it was a default, the oldest of the
+// remaining addresses of the client takes the flag over in the same
+// transaction, so a client with addresses always has defaults to order with.
+func (ar *PgAddressRepository) DeleteAddress(address *models.Address) error {
+	return ar.DbClient.Transaction(func(tx *gorm.DB) error {
+		deleteResult := tx.Delete(address)
+		if deleteResult.Error != nil {
+			logger.Errorf("unable to delete address: %v, with error: %v", address.Id, deleteResult.Error)
+			return errors.New(fmt.Sprintf("unable to delete the address: %v", address.Id))
+		}
+		if !address.DefaultShipping && !address.DefaultBilling {
+			return nil
+		}
+
+		var successors []models.Address
+		successorResult := tx.Where("client_id = ?", address.ClientId).Order("id").Limit(1).Find(&successors)
+		if successorResult.Error != nil {
+			logger.Errorf("unable to find the successor of address: %v, with error: %v", address.Id, successorResult.Error)
+			return errors.New(fmt.Sprintf("unable to delete the address: %v", address.Id))
+		}
+		if len(successors) == 0 {
+			return nil
+		}
+
+		promoted := map[string]interface{}{}
+		if address.DefaultShipping {
+			promoted["default_shipping"] = true
+		}
+		if address.DefaultBilling {
+			promoted["default_billing"] = true
+		}
+		promoteResult := tx.Model(&models.Address{}).Where("id = ?", successors[0].Id).Updates(promoted)
+		if promoteResult.Error != nil {
+			logger.Errorf("unable to promote address: %v, with error: %v", successors[0].Id, promoteResult.Error)
+			return errors.New(fmt.Sprintf("unable to delete the address: %v", address.Id))
+		}
+		return nil
+	})
+}
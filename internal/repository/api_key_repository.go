@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+type (
+	ApiKeyRepository interface {
+		CreateApiKey(apiKey *models.ApiKey) error
+		FindApiKeyByPrefix(prefix string) (*models.ApiKey, error)
+		ListApiKeys() (*[]models.ApiKey, error)
+		RevokeApiKey(apiKeyId int) error
+		TouchApiKey(apiKeyId int, usedAt time.Time) error
+	}
+	PgApiKeyRepository struct {
+		DbClient IApiKeyRepositoryDbClient
+	}
+	IApiKeyRepositoryDbClient interface {
+		First(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Find(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Create(value interface{}) (tx *gorm.DB)
+		Model(value interface{}) (tx *gorm.DB)
+	}
+)
+
+func (ar *PgApiKeyRepository) CreateApiKey(apiKey *models.ApiKey) error {
+	createResult := ar.DbClient.Create(apiKey)
+	if createResult.Error != nil {
+		logger.Errorf("unable to create api key: %v, with error: %v", apiKey.Name, createResult.Error)
+		return errors.New("unable to create the api key")
+	}
+	return nil
+}
+
+func (ar *PgApiKeyRepository) FindApiKeyByPrefix(prefix string) (*models.ApiKey, error) {
+	apiKey := models.ApiKey{}
+	apiKeyResult := ar.DbClient.First(&apiKey, "prefix = ?", prefix)
+	if apiKeyResult.Error != nil {
+		return nil, auth.ErrInvalidApiKey
+	}
+	return &apiKey, nil
+}
+
+func (ar *PgApiKeyRepository) ListApiKeys() (*[]models.ApiKey, error) {
+	var apiKeys []models.ApiKey
+	apiKeysResult := ar.DbClient.Find(&apiKeys)
+	if apiKeysResult.Error != nil {
+		logger.Errorf("unable to list api keys with error: %v", apiKeysResult.Error)
+		return nil, errors.New("unable to retrieve the list of api keys")
+	}
+	return &apiKeys, nil
+}
+
+func (ar *PgApiKeyRepository) RevokeApiKey(apiKeyId int) error {
+	revokeResult := ar.DbClient.Model(&models.ApiKey{}).
+		Where("id = ? AND revoked_at IS NULL", apiKeyId).
+		Update("revoked_at", time.Now())
+	if revokeResult.Error != nil {
+		logger.Errorf("unable to revoke api key: %v, with error: %v", apiKeyId, revokeResult.Error)
+		return errors.New("unable to revoke the api key")
+	}
+	if revokeResult.RowsAffected == 0 {
+		return errors.New(fmt.Sprintf("active api key with id: %v not found", apiKeyId))
+	}
+	return nil
+}
+
+func (ar *PgApiKeyRepository) TouchApiKey(apiKeyId int, usedAt time.Time) error {
+	touchResult := ar.DbClient.Model(&models.ApiKey{}).Where("id = ?", apiKeyId).Update("last_used_at", usedAt)
+	if touchResult.Error != nil {
+		logger.Errorf("unable to record use of api key: %v, with error: %v", apiKeyId, touchResult.Error)
+		return errors.New("unable to update the api key")
+	}
+	return nil
+}
@@ -16,15 +16,23 @@ var (
 type (
 	CartRepository interface {
 		GetCartByClient(clientId int) (*models.Cart, error)
+		GetCartItems(cartId int) (*[]models.ProductCart, error)
 		AddProductToCart(productId, clientId int) error
+		SetProductQuantity(productId, clientId, quantity int) error
+		RemoveProductFromCart(productId, clientId int) error
+		ClearCart(clientId int) error
 	}
 	PgCartRepository struct {
 		DbClient ICartRepositoryDbClient
 	}
 	ICartRepositoryDbClient interface {
 		First(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Find(dest interface{}, conds ...interface{}) (tx *gorm.DB)
 		Create(value interface{}) (tx *gorm.DB)
+		Save(value interface{}) (tx *gorm.DB)
+		Exec(sql string, values ...interface{}) (tx *gorm.DB)
 		FirstOrCreate(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Delete(value interface{}, conds ...interface{}) (tx *gorm.DB)
 	}
 )
 
@@ -43,6 +51,28 @@ func (cr *PgCartRepository) GetCartByClient(clientId int) (*models.Cart, error)
 	return &clientCart, nil
 }
 
+func (cr *PgCartRepository) GetCartItems(cartId int) (*[]models.ProductCart, error) {
+	var productsCarts []models.ProductCart
+	productsResult := cr.DbClient.Find(&productsCarts, "cart_id = ?", cartId)
+	if productsResult.Error != nil {
+		return nil, errors.New("unable to retrieve the list of products")
+	}
+
+	var cartItems []models.ProductCart
+	for _, e := range productsCarts {
+		product := models.Product{}
+		productResult := cr.DbClient.Find(&product, "id = ?", e.ProductId)
+		if productResult.Error != nil {
+			logger.Error(fmt.Sprintf("unable to get the product: %v", productResult.Error.Error()))
+			continue
+		}
+		e.Product = product
+		cartItems = append(cartItems, e)
+	}
+
+	return &cartItems, nil
+}
+
 func (cr *PgCartRepository) AddProductToCart(productId, clientId int) error {
 	clientCart := models.Cart{ClientId: clientId}
 	err := cr.findOrCreateCartForClientId(&clientCart, clientId)
@@ -50,12 +80,30 @@ func (cr *PgCartRepository) AddProductToCart(productId, clientId int) error {
 		return err
 	}
 
-	productCart := models.ProductCart{
+	productCart := models.ProductCart{}
+	found, err := cr.findProductCart(&productCart, clientCart.Id, productId)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		return cr.incrementProductCart(productCart)
+	}
+
+	productCart = models.ProductCart{
 		ProductId: productId,
 		CartId:    clientCart.Id,
+		Quantity:  1,
 	}
 	productCartResult := cr.DbClient.Create(&productCart)
 	if productCartResult.Error != nil {
+		// A concurrent add may have created the line first, in which case the
+		// unique index rejects this one and the unit goes to that line.
+		existingProductCart := models.ProductCart{}
+		found, err := cr.findProductCart(&existingProductCart, clientCart.Id, productId)
+		if err == nil && found {
+			return cr.incrementProductCart(existingProductCart)
+		}
 		logger.Errorf("Error: %v", productCartResult.Error.Error())
 		return errors.New("unable to add product to the cart")
 	}
@@ -65,6 +113,92 @@ func (cr *PgCartRepository) AddProductToCart(productId, clientId int) error {
 	return nil
 }
 
+// incrementProductCart adds a unit to the line unless it already holds the
+// most a cart may have. The check is part of the update so concurrent adds
+// cannot go over it.
+func (cr *PgCartRepository) incrementProductCart(productCart models.ProductCart) error {
+	incrementResult := cr.DbClient.Exec("UPDATE product_carts SET quantity = quantity + 1 WHERE id = ? AND quantity < ?",
+		productCart.Id, models.MaxProductQuantity)
+	if incrementResult.Error != nil {
+		logger.Errorf("Error: %v", incrementResult.Error.Error())
+		return errors.New("unable to add product to the cart")
+	}
+	if incrementResult.RowsAffected == 0 {
+		return models.NewCartQuantityLimitError(productCart.ProductId)
+	}
+	logger.Info("Product quantity increased in the cart")
+	return nil
+}
+
+func (cr *PgCartRepository) SetProductQuantity(productId, clientId, quantity int) error {
+	clientCart := models.Cart{ClientId: clientId}
+	err := cr.findOrCreateCartForClientId(&clientCart, clientId)
+	if err != nil {
+		return err
+	}
+
+	productCart := models.ProductCart{}
+	found, err := cr.findProductCart(&productCart, clientCart.Id, productId)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		productCart = models.ProductCart{
+			ProductId: productId,
+			CartId:    clientCart.Id,
+		}
+	}
+	productCart.Quantity = quantity
+
+	saveResult := cr.DbClient.Save(&productCart)
+	if saveResult.Error != nil {
+		logger.Errorf("Error: %v", saveResult.Error.Error())
+		return errors.New("unable to update the product quantity in the cart")
+	}
+
+	return nil
+}
+
+func (cr *PgCartRepository) RemoveProductFromCart(productId, clientId int) error {
+	clientCart := models.Cart{ClientId: clientId}
+	err := cr.findCartForClientId(&clientCart, clientId)
+	if err != nil {
+		return err
+	}
+
+	deleteResult := cr.DbClient.Delete(&models.ProductCart{}, "cart_id = ? AND product_id = ?", clientCart.Id, productId)
+	if deleteResult.Error != nil {
+		logger.Errorf("Error: %v", deleteResult.Error.Error())
+		return errors.New("unable to remove product from the cart")
+	}
+	if deleteResult.RowsAffected == 0 {
+		return errors.New(fmt.Sprintf("product with id: %v is not in the cart", productId))
+	}
+
+	logger.Info("Product removed from the cart")
+
+	return nil
+}
+
+func (cr *PgCartRepository) ClearCart(clientId int) error {
+	clientCart := models.Cart{ClientId: clientId}
+	err := cr.findCartForClientId(&clientCart, clientId)
+	if err != nil {
+		return err
+	}
+
+	deleteResult := cr.DbClient.Delete(&models.ProductCart{}, "cart_id = ?", clientCart.Id)
+	if deleteResult.Error != nil {
+		logger.Errorf("Error: %v", deleteResult.Error.Error())
+		return errors.New("unable to clear the cart")
+	}
+
+	logger.Info("Cart cleared")
+
+	return nil
+}
+
 func (cr *PgCartRepository) findCartForClientId(clientCart *models.Cart, clientId int) error {
 	clientResult := cr.DbClient.First(clientCart, "client_id = ?", clientId)
 	if clientResult.Error != nil {
@@ -81,3 +215,12 @@ func (cr *PgCartRepository) findOrCreateCartForClientId(clientCart *models.Cart,
 	}
 	return nil
 }
+
+func (cr *PgCartRepository) findProductCart(productCart *models.ProductCart, cartId, productId int) (bool, error) {
+	productCartResult := cr.DbClient.Find(productCart, "cart_id = ? AND product_id = ?", cartId, productId)
+	if productCartResult.Error != nil {
+		logger.Errorf("Error: %v", productCartResult.Error.Error())
+		return false, errors.New("unable to retrieve the product from the cart")
+	}
+	return productCartResult.RowsAffected > 0, nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type (
+	CategoryRepository interface {
+		CreateCategory(category *models.Category) error
+		FindCategoryById(categoryId int) (*models.Category, error)
+		ListCategories() (*[]models.Category, error)
+		UpdateCategory(category *models.Category) error
+		DeleteCategory(categoryId int) error
+	}
+	PgCategoryRepository struct {
+		DbClient ICategoryRepositoryDbClient
+	}
+	ICategoryRepositoryDbClient interface {
+		First(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Find(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Create(value interface{}) (tx *gorm.DB)
+		Save(value interface{}) (tx *gorm.DB)
+		Delete(value interface{}, conds ...interface{}) (tx *gorm.DB)
+		Model(value interface{}) (tx *gorm.DB)
+	}
+)
+
+func (cr *PgCategoryRepository) CreateCategory(category *models.Category) error {
+	createResult := cr.DbClient.Create(category)
+	if createResult.Error != nil {
+		logger.Errorf("unable to create category with error: %v", createResult.Error)
+		return errors.New("unable to create the category")
+	}
+	return nil
+}
+
+func (cr *PgCategoryRepository) FindCategoryById(categoryId int) (*models.Category, error) {
+	category := models.Category{}
+	categoryResult := cr.DbClient.First(&category, "id = ?", categoryId)
+	if categoryResult.Error != nil {
+		return nil, errors.New(fmt.Sprintf("category with id: %v not found", categoryId))
+	}
+	return &category, nil
+}
+
+func (cr *PgCategoryRepository) ListCategories() (*[]models.Category, error) {
+	var categories []models.Category
+	categoriesResult := cr.DbClient.Find(&categories)
+	if categoriesResult.Error != nil {
+		logger.Errorf("unable to list categories with error: %v", categoriesResult.Error)
+		return nil, errors.New("unable to retrieve the list of categories")
+	}
+	return &categories, nil
+}
+
+func (cr *PgCategoryRepository) UpdateCategory(category *models.Category) error {
+	saveResult := cr.DbClient.Save(category)
+	if saveResult.Error != nil {
+		logger.Errorf("unable to update category: %v, with error: %v", category.Id, saveResult.Error)
+		return errors.New("unable to update the category")
+	}
+	return nil
+}
+
+func (cr *PgCategoryRepository) DeleteCategory(categoryId int) error {
+	var children int64
+	childrenResult := cr.DbClient.Model(&models.Category{}).Where("parent_id = ?", categoryId).Count(&children)
+	if childrenResult.Error != nil {
+		return errors.New("unable to delete the category")
+	}
+	if children > 0 {
+		return errors.New(fmt.Sprintf("category with id: %v has subcategories", categoryId))
+	}
+
+	var products int64
+	productsResult := cr.DbClient.Model(&models.Product{}).Where("category_id = ?", categoryId).Count(&products)
+	if productsResult.Error != nil {
+		return errors.New("unable to delete the category")
+	}
+	if products > 0 {
+		return errors.New(fmt.Sprintf("category with id: %v still has products", categoryId))
+	}
+
+	deleteResult := cr.DbClient.Delete(&models.Category{}, "id = ?", categoryId)
+	if deleteResult.Error != nil {
+		logger.Errorf("unable to delete category: %v, with error: %v", categoryId, deleteResult.Error)
+		return errors.New("unable to delete the category")
+	}
+	if deleteResult.RowsAffected == 0 {
+		return errors.New(fmt.Sprintf("category with id: %v not found", categoryId))
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/emiliocc5/online-store-api/internal/models"
 	"gorm.io/gorm"
 )
@@ -8,17 +11,104 @@ import (
 type (
 	ClientRepository interface {
 		IsClientInDataBase(clientId int) bool
+		FindClientById(clientId int) (*models.Client, error)
+		// FindClientByEmail returns nil without error when no account uses the
+		// email.
+		FindClientByEmail(email string) (*models.Client, error)
+		CreateClient(client *models.Client) error
+		UpdateClient(client *models.Client) error
+		DeleteClient(clientId int) error
 	}
 	PgClientRepository struct {
 		DbClient IClientRepositoryDbClient
 	}
 	IClientRepositoryDbClient interface {
 		Find(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		First(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Create(value interface{}) (tx *gorm.DB)
+		Save(value interface{}) (tx *gorm.DB)
+		Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) (err error)
 	}
 )
 
 func (cr *PgClientRepository) IsClientInDataBase(clientId int) bool {
 	client := models.Client{}
 	findClientResult := cr.DbClient.Find(&client, "id = ?", clientId)
-	return findClientResult.Error == nil
+	return findClientResult.Error == nil && findClientResult.RowsAffected > 0
+}
+
+func (cr *PgClientRepository) FindClientById(clientId int) (*models.Client, error) {
+	client := models.Client{}
+	clientResult := cr.DbClient.First(&client, "id = ?", clientId)
+	if clientResult.Error != nil {
+		return nil, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+	return &client, nil
+}
+
+func (cr *PgClientRepository) FindClientByEmail(email string) (*models.Client, error) {
+	client := models.Client{}
+	clientResult := cr.DbClient.Find(&client, "email = ?", email)
+	if clientResult.Error != nil {
+		logger.Errorf("unable to find client by email, with error: %v", clientResult.Error)
+		return nil, errors.New("unable to retrieve the client")
+	}
+	if clientResult.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &client, nil
+}
+
+func (cr *PgClientRepository) CreateClient(client *models.Client) error {
+	createResult := cr.DbClient.Create(client)
+	if createResult.Error != nil {
+		logger.Errorf("unable to create client with error: %v", createResult.Error)
+		if cr.isEmailTaken(client.Id, client.Email) {
+			return models.NewEmailTakenError(client.Email)
+		}
+		return errors.New("unable to create the client")
+	}
+	return nil
+}
+
+func (cr *PgClientRepository) UpdateClient(client *models.Client) error {
+	saveResult := cr.DbClient.Save(client)
+	if saveResult.Error != nil {
+		logger.Errorf("unable to update client: %v, with error: %v", client.Id, saveResult.Error)
+		if cr.isEmailTaken(client.Id, client.Email) {
+			return models.NewEmailTakenError(client.Email)
+		}
+		return errors.New("unable to update the client")
+	}
+	return nil
+}
+
+// isEmailTaken tells whether a write failed on the unique email index: a
+// signup racing with another for the same email passes the check of the
+// service, so the index is the one to reject it.
+func (cr *PgClientRepository) isEmailTaken(clientId int, email string) bool {
+	owner, err := cr.FindClientByEmail(email)
+	return err == nil && owner != nil && owner.Id != clientId
+}
+
+// DeleteClient closes the account and drops its address book. Orders are kept
+// for the records of the store.
+func (cr *PgClientRepository) DeleteClient(clientId int) error {
+	return cr.DbClient.Transaction(func(tx *gorm.DB) error {
+		addressesResult := tx.Delete(&models.Address{}, "client_id = ?", clientId)
+		if addressesResult.Error != nil {
+			logger.Errorf("unable to delete addresses of client: %v, with error: %v", clientId, addressesResult.Error)
+			return errors.New(fmt.Sprintf("unable to delete the client: %v", clientId))
+		}
+
+		deleteResult := tx.Delete(&models.Client{}, "id = ?", clientId)
+		if deleteResult.Error != nil {
+			logger.Errorf("unable to delete client: %v, with error: %v", clientId, deleteResult.Error)
+			return errors.New(fmt.Sprintf("unable to delete the client: %v", clientId))
+		}
+		if deleteResult.RowsAffected == 0 {
+			return errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+		}
+		return nil
+	})
 }
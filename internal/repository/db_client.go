@@ -1,53 +1,58 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/config"
 	"github.com/emiliocc5/online-store-api/internal/models"
 	"github.com/emiliocc5/online-store-api/internal/utils"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
-	"os"
-	"sync"
 )
 
-var (
-	once          sync.Once
-	dbInstance    *gorm.DB
-	errorInstance error
-
-	DbHost     = os.Getenv("DB_HOST")
-	DbUser     = os.Getenv("DB_USER")
-	DbPassword = os.Getenv("DB_PASSWORD")
-	DbName     = os.Getenv("DB_NAME")
-	DbPort     = os.Getenv("DB_PORT")
+const (
+	cartLineIndex = "idx_product_carts_cart_product"
 )
 
 func init() {
 	logger = utils.GetLogger()
 }
 
-func GetClient() (*gorm.DB, error) {
-	return getClientInstance()
-}
-
-func getClientInstance() (*gorm.DB, error) {
-	once.Do(func() {
-		dbInstance, errorInstance = connectDatabase()
-	})
-	return dbInstance, errorInstance
+// Connect opens the database described by the configuration and, when
+// migrate is set, brings its tables up to date.
+func Connect(cfg config.DatabaseConfig, migrate bool) (*gorm.DB, error) {
+	switch cfg.Driver {
+	case config.DriverPostgres:
+		logger.Infof("Opening connections to %v:%v/%v", cfg.Host, cfg.Port, cfg.Name)
+		return connectDatabase(postgres.Open(cfg.DSN()), 0, migrate)
+	case config.DriverSqlite:
+		// SQLite has no row locks, so a single connection serializes the
+		// transactions the way the locks do on Postgres.
+		logger.Infof("Opening the database file %v", cfg.Path)
+		return connectDatabase(sqlite.Open(cfg.DSN()), 1, migrate)
+	}
+	return nil, errors.New(fmt.Sprintf("unsupported database driver: %v", cfg.Driver))
 }
 
-//TODO get variables from env
-func connectDatabase() (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=localhost port=5432 user=goApiUser password=1234 dbname=OnlineStore sslmode=disable")
-	//dsn := fmt.Sprintf("host=%+v user=%+v password=%+v dbname=%+v port=%+v sslmode=disable", DbHost, DbUser, DbPassword, DbName, DbPort)
-
-	logger.Info("Opening connections")
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// connectDatabase opens the database of any dialect, limiting the open
+// connections when maxOpenConns is positive.
+func connectDatabase(dialector gorm.Dialector, maxOpenConns int, migrate bool) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{})
 	if err != nil {
-		logger.Error("error message: %s", err)
+		logger.Errorf("error message: %s", err)
 		return nil, err
 	}
+	if maxOpenConns > 0 {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(maxOpenConns)
+	}
+	if !migrate {
+		return db, nil
+	}
 	err1 := migrateTables(db)
 	if err1 != nil {
 		return nil, err1
@@ -57,13 +62,64 @@ func connectDatabase() (*gorm.DB, error) {
 }
 
 func migrateTables(db *gorm.DB) error {
-	err1 := db.AutoMigrate(&models.Cart{},
+	err := mergeDuplicateCartLines(db)
+	if err != nil {
+		return err
+	}
+
+	err1 := db.AutoMigrate(&models.Category{},
+		&models.Cart{},
 		&models.Product{},
 		&models.ProductCart{},
-		&models.Client{})
+		&models.Client{},
+		&models.Order{},
+		&models.OrderItem{},
+		&models.Stock{},
+		&models.OrderTransition{},
+		&models.Payment{},
+		&models.Download{},
+		&models.Address{},
+		&models.RefreshToken{},
+		&models.PasswordResetToken{},
+		&models.ApiKey{})
 	if err1 != nil {
 		return err1
 	}
 
 	return nil
 }
+
+// mergeDuplicateCartLines folds the lines carts used to get for every unit
+// added into one line per product, capped at the most a cart may hold, so
+// the unique index on them can be created.
+func mergeDuplicateCartLines(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.ProductCart{}) || migrator.HasIndex(&models.ProductCart{}, cartLineIndex) {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if !tx.Migrator().HasColumn(&models.ProductCart{}, "Quantity") {
+			if err := tx.Migrator().AddColumn(&models.ProductCart{}, "Quantity"); err != nil {
+				return err
+			}
+		}
+
+		mergeResult := tx.Exec(`UPDATE product_carts SET quantity = (
+				SELECT CASE WHEN SUM(d.quantity) > ? THEN ? ELSE SUM(d.quantity) END FROM product_carts d
+				WHERE d.cart_id = product_carts.cart_id AND d.product_id = product_carts.product_id)
+			WHERE id IN (SELECT MIN(id) FROM product_carts GROUP BY cart_id, product_id HAVING COUNT(*) > 1)`,
+			models.MaxProductQuantity, models.MaxProductQuantity)
+		if mergeResult.Error != nil {
+			return mergeResult.Error
+		}
+		deleteResult := tx.Exec("DELETE FROM product_carts WHERE id NOT IN (SELECT MIN(id) FROM product_carts GROUP BY cart_id, product_id)")
+		if deleteResult.Error != nil {
+			return deleteResult.Error
+		}
+		if deleteResult.RowsAffected > 0 {
+			logger.Infof("merged %v duplicated cart lines", deleteResult.RowsAffected)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type (
+	DownloadRepository interface {
+		FindOrderItem(orderId, productId int) (*models.OrderItem, error)
+		RecordDownload(download *models.Download, limit int) error
+	}
+	PgDownloadRepository struct {
+		DbClient IDownloadRepositoryDbClient
+	}
+	IDownloadRepositoryDbClient interface {
+		First(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) (err error)
+	}
+)
+
+func (dr *PgDownloadRepository) FindOrderItem(orderId, productId int) (*models.OrderItem, error) {
+	item := models.OrderItem{}
+	itemResult := dr.DbClient.First(&item, "order_id = ? AND product_id = ?", orderId, productId)
+	if itemResult.Error != nil {
+		return nil, errors.New(fmt.Sprintf("the product: %v is not part of the order: %v", productId, orderId))
+	}
+	return &item, nil
+}
+
+// RecordDownload counts the download against the order item and keeps it in
+// the audit log. The count is only increased while it is below limit, so
+// concurrent downloads cannot go over it.
+func (dr *PgDownloadRepository) RecordDownload(download *models.Download, limit int) error {
+	return dr.DbClient.Transaction(func(tx *gorm.DB) error {
+		countResult := tx.Model(&models.OrderItem{}).
+			Where("order_id = ? AND product_id = ? AND downloads < ?", download.OrderId, download.ProductId, limit).
+			UpdateColumn("downloads", gorm.Expr("downloads + 1"))
+		if countResult.Error != nil {
+			logger.Errorf("unable to count download of product: %v in order: %v, with error: %v",
+				download.ProductId, download.OrderId, countResult.Error)
+			return errors.New(fmt.Sprintf("unable to record the download of the product: %v", download.ProductId))
+		}
+		if countResult.RowsAffected == 0 {
+			return fmt.Errorf("%w: the product: %v of the order: %v can be downloaded %v times",
+				models.ErrDownloadLimitReached, download.ProductId, download.OrderId, limit)
+		}
+
+		createResult := tx.Create(download)
+		if createResult.Error != nil {
+			logger.Errorf("unable to save download of product: %v in order: %v, with error: %v",
+				download.ProductId, download.OrderId, createResult.Error)
+			return errors.New(fmt.Sprintf("unable to record the download of the product: %v", download.ProductId))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"sort"
+)
+
+type MemoryAddressRepository struct {
+	Store *MemoryStore
+}
+
+func (ar *MemoryAddressRepository) ListAddresses(clientId int) (*[]models.Address, error) {
+	ar.Store.mutex.RLock()
+	defer ar.Store.mutex.RUnlock()
+
+	addresses := []models.Address{}
+	for _, address := range ar.Store.addresses {
+		if address.ClientId == clientId {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Id < addresses[j].Id
+	})
+	return &addresses, nil
+}
+
+func (ar *MemoryAddressRepository) FindAddress(clientId, addressId int) (*models.Address, error) {
+	ar.Store.mutex.RLock()
+	defer ar.Store.mutex.RUnlock()
+
+	address, found := ar.Store.addresses[addressId]
+	if !found || address.ClientId != clientId {
+		return nil, errors.New(fmt.Sprintf("address with id: %v not found", addressId))
+	}
+	return &address, nil
+}
+
+func (ar *MemoryAddressRepository) FindDefaultShippingAddress(clientId int) (*models.Address, error) {
+	ar.Store.mutex.RLock()
+	defer ar.Store.mutex.RUnlock()
+
+	for _, address := range ar.Store.addresses {
+		if address.ClientId == clientId && address.DefaultShipping {
+			return &address, nil
+		}
+	}
+	return nil, nil
+}
+
+// SaveAddress creates or updates the address. Flagging it as a default takes
+// the flag away from the other addresses of the client.
+func (ar *MemoryAddressRepository) SaveAddress(address *models.Address) error {
+	ar.Store.mutex.Lock()
+	defer ar.Store.mutex.Unlock()
+
+	for id, other := range ar.Store.addresses {
+		if other.ClientId != address.ClientId || id == address.Id {
+			continue
+		}
+		if address.DefaultShipping {
+			other.DefaultShipping = false
+		}
+		if address.DefaultBilling {
+			other.DefaultBilling = false
+		}
+		ar.Store.addresses[id] = other
+	}
+
+	now := ar.Store.now()
+	if address.Id == 0 {
+		address.Id = ar.Store.nextId("addresses")
+	}
+	if address.CreatedAt.IsZero() {
+		address.CreatedAt = now
+	}
+	address.UpdatedAt = now
+	ar.Store.addresses[address.Id] = *address
+	return nil
+}
+
+// DeleteAddress removes the address. When it was a default, the oldest of the
+// remaining addresses of the client takes the flag over.
+func (ar *MemoryAddressRepository) DeleteAddress(address *models.Address) error {
+	ar.Store.mutex.Lock()
+	defer ar.Store.mutex.Unlock()
+
+	delete(ar.Store.addresses, address.Id)
+	if !address.DefaultShipping && !address.DefaultBilling {
+		return nil
+	}
+
+	successor := models.Address{}
+	for _, other := range ar.Store.addresses {
+		if other.ClientId == address.ClientId && (successor.Id == 0 || other.Id < successor.Id) {
+			successor = other
+		}
+	}
+	if successor.Id == 0 {
+		return nil
+	}
+	successor.DefaultShipping = successor.DefaultShipping || address.DefaultShipping
+	successor.DefaultBilling = successor.DefaultBilling || address.DefaultBilling
+	ar.Store.addresses[successor.Id] = successor
+	return nil
+}
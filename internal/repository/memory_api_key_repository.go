@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"sort"
+	"time"
+)
+
+type MemoryApiKeyRepository struct {
+	Store *MemoryStore
+}
+
+func (ar *MemoryApiKeyRepository) CreateApiKey(apiKey *models.ApiKey) error {
+	ar.Store.mutex.Lock()
+	defer ar.Store.mutex.Unlock()
+
+	for _, other := range ar.Store.apiKeys {
+		if other.Prefix == apiKey.Prefix {
+			logger.Errorf("unable to create api key: %v, with error: the prefix is already in use", apiKey.Name)
+			return errors.New("unable to create the api key")
+		}
+	}
+
+	apiKey.Id = ar.Store.nextId("api_keys")
+	if apiKey.CreatedAt.IsZero() {
+		apiKey.CreatedAt = ar.Store.now()
+	}
+	ar.Store.apiKeys[apiKey.Id] = copyApiKey(*apiKey)
+	return nil
+}
+
+func (ar *MemoryApiKeyRepository) FindApiKeyByPrefix(prefix string) (*models.ApiKey, error) {
+	ar.Store.mutex.RLock()
+	defer ar.Store.mutex.RUnlock()
+
+	for _, apiKey := range ar.Store.apiKeys {
+		if apiKey.Prefix == prefix {
+			apiKey = copyApiKey(apiKey)
+			return &apiKey, nil
+		}
+	}
+	return nil, auth.ErrInvalidApiKey
+}
+
+func (ar *MemoryApiKeyRepository) ListApiKeys() (*[]models.ApiKey, error) {
+	ar.Store.mutex.RLock()
+	defer ar.Store.mutex.RUnlock()
+
+	apiKeys := []models.ApiKey{}
+	for _, apiKey := range ar.Store.apiKeys {
+		apiKeys = append(apiKeys, copyApiKey(apiKey))
+	}
+	sort.Slice(apiKeys, func(i, j int) bool {
+		return apiKeys[i].Id < apiKeys[j].Id
+	})
+	return &apiKeys, nil
+}
+
+func (ar *MemoryApiKeyRepository) RevokeApiKey(apiKeyId int) error {
+	ar.Store.mutex.Lock()
+	defer ar.Store.mutex.Unlock()
+
+	apiKey, found := ar.Store.apiKeys[apiKeyId]
+	if !found || apiKey.RevokedAt != nil {
+		return errors.New(fmt.Sprintf("active api key with id: %v not found", apiKeyId))
+	}
+	revokedAt := ar.Store.now()
+	apiKey.RevokedAt = &revokedAt
+	ar.Store.apiKeys[apiKeyId] = apiKey
+	return nil
+}
+
+func (ar *MemoryApiKeyRepository) TouchApiKey(apiKeyId int, usedAt time.Time) error {
+	ar.Store.mutex.Lock()
+	defer ar.Store.mutex.Unlock()
+
+	apiKey, found := ar.Store.apiKeys[apiKeyId]
+	if !found {
+		return nil
+	}
+	apiKey.LastUsedAt = &usedAt
+	ar.Store.apiKeys[apiKeyId] = apiKey
+	return nil
+}
+
+// copyApiKey gives the key its own usage and revocation dates, which are
+// pointers.
+func copyApiKey(apiKey models.ApiKey) models.ApiKey {
+	if apiKey.LastUsedAt != nil {
+		lastUsedAt := *apiKey.LastUsedAt
+		apiKey.LastUsedAt = &lastUsedAt
+	}
+	if apiKey.RevokedAt != nil {
+		revokedAt := *apiKey.RevokedAt
+		apiKey.RevokedAt = &revokedAt
+	}
+	return apiKey
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"sort"
+)
+
+type MemoryCartRepository struct {
+	Store *MemoryStore
+}
+
+func (cr *MemoryCartRepository) GetCartByClient(clientId int) (*models.Cart, error) {
+	cr.Store.mutex.RLock()
+	defer cr.Store.mutex.RUnlock()
+
+	cart, found := cr.Store.cartForClient(clientId)
+	if !found {
+		return nil, errors.New(fmt.Sprintf("cart for client id: %v not found", clientId))
+	}
+	return &cart, nil
+}
+
+func (cr *MemoryCartRepository) GetCartItems(cartId int) (*[]models.ProductCart, error) {
+	cr.Store.mutex.RLock()
+	defer cr.Store.mutex.RUnlock()
+
+	var cartItems []models.ProductCart
+	for _, e := range cr.Store.cartItems(cartId) {
+		e.Product, _ = cr.Store.product(e.ProductId)
+		cartItems = append(cartItems, e)
+	}
+	return &cartItems, nil
+}
+
+func (cr *MemoryCartRepository) AddProductToCart(productId, clientId int) error {
+	cr.Store.mutex.Lock()
+	defer cr.Store.mutex.Unlock()
+
+	cart := cr.Store.findOrCreateCart(clientId)
+	productCart, found := cr.Store.cartItem(cart.Id, productId)
+	if found {
+		if productCart.Quantity >= models.MaxProductQuantity {
+			return models.NewCartQuantityLimitError(productId)
+		}
+		productCart.Quantity++
+		cr.Store.productCarts[productCart.Id] = productCart
+		return nil
+	}
+
+	productCart = models.ProductCart{
+		Id:        cr.Store.nextId("product_carts"),
+		ProductId: productId,
+		CartId:    cart.Id,
+		Quantity:  1,
+	}
+	cr.Store.productCarts[productCart.Id] = productCart
+	return nil
+}
+
+func (cr *MemoryCartRepository) SetProductQuantity(productId, clientId, quantity int) error {
+	cr.Store.mutex.Lock()
+	defer cr.Store.mutex.Unlock()
+
+	cart := cr.Store.findOrCreateCart(clientId)
+	productCart, found := cr.Store.cartItem(cart.Id, productId)
+	if !found {
+		productCart = models.ProductCart{
+			Id:        cr.Store.nextId("product_carts"),
+			ProductId: productId,
+			CartId:    cart.Id,
+		}
+	}
+	productCart.Quantity = quantity
+	cr.Store.productCarts[productCart.Id] = productCart
+	return nil
+}
+
+func (cr *MemoryCartRepository) RemoveProductFromCart(productId, clientId int) error {
+	cr.Store.mutex.Lock()
+	defer cr.Store.mutex.Unlock()
+
+	cart, found := cr.Store.cartForClient(clientId)
+	if !found {
+		return errors.New(fmt.Sprintf("cart for client id: %v not found", clientId))
+	}
+
+	productCart, found := cr.Store.cartItem(cart.Id, productId)
+	if !found {
+		return errors.New(fmt.Sprintf("product with id: %v is not in the cart", productId))
+	}
+	delete(cr.Store.productCarts, productCart.Id)
+	return nil
+}
+
+func (cr *MemoryCartRepository) ClearCart(clientId int) error {
+	cr.Store.mutex.Lock()
+	defer cr.Store.mutex.Unlock()
+
+	cart, found := cr.Store.cartForClient(clientId)
+	if !found {
+		return errors.New(fmt.Sprintf("cart for client id: %v not found", clientId))
+	}
+	cr.Store.clearCart(cart.Id)
+	return nil
+}
+
+func (ms *MemoryStore) cartForClient(clientId int) (models.Cart, bool) {
+	for _, cart := range ms.carts {
+		if cart.ClientId == clientId {
+			return cart, true
+		}
+	}
+	return models.Cart{}, false
+}
+
+func (ms *MemoryStore) findOrCreateCart(clientId int) models.Cart {
+	cart, found := ms.cartForClient(clientId)
+	if found {
+		return cart
+	}
+	cart = models.Cart{Id: ms.nextId("carts"), ClientId: clientId}
+	ms.carts[cart.Id] = cart
+	return cart
+}
+
+// cartItems returns the products of the cart in the order they were added.
+func (ms *MemoryStore) cartItems(cartId int) []models.ProductCart {
+	var items []models.ProductCart
+	for _, e := range ms.productCarts {
+		if e.CartId == cartId {
+			items = append(items, e)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Id < items[j].Id
+	})
+	return items
+}
+
+func (ms *MemoryStore) cartItem(cartId, productId int) (models.ProductCart, bool) {
+	for _, e := range ms.productCarts {
+		if e.CartId == cartId && e.ProductId == productId {
+			return e, true
+		}
+	}
+	return models.ProductCart{}, false
+}
+
+func (ms *MemoryStore) clearCart(cartId int) {
+	for id, e := range ms.productCarts {
+		if e.CartId == cartId {
+			delete(ms.productCarts, id)
+		}
+	}
+}
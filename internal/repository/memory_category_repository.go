@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"sort"
+)
+
+type MemoryCategoryRepository struct {
+	Store *MemoryStore
+}
+
+func (cr *MemoryCategoryRepository) CreateCategory(category *models.Category) error {
+	cr.Store.mutex.Lock()
+	defer cr.Store.mutex.Unlock()
+
+	category.Id = cr.Store.nextId("categories")
+	cr.Store.categories[category.Id] = copyCategory(*category)
+	return nil
+}
+
+func (cr *MemoryCategoryRepository) FindCategoryById(categoryId int) (*models.Category, error) {
+	cr.Store.mutex.RLock()
+	defer cr.Store.mutex.RUnlock()
+
+	category, found := cr.Store.categories[categoryId]
+	if !found {
+		return nil, errors.New(fmt.Sprintf("category with id: %v not found", categoryId))
+	}
+	category = copyCategory(category)
+	return &category, nil
+}
+
+func (cr *MemoryCategoryRepository) ListCategories() (*[]models.Category, error) {
+	cr.Store.mutex.RLock()
+	defer cr.Store.mutex.RUnlock()
+
+	categories := []models.Category{}
+	for _, category := range cr.Store.categories {
+		categories = append(categories, copyCategory(category))
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Id < categories[j].Id
+	})
+	return &categories, nil
+}
+
+func (cr *MemoryCategoryRepository) UpdateCategory(category *models.Category) error {
+	cr.Store.mutex.Lock()
+	defer cr.Store.mutex.Unlock()
+
+	cr.Store.categories[category.Id] = copyCategory(*category)
+	return nil
+}
+
+func (cr *MemoryCategoryRepository) DeleteCategory(categoryId int) error {
+	cr.Store.mutex.Lock()
+	defer cr.Store.mutex.Unlock()
+
+	for _, category := range cr.Store.categories {
+		if category.ParentId != nil && *category.ParentId == categoryId {
+			return errors.New(fmt.Sprintf("category with id: %v has subcategories", categoryId))
+		}
+	}
+	for _, product := range cr.Store.products {
+		if product.CategoryId == categoryId {
+			return errors.New(fmt.Sprintf("category with id: %v still has products", categoryId))
+		}
+	}
+
+	if _, found := cr.Store.categories[categoryId]; !found {
+		return errors.New(fmt.Sprintf("category with id: %v not found", categoryId))
+	}
+	delete(cr.Store.categories, categoryId)
+	return nil
+}
+
+// copyCategory gives the category its own parent id, which is a pointer.
+func copyCategory(category models.Category) models.Category {
+	if category.ParentId != nil {
+		parentId := *category.ParentId
+		category.ParentId = &parentId
+	}
+	return category
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type MemoryClientRepository struct {
+	Store *MemoryStore
+}
+
+func (cr *MemoryClientRepository) IsClientInDataBase(clientId int) bool {
+	cr.Store.mutex.RLock()
+	defer cr.Store.mutex.RUnlock()
+
+	_, found := cr.Store.activeClient(clientId)
+	return found
+}
+
+func (cr *MemoryClientRepository) FindClientById(clientId int) (*models.Client, error) {
+	cr.Store.mutex.RLock()
+	defer cr.Store.mutex.RUnlock()
+
+	client, found := cr.Store.activeClient(clientId)
+	if !found {
+		return nil, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+	return &client, nil
+}
+
+func (cr *MemoryClientRepository) FindClientByEmail(email string) (*models.Client, error) {
+	cr.Store.mutex.RLock()
+	defer cr.Store.mutex.RUnlock()
+
+	client, found := cr.Store.activeClientByEmail(email)
+	if !found {
+		return nil, nil
+	}
+	return &client, nil
+}
+
+func (cr *MemoryClientRepository) CreateClient(client *models.Client) error {
+	cr.Store.mutex.Lock()
+	defer cr.Store.mutex.Unlock()
+
+	if _, taken := cr.Store.activeClientByEmail(client.Email); taken {
+		logger.Errorf("unable to create client with error: the email is already in use")
+		return models.NewEmailTakenError(client.Email)
+	}
+
+	now := cr.Store.now()
+	client.Id = cr.Store.nextId("clients")
+	if client.Role == "" {
+		client.Role = models.RoleCustomer
+	}
+	if client.CreatedAt.IsZero() {
+		client.CreatedAt = now
+	}
+	client.UpdatedAt = now
+	cr.Store.clients[client.Id] = *client
+	return nil
+}
+
+func (cr *MemoryClientRepository) UpdateClient(client *models.Client) error {
+	cr.Store.mutex.Lock()
+	defer cr.Store.mutex.Unlock()
+
+	if owner, taken := cr.Store.activeClientByEmail(client.Email); taken && owner.Id != client.Id {
+		logger.Errorf("unable to update client: %v, with error: the email is already in use", client.Id)
+		return models.NewEmailTakenError(client.Email)
+	}
+
+	client.UpdatedAt = cr.Store.now()
+	cr.Store.clients[client.Id] = *client
+	return nil
+}
+
+// DeleteClient closes the account and drops its address book. Orders are kept
+// for the records of the store.
+func (cr *MemoryClientRepository) DeleteClient(clientId int) error {
+	cr.Store.mutex.Lock()
+	defer cr.Store.mutex.Unlock()
+
+	client, found := cr.Store.activeClient(clientId)
+	if !found {
+		return errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+
+	for id, address := range cr.Store.addresses {
+		if address.ClientId == clientId {
+			delete(cr.Store.addresses, id)
+		}
+	}
+	client.DeletedAt = gorm.DeletedAt{Time: cr.Store.now(), Valid: true}
+	cr.Store.clients[clientId] = client
+	return nil
+}
+
+// activeClient skips deleted accounts, as gorm does for soft deleted rows.
+func (ms *MemoryStore) activeClient(clientId int) (models.Client, bool) {
+	client, found := ms.clients[clientId]
+	if !found || client.DeletedAt.Valid {
+		return models.Client{}, false
+	}
+	return client, true
+}
+
+func (ms *MemoryStore) activeClientByEmail(email string) (models.Client, bool) {
+	for _, client := range ms.clients {
+		if client.Email == email && !client.DeletedAt.Valid {
+			return client, true
+		}
+	}
+	return models.Client{}, false
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+)
+
+type MemoryDownloadRepository struct {
+	Store *MemoryStore
+}
+
+func (dr *MemoryDownloadRepository) FindOrderItem(orderId, productId int) (*models.OrderItem, error) {
+	dr.Store.mutex.RLock()
+	defer dr.Store.mutex.RUnlock()
+
+	item, found := dr.Store.orderItem(orderId, productId)
+	if !found {
+		return nil, errors.New(fmt.Sprintf("the product: %v is not part of the order: %v", productId, orderId))
+	}
+	return &item, nil
+}
+
+// RecordDownload counts the download against the order item and keeps it in
+// the audit log, as long as the count is below limit.
+func (dr *MemoryDownloadRepository) RecordDownload(download *models.Download, limit int) error {
+	dr.Store.mutex.Lock()
+	defer dr.Store.mutex.Unlock()
+
+	item, found := dr.Store.orderItem(download.OrderId, download.ProductId)
+	if !found || item.Downloads >= limit {
+		return fmt.Errorf("%w: the product: %v of the order: %v can be downloaded %v times",
+			models.ErrDownloadLimitReached, download.ProductId, download.OrderId, limit)
+	}
+	item.Downloads++
+	dr.Store.orderItems[item.Id] = item
+
+	download.Id = dr.Store.nextId("downloads")
+	if download.CreatedAt.IsZero() {
+		download.CreatedAt = dr.Store.now()
+	}
+	dr.Store.downloads[download.Id] = *download
+	return nil
+}
+
+func (ms *MemoryStore) orderItem(orderId, productId int) (models.OrderItem, bool) {
+	for _, e := range ms.orderItemsOf(orderId) {
+		if e.ProductId == productId {
+			return e, true
+		}
+	}
+	return models.OrderItem{}, false
+}
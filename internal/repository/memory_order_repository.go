@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"sort"
+	"time"
+)
+
+type MemoryOrderRepository struct {
+	Store *MemoryStore
+}
+
+func (or *MemoryOrderRepository) CreateOrder(clientId int, shipping models.OrderShipping) (*models.Order, error) {
+	or.Store.mutex.Lock()
+	defer or.Store.mutex.Unlock()
+
+	if _, found := or.Store.activeClient(clientId); !found {
+		return nil, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+	cart, found := or.Store.cartForClient(clientId)
+	if !found {
+		return nil, errors.New(fmt.Sprintf("cart for client id: %v not found", clientId))
+	}
+
+	order := models.Order{ClientId: clientId, Status: models.OrderStatusPending, Shipping: shipping}
+	err := or.convertCartToOrder(cart, &order)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (or *MemoryOrderRepository) GetOrderWithItems(clientId, orderId int) (*models.Order, error) {
+	or.Store.mutex.RLock()
+	defer or.Store.mutex.RUnlock()
+
+	if _, found := or.Store.activeClient(clientId); !found {
+		return nil, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+	order, err := or.Store.orderForClient(clientId, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Items = or.Store.orderItemsOf(order.Id)
+	for i, e := range order.Items {
+		order.Items[i].Product, _ = or.Store.product(e.ProductId)
+	}
+	return &order, nil
+}
+
+func (or *MemoryOrderRepository) ListOrdersForClient(clientId, page, pageSize int) (*[]models.OrderSummary, int64, error) {
+	or.Store.mutex.RLock()
+	defer or.Store.mutex.RUnlock()
+
+	if _, found := or.Store.activeClient(clientId); !found {
+		return nil, 0, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+
+	var orders []models.Order
+	for _, order := range or.Store.orders {
+		if order.ClientId == clientId {
+			orders = append(orders, order)
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].CreatedAt.Equal(orders[j].CreatedAt) {
+			return orders[i].CreatedAt.After(orders[j].CreatedAt)
+		}
+		return orders[i].Id > orders[j].Id
+	})
+
+	start, end := pageBounds(len(orders), page, pageSize)
+	summaries := make([]models.OrderSummary, 0, end-start)
+	for _, order := range orders[start:end] {
+		itemCount := 0
+		for _, e := range or.Store.orderItemsOf(order.Id) {
+			itemCount += e.Quantity
+		}
+		summaries = append(summaries, models.OrderSummary{Order: order, ItemCount: itemCount})
+	}
+	return &summaries, int64(len(orders)), nil
+}
+
+func (or *MemoryOrderRepository) FindOrderById(orderId int) (*models.Order, error) {
+	or.Store.mutex.RLock()
+	defer or.Store.mutex.RUnlock()
+
+	order, found := or.Store.orders[orderId]
+	if !found {
+		return nil, errors.New(fmt.Sprintf("order: %v not found", orderId))
+	}
+	return &order, nil
+}
+
+func (or *MemoryOrderRepository) FindOrderForClient(clientId, orderId int) (*models.Order, error) {
+	or.Store.mutex.RLock()
+	defer or.Store.mutex.RUnlock()
+
+	if _, found := or.Store.activeClient(clientId); !found {
+		return nil, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+	order, err := or.Store.orderForClient(clientId, orderId)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// TransitionOrder moves the order from one status to the next and records who
+// did it, failing when the order is no longer in the expected status.
+func (or *MemoryOrderRepository) TransitionOrder(orderId int, from, to models.OrderStatus, actor string) (*models.OrderTransition, error) {
+	or.Store.mutex.Lock()
+	defer or.Store.mutex.Unlock()
+
+	transition := models.OrderTransition{OrderId: orderId, FromStatus: from, ToStatus: to, Actor: actor}
+	if _, err := or.transitionOrder(&transition, ""); err != nil {
+		return nil, err
+	}
+	return &transition, nil
+}
+
+// CancelOrder moves the order to cancelled recording why, which gives back its
+// reserved stock and flags it for refund when it was already paid.
+func (or *MemoryOrderRepository) CancelOrder(orderId int, from models.OrderStatus, actor, reason string) (*models.Order, error) {
+	or.Store.mutex.Lock()
+	defer or.Store.mutex.Unlock()
+
+	transition := models.OrderTransition{OrderId: orderId, FromStatus: from, ToStatus: models.OrderStatusCancelled, Actor: actor}
+	return or.transitionOrder(&transition, reason)
+}
+
+func (or *MemoryOrderRepository) ListOrderTransitions(orderId int) (*[]models.OrderTransition, error) {
+	or.Store.mutex.RLock()
+	defer or.Store.mutex.RUnlock()
+
+	transitions := []models.OrderTransition{}
+	for _, transition := range or.Store.transitions {
+		if transition.OrderId == orderId {
+			transitions = append(transitions, transition)
+		}
+	}
+	sort.Slice(transitions, func(i, j int) bool {
+		return transitions[i].Id < transitions[j].Id
+	})
+	return &transitions, nil
+}
+
+// ReleaseAbandonedOrders cancels the pending orders placed before createdBefore,
+// which gives their reserved stock back.
+func (or *MemoryOrderRepository) ReleaseAbandonedOrders(createdBefore time.Time) ([]int, error) {
+	or.Store.mutex.Lock()
+	defer or.Store.mutex.Unlock()
+
+	abandoned := []int{}
+	for _, order := range or.Store.orders {
+		if order.Status == models.OrderStatusPending && order.CreatedAt.Before(createdBefore) {
+			abandoned = append(abandoned, order.Id)
+		}
+	}
+	sort.Ints(abandoned)
+
+	for i, orderId := range abandoned {
+		transition := models.OrderTransition{
+			OrderId:    orderId,
+			FromStatus: models.OrderStatusPending,
+			ToStatus:   models.OrderStatusCancelled,
+			Actor:      models.SystemActor,
+		}
+		if _, err := or.transitionOrder(&transition, AbandonedOrderReason); err != nil {
+			return abandoned[:i], err
+		}
+	}
+	return abandoned, nil
+}
+
+// convertCartToOrder copies the products of the cart into order items and
+// empties it. Everything is checked before the store is written, so a failed
+// checkout leaves it untouched.
+func (or *MemoryOrderRepository) convertCartToOrder(cart models.Cart, order *models.Order) error {
+	productsCarts := or.Store.cartItems(cart.Id)
+	if len(productsCarts) == 0 {
+		return errors.New("the cart has no products")
+	}
+
+	shippable := false
+	weight := 0.0
+	orderItems := make([]models.OrderItem, 0, len(productsCarts))
+	for _, e := range productsCarts {
+		product, found := or.Store.product(e.ProductId)
+		if !found || product.IsArchived() {
+			return errors.New(fmt.Sprintf("the product: %v is no longer available", e.ProductId))
+		}
+		if product.Type.IsShippable() {
+			shippable = true
+			weight += product.Weight * float64(e.Quantity)
+		}
+
+		total, err := order.Total.Add(product.Price.Multiply(e.Quantity))
+		if err != nil {
+			return errors.New("the cart contains products priced in different currencies")
+		}
+		order.Total = total
+		orderItems = append(orderItems, models.OrderItem{
+			ProductId:     e.ProductId,
+			UnitPrice:     product.Price,
+			Quantity:      e.Quantity,
+			StockReserved: product.Type.TracksStock(),
+		})
+	}
+
+	err := addShippingCost(order, shippable, weight)
+	if err != nil {
+		return err
+	}
+
+	untracked, err := or.Store.reserveStock(reservedQuantities(orderItems))
+	if err != nil {
+		return err
+	}
+	skipUntrackedStock(orderItems, untracked)
+
+	order.Id = or.Store.nextId("orders")
+	order.CreatedAt = or.Store.now()
+	or.Store.orders[order.Id] = *order
+
+	for i := range orderItems {
+		orderItems[i].Id = or.Store.nextId("order_items")
+		orderItems[i].OrderId = order.Id
+		or.Store.orderItems[orderItems[i].Id] = orderItems[i]
+	}
+	order.Items = orderItems
+
+	or.Store.clearCart(cart.Id)
+	return nil
+}
+
+func (or *MemoryOrderRepository) transitionOrder(transition *models.OrderTransition, reason string) (*models.Order, error) {
+	order, found := or.Store.orders[transition.OrderId]
+	if !found {
+		return nil, errors.New(fmt.Sprintf("order: %v not found", transition.OrderId))
+	}
+	if order.Status != transition.FromStatus {
+		return nil, models.NewInvalidOrderTransitionError(order.Status, transition.ToStatus)
+	}
+
+	orderItems := or.Store.orderItemsOf(order.Id)
+	switch transition.ToStatus {
+	case models.OrderStatusCancelled:
+		or.Store.releaseStock(reservedQuantities(orderItems))
+		order.CancellationReason = reason
+		order.RefundPending = order.Status.HasCollectedPayment()
+	case models.OrderStatusShipped:
+		or.Store.commitStock(reservedQuantities(orderItems))
+	case models.OrderStatusRefunded:
+		order.RefundPending = false
+	}
+	order.Status = transition.ToStatus
+	or.Store.orders[order.Id] = order
+
+	transition.Id = or.Store.nextId("order_transitions")
+	transition.CreatedAt = or.Store.now()
+	or.Store.transitions[transition.Id] = *transition
+	return &order, nil
+}
+
+func (ms *MemoryStore) orderForClient(clientId, orderId int) (models.Order, error) {
+	order, found := ms.orders[orderId]
+	if !found || order.ClientId != clientId {
+		return models.Order{}, errors.New(fmt.Sprintf("order: %v for clientId: %v not found", orderId, clientId))
+	}
+	return order, nil
+}
+
+func (ms *MemoryStore) orderItemsOf(orderId int) []models.OrderItem {
+	var items []models.OrderItem
+	for _, e := range ms.orderItems {
+		if e.OrderId == orderId {
+			items = append(items, e)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Id < items[j].Id
+	})
+	return items
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+)
+
+type MemoryPaymentRepository struct {
+	Store *MemoryStore
+}
+
+// FindPaymentByOrderId returns nil without error when the order was never
+// charged.
+func (pr *MemoryPaymentRepository) FindPaymentByOrderId(orderId int) (*models.Payment, error) {
+	pr.Store.mutex.RLock()
+	defer pr.Store.mutex.RUnlock()
+
+	for _, payment := range pr.Store.payments {
+		if payment.OrderId == orderId {
+			return &payment, nil
+		}
+	}
+	return nil, nil
+}
+
+func (pr *MemoryPaymentRepository) SavePayment(payment *models.Payment) error {
+	pr.Store.mutex.Lock()
+	defer pr.Store.mutex.Unlock()
+
+	for _, other := range pr.Store.payments {
+		if other.OrderId == payment.OrderId && other.Id != payment.Id {
+			logger.Errorf("unable to save payment of order: %v, with error: the order already has a payment", payment.OrderId)
+			return errors.New(fmt.Sprintf("unable to save the payment of the order: %v", payment.OrderId))
+		}
+	}
+
+	now := pr.Store.now()
+	if payment.Id == 0 {
+		payment.Id = pr.Store.nextId("payments")
+	}
+	if payment.CreatedAt.IsZero() {
+		payment.CreatedAt = now
+	}
+	payment.UpdatedAt = now
+	pr.Store.payments[payment.Id] = *payment
+	return nil
+}
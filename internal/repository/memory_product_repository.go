@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"sort"
+	"strings"
+)
+
+var (
+	// productSortLess mirrors productSortColumns, every sort breaking ties by
+	// id.
+	productSortLess = map[string]func(a, b models.Product) bool{
+		models.ProductSortById: func(a, b models.Product) bool {
+			return a.Id < b.Id
+		},
+		models.ProductSortByLabel: func(a, b models.Product) bool {
+			if a.Label != b.Label {
+				return a.Label < b.Label
+			}
+			return a.Id < b.Id
+		},
+		models.ProductSortByLabelDesc: func(a, b models.Product) bool {
+			if a.Label != b.Label {
+				return a.Label > b.Label
+			}
+			return a.Id < b.Id
+		},
+		models.ProductSortByPrice: func(a, b models.Product) bool {
+			if a.Price.Amount != b.Price.Amount {
+				return a.Price.Amount < b.Price.Amount
+			}
+			return a.Id < b.Id
+		},
+		models.ProductSortByPriceDesc: func(a, b models.Product) bool {
+			if a.Price.Amount != b.Price.Amount {
+				return a.Price.Amount > b.Price.Amount
+			}
+			return a.Id < b.Id
+		},
+	}
+)
+
+type MemoryProductRepository struct {
+	Store *MemoryStore
+}
+
+func (pr *MemoryProductRepository) FindProductById(productId int) (*models.Product, error) {
+	pr.Store.mutex.RLock()
+	defer pr.Store.mutex.RUnlock()
+
+	product, found := pr.Store.product(productId)
+	if !found {
+		return nil, errors.New("unable to find the product in our db")
+	}
+	product.Category = pr.Store.categories[product.CategoryId]
+	return &product, nil
+}
+
+func (pr *MemoryProductRepository) ListProducts(filter models.ProductFilter) (*[]models.Product, int64, error) {
+	pr.Store.mutex.RLock()
+	defer pr.Store.mutex.RUnlock()
+
+	matching := []models.Product{}
+	for id := range pr.Store.products {
+		product, _ := pr.Store.product(id)
+		if matchesProductFilter(product, filter) {
+			product.Category = pr.Store.categories[product.CategoryId]
+			matching = append(matching, product)
+		}
+	}
+
+	less, ok := productSortLess[filter.Sort]
+	if !ok {
+		less = productSortLess[models.ProductSortById]
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return less(matching[i], matching[j])
+	})
+
+	start, end := pageBounds(len(matching), filter.Page, filter.PageSize)
+	products := matching[start:end]
+	return &products, int64(len(matching)), nil
+}
+
+func (pr *MemoryProductRepository) CreateProduct(product *models.Product) error {
+	pr.Store.mutex.Lock()
+	defer pr.Store.mutex.Unlock()
+
+	product.Id = pr.Store.nextId("products")
+	pr.Store.saveProduct(*product)
+	if product.Type.TracksStock() {
+		pr.Store.stocks[product.Id] = models.Stock{ProductId: product.Id}
+	}
+	return nil
+}
+
+func (pr *MemoryProductRepository) UpdateProduct(product *models.Product) error {
+	pr.Store.mutex.Lock()
+	defer pr.Store.mutex.Unlock()
+
+	pr.Store.saveProduct(*product)
+	return nil
+}
+
+// product returns a copy of the product that does not share its archive date
+// with the stored row.
+func (ms *MemoryStore) product(productId int) (models.Product, bool) {
+	product, found := ms.products[productId]
+	if found && product.ArchivedAt != nil {
+		archivedAt := *product.ArchivedAt
+		product.ArchivedAt = &archivedAt
+	}
+	return product, found
+}
+
+// saveProduct stores the product without its category, which lives in its
+// own table.
+func (ms *MemoryStore) saveProduct(product models.Product) {
+	product.Category = models.Category{}
+	if product.ArchivedAt != nil {
+		archivedAt := *product.ArchivedAt
+		product.ArchivedAt = &archivedAt
+	}
+	ms.products[product.Id] = product
+}
+
+func matchesProductFilter(product models.Product, filter models.ProductFilter) bool {
+	if product.IsArchived() {
+		return false
+	}
+	if len(filter.CategoryIds) > 0 && !containsId(filter.CategoryIds, product.CategoryId) {
+		return false
+	}
+	if filter.Type != nil && product.Type != *filter.Type {
+		return false
+	}
+	return strings.Contains(strings.ToLower(product.Label), strings.ToLower(filter.Label))
+}
+
+func containsId(ids []int, id int) bool {
+	for _, e := range ids {
+		if e == id {
+			return true
+		}
+	}
+	return false
+}
+
+// pageBounds works out the slice of n sorted rows that a LIMIT and OFFSET
+// would return.
+func pageBounds(n, page, pageSize int) (int, int) {
+	start := (page - 1) * pageSize
+	if start < 0 {
+		start = 0
+	}
+	if start > n {
+		start = n
+	}
+	end := start + pageSize
+	if pageSize <= 0 || end > n {
+		end = n
+	}
+	return start, end
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+)
+
+type MemoryStockRepository struct {
+	Store *MemoryStore
+}
+
+// GetStock returns the ledger of the product, or nil when its stock is not
+// tracked.
+func (sr *MemoryStockRepository) GetStock(productId int) (*models.Stock, error) {
+	sr.Store.mutex.RLock()
+	defer sr.Store.mutex.RUnlock()
+
+	stock, found := sr.Store.stocks[productId]
+	if !found {
+		return nil, nil
+	}
+	return &stock, nil
+}
+
+func (sr *MemoryStockRepository) SetOnHand(productId, onHand int) (*models.Stock, error) {
+	sr.Store.mutex.Lock()
+	defer sr.Store.mutex.Unlock()
+
+	stock := sr.Store.stocks[productId]
+	stock.ProductId = productId
+	if onHand < stock.Reserved {
+		return nil, errors.New(fmt.Sprintf("on hand cannot be lower than the %v units reserved by placed orders", stock.Reserved))
+	}
+	stock.OnHand = onHand
+	sr.Store.stocks[productId] = stock
+	return &stock, nil
+}
+
+// reserveStock checks every product before holding any unit, so a failed
+// checkout leaves the ledger untouched. It returns the products whose stock
+// is not tracked, which are sold without limit.
+func (ms *MemoryStore) reserveStock(quantities map[int]int) (map[int]bool, error) {
+	untracked := make(map[int]bool)
+	for _, productId := range sortedProductIds(quantities) {
+		stock, found := ms.stocks[productId]
+		if !found {
+			untracked[productId] = true
+			continue
+		}
+		if stock.Available() < quantities[productId] {
+			return nil, models.NewInsufficientStockError(productId, stock.Available())
+		}
+	}
+	for productId, quantity := range quantities {
+		if untracked[productId] {
+			continue
+		}
+		stock := ms.stocks[productId]
+		stock.Reserved += quantity
+		ms.stocks[productId] = stock
+	}
+	return untracked, nil
+}
+
+// releaseStock gives back units reserved by reserveStock.
+func (ms *MemoryStore) releaseStock(quantities map[int]int) {
+	for productId, quantity := range quantities {
+		if stock, found := ms.stocks[productId]; found {
+			stock.Reserved -= quantity
+			ms.stocks[productId] = stock
+		}
+	}
+}
+
+// commitStock takes reserved units out of the warehouse once they are shipped.
+func (ms *MemoryStore) commitStock(quantities map[int]int) {
+	for productId, quantity := range quantities {
+		if stock, found := ms.stocks[productId]; found {
+			stock.OnHand -= quantity
+			stock.Reserved -= quantity
+			ms.stocks[productId] = stock
+		}
+	}
+}
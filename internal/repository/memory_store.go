@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps every table of the store in memory behind a single lock,
+// so the memory repositories built on it see each other's writes and each
+// call is atomic, like a transaction. Rows are stored and returned by value so
+// callers never share them.
+type MemoryStore struct {
+	mutex sync.RWMutex
+	ids   map[string]int
+
+	addresses     map[int]models.Address
+	apiKeys       map[int]models.ApiKey
+	carts         map[int]models.Cart
+	categories    map[int]models.Category
+	clients       map[int]models.Client
+	downloads     map[int]models.Download
+	orders        map[int]models.Order
+	orderItems    map[int]models.OrderItem
+	payments      map[int]models.Payment
+	productCarts  map[int]models.ProductCart
+	products      map[int]models.Product
+	refreshTokens map[int]models.RefreshToken
+	stocks        map[int]models.Stock
+	transitions   map[int]models.OrderTransition
+
+	passwordResetTokens map[int]models.PasswordResetToken
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		ids:           map[string]int{},
+		addresses:     map[int]models.Address{},
+		apiKeys:       map[int]models.ApiKey{},
+		carts:         map[int]models.Cart{},
+		categories:    map[int]models.Category{},
+		clients:       map[int]models.Client{},
+		downloads:     map[int]models.Download{},
+		orders:        map[int]models.Order{},
+		orderItems:    map[int]models.OrderItem{},
+		payments:      map[int]models.Payment{},
+		productCarts:  map[int]models.ProductCart{},
+		products:      map[int]models.Product{},
+		refreshTokens: map[int]models.RefreshToken{},
+		stocks:        map[int]models.Stock{},
+		transitions:   map[int]models.OrderTransition{},
+
+		passwordResetTokens: map[int]models.PasswordResetToken{},
+	}
+}
+
+// NewMemoryRepositories returns repositories that keep their data in the
+// given store, which is lost on restart.
+func NewMemoryRepositories(store *MemoryStore) Repositories {
+	return Repositories{
+		Address:  &MemoryAddressRepository{Store: store},
+		ApiKey:   &MemoryApiKeyRepository{Store: store},
+		Cart:     &MemoryCartRepository{Store: store},
+		Category: &MemoryCategoryRepository{Store: store},
+		Client:   &MemoryClientRepository{Store: store},
+		Download: &MemoryDownloadRepository{Store: store},
+		Order:    &MemoryOrderRepository{Store: store},
+		Payment:  &MemoryPaymentRepository{Store: store},
+		Product:  &MemoryProductRepository{Store: store},
+		Stock:    &MemoryStockRepository{Store: store},
+		Token:    &MemoryTokenRepository{Store: store},
+	}
+}
+
+// nextId hands out primary keys per table, starting at 1 like a serial
+// column.
+func (ms *MemoryStore) nextId(table string) int {
+	ms.ids[table]++
+	return ms.ids[table]
+}
+
+// now drops the monotonic clock reading, as a round trip through the
+// database would.
+func (ms *MemoryStore) now() time.Time {
+	return time.Now().Round(0)
+}
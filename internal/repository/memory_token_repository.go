@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+)
+
+type MemoryTokenRepository struct {
+	Store *MemoryStore
+}
+
+func (tr *MemoryTokenRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	tr.Store.mutex.Lock()
+	defer tr.Store.mutex.Unlock()
+
+	for _, other := range tr.Store.refreshTokens {
+		if other.TokenHash == token.TokenHash {
+			logger.Errorf("unable to create refresh token for client: %v, with error: the token already exists", token.ClientId)
+			return errors.New("unable to create the refresh token")
+		}
+	}
+
+	token.Id = tr.Store.nextId("refresh_tokens")
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = tr.Store.now()
+	}
+	tr.Store.refreshTokens[token.Id] = copyRefreshToken(*token)
+	return nil
+}
+
+func (tr *MemoryTokenRepository) FindRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	tr.Store.mutex.RLock()
+	defer tr.Store.mutex.RUnlock()
+
+	for _, token := range tr.Store.refreshTokens {
+		if token.TokenHash == tokenHash {
+			token = copyRefreshToken(token)
+			return &token, nil
+		}
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+func (tr *MemoryTokenRepository) RevokeRefreshToken(tokenId int) error {
+	tr.Store.mutex.Lock()
+	defer tr.Store.mutex.Unlock()
+
+	token, found := tr.Store.refreshTokens[tokenId]
+	if !found || token.RevokedAt != nil {
+		return auth.ErrInvalidToken
+	}
+	revokedAt := tr.Store.now()
+	token.RevokedAt = &revokedAt
+	tr.Store.refreshTokens[tokenId] = token
+	return nil
+}
+
+func (tr *MemoryTokenRepository) RevokeClientRefreshTokens(clientId int) error {
+	tr.Store.mutex.Lock()
+	defer tr.Store.mutex.Unlock()
+
+	revokedAt := tr.Store.now()
+	for id, token := range tr.Store.refreshTokens {
+		if token.ClientId == clientId && token.RevokedAt == nil {
+			token.RevokedAt = &revokedAt
+			tr.Store.refreshTokens[id] = token
+		}
+	}
+	return nil
+}
+
+func (tr *MemoryTokenRepository) CreatePasswordResetToken(token *models.PasswordResetToken) error {
+	tr.Store.mutex.Lock()
+	defer tr.Store.mutex.Unlock()
+
+	for _, other := range tr.Store.passwordResetTokens {
+		if other.TokenHash == token.TokenHash {
+			logger.Errorf("unable to create password reset token for client: %v, with error: the token already exists", token.ClientId)
+			return errors.New("unable to create the password reset token")
+		}
+	}
+
+	token.Id = tr.Store.nextId("password_reset_tokens")
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = tr.Store.now()
+	}
+	tr.Store.passwordResetTokens[token.Id] = copyPasswordResetToken(*token)
+	return nil
+}
+
+func (tr *MemoryTokenRepository) FindPasswordResetToken(tokenHash string) (*models.PasswordResetToken, error) {
+	tr.Store.mutex.RLock()
+	defer tr.Store.mutex.RUnlock()
+
+	for _, token := range tr.Store.passwordResetTokens {
+		if token.TokenHash == tokenHash {
+			token = copyPasswordResetToken(token)
+			return &token, nil
+		}
+	}
+	return nil, auth.ErrInvalidToken
+}
+
+func (tr *MemoryTokenRepository) UsePasswordResetToken(tokenId int) error {
+	tr.Store.mutex.Lock()
+	defer tr.Store.mutex.Unlock()
+
+	token, found := tr.Store.passwordResetTokens[tokenId]
+	if !found || token.UsedAt != nil {
+		return auth.ErrInvalidToken
+	}
+	usedAt := tr.Store.now()
+	token.UsedAt = &usedAt
+	tr.Store.passwordResetTokens[tokenId] = token
+	return nil
+}
+
+// copyRefreshToken gives the token its own revocation date, which is a
+// pointer.
+func copyRefreshToken(token models.RefreshToken) models.RefreshToken {
+	if token.RevokedAt != nil {
+		revokedAt := *token.RevokedAt
+		token.RevokedAt = &revokedAt
+	}
+	return token
+}
+
+// copyPasswordResetToken gives the token its own use date, which is a
+// pointer.
+func copyPasswordResetToken(token models.PasswordResetToken) models.PasswordResetToken {
+	if token.UsedAt != nil {
+		usedAt := *token.UsedAt
+		token.UsedAt = &usedAt
+	}
+	return token
+}
@@ -1,17 +1,37 @@
 package repository
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/emiliocc5/online-store-api/internal/models"
 	"github.com/emiliocc5/online-store-api/internal/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"math"
+	"time"
+)
+
+const (
+	AbandonedOrderReason = "the order was not paid in time"
+
+	// shippingWeightTolerance absorbs the rounding of summing weights in a
+	// different order.
+	shippingWeightTolerance = 1e-6
 )
 
 type (
 	OrderRepository interface {
-		CreateOrder(clientId, cartId int) error
-		GetProductsFromOrder(clientId, orderId int) (*[]models.Product, error)
+		CreateOrder(clientId int, shipping models.OrderShipping) (*models.Order, error)
+		GetOrderWithItems(clientId, orderId int) (*models.Order, error)
+		ListOrdersForClient(clientId, page, pageSize int) (*[]models.OrderSummary, int64, error)
+		FindOrderById(orderId int) (*models.Order, error)
+		FindOrderForClient(clientId, orderId int) (*models.Order, error)
+		TransitionOrder(orderId int, from, to models.OrderStatus, actor string) (*models.OrderTransition, error)
+		CancelOrder(orderId int, from models.OrderStatus, actor, reason string) (*models.Order, error)
+		ListOrderTransitions(orderId int) (*[]models.OrderTransition, error)
+		// ReleaseAbandonedOrders returns the ids of the orders it cancelled.
+		ReleaseAbandonedOrders(createdBefore time.Time) ([]int, error)
 	}
 	PgOrderRepository struct {
 		DbClient IOrderRepositoryDbClient
@@ -20,6 +40,9 @@ type (
 		First(dest interface{}, conds ...interface{}) (tx *gorm.DB)
 		Find(dest interface{}, conds ...interface{}) (tx *gorm.DB)
 		Create(value interface{}) (tx *gorm.DB)
+		Model(value interface{}) (tx *gorm.DB)
+		Where(query interface{}, args ...interface{}) (tx *gorm.DB)
+		Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) (err error)
 	}
 )
 
@@ -27,36 +50,95 @@ func init() {
 	logger = utils.GetLogger()
 }
 
-func (or *PgOrderRepository) CreateOrder(clientId, cartId int) error {
+func (or *PgOrderRepository) CreateOrder(clientId int, shipping models.OrderShipping) (*models.Order, error) {
 	if !or.isClientInDataBase(clientId) {
-		return errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+		return nil, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
 	}
 
 	clientCart := models.Cart{ClientId: clientId}
 	err := or.findCartForClientId(&clientCart, clientId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	var productsCarts []models.ProductCart
-	err = or.findListOfProductsForCartId(&productsCarts, clientCart.Id)
+	order := models.Order{ClientId: clientId, Status: models.OrderStatusPending, Shipping: shipping}
+	err = or.DbClient.Transaction(func(tx *gorm.DB) error {
+		return or.convertCartToOrder(tx, clientCart, &order)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if len(productsCarts) == 0 {
-		return errors.New("the cart has no products")
+	return &order, nil
+}
+
+func (or *PgOrderRepository) GetOrderWithItems(clientId, orderId int) (*models.Order, error) {
+	if !or.isClientInDataBase(clientId) {
+		return nil, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
 	}
 
-	err = or.createOrderForClientId(clientCart, clientId)
+	order, err := or.findOrderForClientId(clientId, orderId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	var orderItems []models.OrderItem
+	err = or.findListOfItemsForOrderId(&orderItems, order.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	or.findProductsFromOrderItemsList(&orderItems)
+	order.Items = orderItems
+
+	return &order, nil
+}
+
+func (or *PgOrderRepository) ListOrdersForClient(clientId, page, pageSize int) (*[]models.OrderSummary, int64, error) {
+	if !or.isClientInDataBase(clientId) {
+		return nil, 0, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+
+	var total int64
+	countResult := or.DbClient.Model(&models.Order{}).Where("client_id = ?", clientId).Count(&total)
+	if countResult.Error != nil {
+		logger.Errorf("unable to count orders for client: %v, with error: %v", clientId, countResult.Error)
+		return nil, 0, errors.New("unable to retrieve the list of orders")
+	}
+
+	var orders []models.Order
+	ordersResult := or.DbClient.Where("client_id = ?", clientId).
+		Order("created_at desc, id desc").
+		Limit(pageSize).
+		Offset((page - 1) * pageSize).
+		Find(&orders)
+	if ordersResult.Error != nil {
+		logger.Errorf("unable to list orders for client: %v, with error: %v", clientId, ordersResult.Error)
+		return nil, 0, errors.New("unable to retrieve the list of orders")
+	}
+
+	summaries := make([]models.OrderSummary, 0, len(orders))
+	for _, order := range orders {
+		itemCount, err := or.countItemsForOrderId(order.Id)
+		if err != nil {
+			return nil, 0, err
+		}
+		summaries = append(summaries, models.OrderSummary{Order: order, ItemCount: itemCount})
+	}
+
+	return &summaries, total, nil
+}
+
+func (or *PgOrderRepository) FindOrderById(orderId int) (*models.Order, error) {
+	order := models.Order{}
+	orderResult := or.DbClient.First(&order, "id = ?", orderId)
+	if orderResult.Error != nil {
+		return nil, errors.New(fmt.Sprintf("order: %v not found", orderId))
+	}
+	return &order, nil
 }
 
-func (or *PgOrderRepository) GetProductsFromOrder(clientId, orderId int) (*[]models.Product, error) {
+func (or *PgOrderRepository) FindOrderForClient(clientId, orderId int) (*models.Order, error) {
 	if !or.isClientInDataBase(clientId) {
 		return nil, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
 	}
@@ -65,23 +147,79 @@ func (or *PgOrderRepository) GetProductsFromOrder(clientId, orderId int) (*[]mod
 	if err != nil {
 		return nil, err
 	}
+	return &order, nil
+}
 
-	var productsCarts []models.ProductCart
-	err = or.findListOfProductsForCartId(&productsCarts, order.CartId)
+// TransitionOrder moves the order from one status to the next and records who
+// did it. The status is checked again under lock, so when two actors race on
+// the same order only the first one wins.
+func (or *PgOrderRepository) TransitionOrder(orderId int, from, to models.OrderStatus, actor string) (*models.OrderTransition, error) {
+	transition := models.OrderTransition{OrderId: orderId, FromStatus: from, ToStatus: to, Actor: actor}
+	err := or.DbClient.Transaction(func(tx *gorm.DB) error {
+		_, err := or.transitionOrder(tx, &transition, "")
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
+	return &transition, nil
+}
 
-	var productList []models.Product
-	or.findProductsFromProductCartsList(productsCarts, &productList)
+// CancelOrder moves the order to cancelled recording why, which gives back its
+// reserved stock and flags it for refund when it was already paid.
+func (or *PgOrderRepository) CancelOrder(orderId int, from models.OrderStatus, actor, reason string) (*models.Order, error) {
+	transition := models.OrderTransition{OrderId: orderId, FromStatus: from, ToStatus: models.OrderStatusCancelled, Actor: actor}
+	var order *models.Order
+	err := or.DbClient.Transaction(func(tx *gorm.DB) error {
+		var err error
+		order, err = or.transitionOrder(tx, &transition, reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (or *PgOrderRepository) ListOrderTransitions(orderId int) (*[]models.OrderTransition, error) {
+	var transitions []models.OrderTransition
+	transitionsResult := or.DbClient.Find(&transitions, "order_id = ?", orderId)
+	if transitionsResult.Error != nil {
+		logger.Errorf("unable to list transitions of order: %v, with error: %v", orderId, transitionsResult.Error)
+		return nil, errors.New(fmt.Sprintf("unable to retrieve the history of the order: %v", orderId))
+	}
+	return &transitions, nil
+}
+
+// ReleaseAbandonedOrders cancels the pending orders placed before createdBefore,
+// which gives their reserved stock back. Each order is handled in its own
+// transaction so a failure leaves the others untouched.
+func (or *PgOrderRepository) ReleaseAbandonedOrders(createdBefore time.Time) ([]int, error) {
+	var orders []models.Order
+	ordersResult := or.DbClient.Find(&orders, "status = ? AND created_at < ?", models.OrderStatusPending, createdBefore)
+	if ordersResult.Error != nil {
+		logger.Errorf("unable to list abandoned orders with error: %v", ordersResult.Error)
+		return nil, errors.New("unable to retrieve the list of abandoned orders")
+	}
 
-	return &productList, nil
+	released := []int{}
+	for _, order := range orders {
+		_, err := or.CancelOrder(order.Id, models.OrderStatusPending, models.SystemActor, AbandonedOrderReason)
+		if errors.Is(err, models.ErrInvalidOrderTransition) {
+			continue
+		}
+		if err != nil {
+			return released, err
+		}
+		released = append(released, order.Id)
+	}
+	return released, nil
 }
 
 func (or *PgOrderRepository) isClientInDataBase(clientId int) bool {
 	client := models.Client{}
 	findClientResult := or.DbClient.Find(&client, "id = ?", clientId)
-	return findClientResult.Error == nil
+	return findClientResult.Error == nil && findClientResult.RowsAffected > 0
 }
 
 func (or *PgOrderRepository) findCartForClientId(clientCart *models.Cart, clientId int) error {
@@ -92,35 +230,208 @@ func (or *PgOrderRepository) findCartForClientId(clientCart *models.Cart, client
 	return nil
 }
 
-func (or *PgOrderRepository) createOrderForClientId(cart models.Cart, clientId int) error {
-	order := models.Order{
-		Cart:     cart,
-		CartId:   cart.Id,
-		ClientId: clientId,
+// convertCartToOrder locks the cart, copies its products into order items and
+// empties it, so products added afterwards never leak into the placed order.
+func (or *PgOrderRepository) convertCartToOrder(tx *gorm.DB, cart models.Cart, order *models.Order) error {
+	lockResult := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&cart, "id = ?", cart.Id)
+	if lockResult.Error != nil {
+		logger.Errorf("unable to lock cart: %v, with error: %v", cart.Id, lockResult.Error)
+		return errors.New(fmt.Sprintf("cart for client id: %v not found", cart.ClientId))
+	}
+
+	var productsCarts []models.ProductCart
+	productsResult := tx.Find(&productsCarts, "cart_id = ?", cart.Id)
+	if productsResult.Error != nil {
+		return errors.New("unable to retrieve the list of products")
 	}
-	createOrderResult := or.DbClient.Create(&order)
+	if len(productsCarts) == 0 {
+		return errors.New("the cart has no products")
+	}
+
+	shippable := false
+	weight := 0.0
+	orderItems := make([]models.OrderItem, 0, len(productsCarts))
+	for _, e := range productsCarts {
+		product := models.Product{}
+		productResult := tx.First(&product, "id = ?", e.ProductId)
+		if productResult.Error != nil || product.IsArchived() {
+			return errors.New(fmt.Sprintf("the product: %v is no longer available", e.ProductId))
+		}
+		if product.Type.IsShippable() {
+			shippable = true
+			weight += product.Weight * float64(e.Quantity)
+		}
+
+		total, err := order.Total.Add(product.Price.Multiply(e.Quantity))
+		if err != nil {
+			return errors.New("the cart contains products priced in different currencies")
+		}
+		order.Total = total
+		orderItems = append(orderItems, models.OrderItem{
+			ProductId:     e.ProductId,
+			UnitPrice:     product.Price,
+			Quantity:      e.Quantity,
+			StockReserved: product.Type.TracksStock(),
+		})
+	}
+
+	err := addShippingCost(order, shippable, weight)
+	if err != nil {
+		return err
+	}
+
+	untracked, err := reserveStock(tx, reservedQuantities(orderItems))
+	if err != nil {
+		return err
+	}
+	skipUntrackedStock(orderItems, untracked)
+
+	createOrderResult := tx.Create(order)
 	if createOrderResult.Error != nil {
-		return errors.New(fmt.Sprintf("Unable to create order for clientId: %v", clientId))
+		logger.Errorf("unable to create order with error: %v", createOrderResult.Error)
+		return errors.New(fmt.Sprintf("Unable to create order for clientId: %v", order.ClientId))
+	}
+
+	for i := range orderItems {
+		orderItems[i].OrderId = order.Id
+	}
+	createItemsResult := tx.Create(&orderItems)
+	if createItemsResult.Error != nil {
+		logger.Errorf("unable to create order items with error: %v", createItemsResult.Error)
+		return errors.New(fmt.Sprintf("Unable to create order for clientId: %v", order.ClientId))
+	}
+	order.Items = orderItems
+
+	clearCartResult := tx.Delete(&productsCarts)
+	if clearCartResult.Error != nil {
+		logger.Errorf("unable to clear cart: %v, with error: %v", cart.Id, clearCartResult.Error)
+		return errors.New(fmt.Sprintf("Unable to create order for clientId: %v", order.ClientId))
 	}
+
 	return nil
 }
 
-func (or *PgOrderRepository) findListOfProductsForCartId(productCarts *[]models.ProductCart, clientCartId int) error {
-	productsResult := or.DbClient.Find(productCarts, "cart_id = ?", clientCartId)
-	if productsResult.Error != nil {
+// addShippingCost adds the chosen shipping to the total. The cart may have
+// changed since the option was priced, so the quote is only taken when it was
+// made for the weight being ordered; otherwise the client has to price the
+// shipping again.
+func addShippingCost(order *models.Order, shippable bool, weight float64) error {
+	if !shippable {
+		order.Shipping = models.OrderShipping{}
+		return nil
+	}
+	if order.Shipping.IsEmpty() {
+		return errors.New("a shipping address and option are required for carts with physical products")
+	}
+	if math.Abs(order.Shipping.Weight-weight) > shippingWeightTolerance {
+		return fmt.Errorf("%w: it was priced for %v kg but %v kg are being ordered", models.ErrStaleShippingQuote,
+			order.Shipping.Weight, weight)
+	}
+
+	total, err := order.Total.Add(order.Shipping.Cost)
+	if err != nil {
+		return errors.New("the shipping cost is not available in the currency of the order")
+	}
+	order.Total = total
+	return nil
+}
+
+func (or *PgOrderRepository) transitionOrder(tx *gorm.DB, transition *models.OrderTransition, reason string) (*models.Order, error) {
+	order := models.Order{}
+	orderResult := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", transition.OrderId)
+	if orderResult.Error != nil {
+		return nil, errors.New(fmt.Sprintf("order: %v not found", transition.OrderId))
+	}
+	if order.Status != transition.FromStatus {
+		return nil, models.NewInvalidOrderTransitionError(order.Status, transition.ToStatus)
+	}
+
+	var orderItems []models.OrderItem
+	itemsResult := tx.Find(&orderItems, "order_id = ?", order.Id)
+	if itemsResult.Error != nil {
+		return nil, errors.New("unable to retrieve the list of products")
+	}
+
+	var err error
+	switch transition.ToStatus {
+	case models.OrderStatusCancelled:
+		err = releaseStock(tx, reservedQuantities(orderItems))
+		order.CancellationReason = reason
+		order.RefundPending = order.Status.HasCollectedPayment()
+	case models.OrderStatusShipped:
+		err = commitStock(tx, reservedQuantities(orderItems))
+	case models.OrderStatusRefunded:
+		order.RefundPending = false
+	}
+	if err != nil {
+		return nil, err
+	}
+	order.Status = transition.ToStatus
+
+	statusResult := tx.Model(&order).Updates(map[string]interface{}{
+		"status":              order.Status,
+		"cancellation_reason": order.CancellationReason,
+		"refund_pending":      order.RefundPending,
+	})
+	if statusResult.Error != nil {
+		logger.Errorf("unable to update status of order: %v, with error: %v", order.Id, statusResult.Error)
+		return nil, errors.New(fmt.Sprintf("unable to update the status of the order: %v", order.Id))
+	}
+
+	createResult := tx.Create(transition)
+	if createResult.Error != nil {
+		logger.Errorf("unable to record transition of order: %v, with error: %v", order.Id, createResult.Error)
+		return nil, errors.New(fmt.Sprintf("unable to update the status of the order: %v", order.Id))
+	}
+	return &order, nil
+}
+
+// skipUntrackedStock clears the reservation of the items whose product stock
+// is not tracked, so cancelling or shipping them leaves the ledger alone even
+// if tracking starts in between.
+func skipUntrackedStock(orderItems []models.OrderItem, untracked map[int]bool) {
+	for i := range orderItems {
+		if untracked[orderItems[i].ProductId] {
+			orderItems[i].StockReserved = false
+		}
+	}
+}
+
+func reservedQuantities(orderItems []models.OrderItem) map[int]int {
+	quantities := make(map[int]int)
+	for _, e := range orderItems {
+		if e.StockReserved {
+			quantities[e.ProductId] += e.Quantity
+		}
+	}
+	return quantities
+}
+
+func (or *PgOrderRepository) findListOfItemsForOrderId(orderItems *[]models.OrderItem, orderId int) error {
+	itemsResult := or.DbClient.Find(orderItems, "order_id = ?", orderId)
+	if itemsResult.Error != nil {
 		return errors.New("unable to retrieve the list of products")
 	}
 	return nil
 }
 
-func (or *PgOrderRepository) findProductsFromProductCartsList(productCarts []models.ProductCart, productList *[]models.Product) {
-	for _, e := range productCarts {
-		product := models.Product{}
-		err := or.findProductById(&product, e.ProductId)
+func (or *PgOrderRepository) countItemsForOrderId(orderId int) (int, error) {
+	var count int64
+	countResult := or.DbClient.Model(&models.OrderItem{}).
+		Where("order_id = ?", orderId).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&count)
+	if countResult.Error != nil {
+		return 0, errors.New("unable to count the products of the order")
+	}
+	return int(count), nil
+}
+
+func (or *PgOrderRepository) findProductsFromOrderItemsList(orderItems *[]models.OrderItem) {
+	for i, e := range *orderItems {
+		err := or.findProductById(&(*orderItems)[i].Product, e.ProductId)
 		if err != nil {
 			logger.Error(fmt.Sprintf("unable to get the product: %v", err.Error()))
-		} else {
-			*productList = append(*productList, product)
 		}
 	}
 }
@@ -135,9 +446,9 @@ func (or *PgOrderRepository) findProductById(product *models.Product, productId
 
 func (or *PgOrderRepository) findOrderForClientId(clientId, orderId int) (models.Order, error) {
 	order := models.Order{}
-	orderResult := or.DbClient.Find(&order, "client_id = ?", clientId, "order_id = ?", orderId)
+	orderResult := or.DbClient.First(&order, "id = ? AND client_id = ?", orderId, clientId)
 	if orderResult.Error != nil {
-		return order, errors.New(fmt.Sprintf("order for clientId: %v not found", clientId))
+		return order, errors.New(fmt.Sprintf("order: %v for clientId: %v not found", orderId, clientId))
 	}
 	return order, nil
 }
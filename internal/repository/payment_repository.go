@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"gorm.io/gorm"
+)
+
+type (
+	PaymentRepository interface {
+		FindPaymentByOrderId(orderId int) (*models.Payment, error)
+		SavePayment(payment *models.Payment) error
+	}
+	PgPaymentRepository struct {
+		DbClient IPaymentRepositoryDbClient
+	}
+	IPaymentRepositoryDbClient interface {
+		Find(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Save(value interface{}) (tx *gorm.DB)
+	}
+)
+
+// FindPaymentByOrderId returns nil without error when the order was never
+// charged.
+func (pr *PgPaymentRepository) FindPaymentByOrderId(orderId int) (*models.Payment, error) {
+	payment := models.Payment{}
+	paymentResult := pr.DbClient.Find(&payment, "order_id = ?", orderId)
+	if paymentResult.Error != nil {
+		logger.Errorf("unable to get payment of order: %v, with error: %v", orderId, paymentResult.Error)
+		return nil, errors.New(fmt.Sprintf("unable to get the payment of the order: %v", orderId))
+	}
+	if paymentResult.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &payment, nil
+}
+
+func (pr *PgPaymentRepository) SavePayment(payment *models.Payment) error {
+	saveResult := pr.DbClient.Save(payment)
+	if saveResult.Error != nil {
+		logger.Errorf("unable to save payment of order: %v, with error: %v", payment.OrderId, saveResult.Error)
+		return errors.New(fmt.Sprintf("unable to save the payment of the order: %v", payment.OrderId))
+	}
+	return nil
+}
@@ -1,38 +1,43 @@
 package repository
 
 import (
+	"database/sql"
 	"errors"
-	"fmt"
 	"github.com/emiliocc5/online-store-api/internal/models"
 	"gorm.io/gorm"
+	"strings"
+)
+
+var (
+	productSortColumns = map[string]string{
+		models.ProductSortById:        "id asc",
+		models.ProductSortByLabel:     "label asc, id asc",
+		models.ProductSortByLabelDesc: "label desc, id asc",
+		models.ProductSortByPrice:     "price_amount asc, id asc",
+		models.ProductSortByPriceDesc: "price_amount desc, id asc",
+	}
+	likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
 )
 
 type (
 	ProductRepository interface {
-		FindProductsFromCart(cartId int) (*[]models.Product, error)
 		FindProductById(productId int) (*models.Product, error)
+		ListProducts(filter models.ProductFilter) (*[]models.Product, int64, error)
+		CreateProduct(product *models.Product) error
+		UpdateProduct(product *models.Product) error
 	}
 	PgProductRepository struct {
 		DbClient IProductRepositoryDbClient
 	}
 	IProductRepositoryDbClient interface {
 		Find(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Model(value interface{}) (tx *gorm.DB)
+		Create(value interface{}) (tx *gorm.DB)
+		Save(value interface{}) (tx *gorm.DB)
+		Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) (err error)
 	}
 )
 
-func (pr *PgProductRepository) FindProductsFromCart(cartId int) (*[]models.Product, error) {
-	var productsCarts []models.ProductCart
-	err := pr.findListOfProductsForCartId(&productsCarts, cartId)
-	if err != nil {
-		return nil, err
-	}
-
-	var productList []models.Product
-	pr.findProductsFromProductCartsList(productsCarts, &productList)
-
-	return &productList, nil
-}
-
 func (pr *PgProductRepository) FindProductById(productId int) (*models.Product, error) {
 	product := models.Product{}
 	productResult := pr.DbClient.Find(&product, "id = ?", productId)
@@ -40,24 +45,92 @@ func (pr *PgProductRepository) FindProductById(productId int) (*models.Product,
 		logger.Errorf("unable to find the product with error: %v", productResult.Error)
 		return nil, errors.New("unable to find the product in our db")
 	}
+	if productResult.RowsAffected == 0 {
+		return nil, errors.New("unable to find the product in our db")
+	}
+
+	if product.CategoryId != 0 {
+		categoryResult := pr.DbClient.Find(&product.Category, "id = ?", product.CategoryId)
+		if categoryResult.Error != nil {
+			logger.Errorf("unable to find the category of the product with error: %v", categoryResult.Error)
+		}
+	}
+
 	return &product, nil
 }
 
-func (pr *PgProductRepository) findListOfProductsForCartId(productCarts *[]models.ProductCart, clientCartId int) error {
-	productsResult := pr.DbClient.Find(productCarts, "cart_id = ?", clientCartId)
+func (pr *PgProductRepository) ListProducts(filter models.ProductFilter) (*[]models.Product, int64, error) {
+	var total int64
+	countResult := pr.DbClient.Model(&models.Product{}).Scopes(productFilterScope(filter)).Count(&total)
+	if countResult.Error != nil {
+		logger.Errorf("unable to count products with error: %v", countResult.Error)
+		return nil, 0, errors.New("unable to retrieve the list of products")
+	}
+
+	sort, ok := productSortColumns[filter.Sort]
+	if !ok {
+		sort = productSortColumns[models.ProductSortById]
+	}
+
+	var products []models.Product
+	productsResult := pr.DbClient.Model(&models.Product{}).
+		Scopes(productFilterScope(filter)).
+		Preload("Category").
+		Order(sort).
+		Limit(filter.PageSize).
+		Offset((filter.Page - 1) * filter.PageSize).
+		Find(&products)
 	if productsResult.Error != nil {
-		return errors.New("unable to retrieve the list of products")
+		logger.Errorf("unable to list products with error: %v", productsResult.Error)
+		return nil, 0, errors.New("unable to retrieve the list of products")
+	}
+
+	return &products, total, nil
+}
+
+// CreateProduct saves the product along with an empty stock ledger when its
+// type tracks stock.
+func (pr *PgProductRepository) CreateProduct(product *models.Product) error {
+	err := pr.DbClient.Transaction(func(tx *gorm.DB) error {
+		createResult := tx.Create(product)
+		if createResult.Error != nil {
+			return createResult.Error
+		}
+		if !product.Type.TracksStock() {
+			return nil
+		}
+		return tx.Create(&models.Stock{ProductId: product.Id}).Error
+	})
+	if err != nil {
+		logger.Errorf("unable to create product with error: %v", err)
+		return errors.New("unable to create the product")
+	}
+	return nil
+}
+
+func (pr *PgProductRepository) UpdateProduct(product *models.Product) error {
+	toSave := *product
+	toSave.Category = models.Category{}
+	saveResult := pr.DbClient.Save(&toSave)
+	if saveResult.Error != nil {
+		logger.Errorf("unable to update product: %v, with error: %v", product.Id, saveResult.Error)
+		return errors.New("unable to update the product")
 	}
 	return nil
 }
 
-func (pr *PgProductRepository) findProductsFromProductCartsList(productCarts []models.ProductCart, productList *[]models.Product) {
-	for _, e := range productCarts {
-		product, err := pr.FindProductById(e.ProductId)
-		if err != nil {
-			logger.Error(fmt.Sprintf("unable to get the product: %v", err.Error()))
-		} else {
-			*productList = append(*productList, *product)
+func productFilterScope(filter models.ProductFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("archived_at IS NULL")
+		if len(filter.CategoryIds) > 0 {
+			db = db.Where("category_id IN ?", filter.CategoryIds)
+		}
+		if filter.Type != nil {
+			db = db.Where("type = ?", *filter.Type)
+		}
+		if filter.Label != "" {
+			db = db.Where(`LOWER(label) LIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(strings.ToLower(filter.Label))+"%")
 		}
+		return db
 	}
 }
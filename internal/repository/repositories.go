@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/config"
+	"gorm.io/gorm"
+)
+
+// Repositories groups the storage the application is built on, so it can be
+// swapped as a whole.
+type Repositories struct {
+	Address  AddressRepository
+	ApiKey   ApiKeyRepository
+	Cart     CartRepository
+	Category CategoryRepository
+	Client   ClientRepository
+	Download DownloadRepository
+	Order    OrderRepository
+	Payment  PaymentRepository
+	Product  ProductRepository
+	Stock    StockRepository
+	Token    TokenRepository
+}
+
+// NewGormRepositories returns the repositories backed by a gorm database,
+// either Postgres or SQLite.
+func NewGormRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Address:  &PgAddressRepository{DbClient: db},
+		ApiKey:   &PgApiKeyRepository{DbClient: db},
+		Cart:     &PgCartRepository{DbClient: db},
+		Category: &PgCategoryRepository{DbClient: db},
+		Client:   &PgClientRepository{DbClient: db},
+		Download: &PgDownloadRepository{DbClient: db},
+		Order:    &PgOrderRepository{DbClient: db},
+		Payment:  &PgPaymentRepository{DbClient: db},
+		Product:  &PgProductRepository{DbClient: db},
+		Stock:    &PgStockRepository{DbClient: db},
+		Token:    &PgTokenRepository{DbClient: db},
+	}
+}
+
+// Open returns the repositories of the configured driver, connecting to the
+// database when there is one.
+func Open(cfg config.DatabaseConfig, migrate bool) (Repositories, error) {
+	switch cfg.Driver {
+	case config.DriverMemory:
+		logger.Warn("using the memory storage, all data will be lost on restart")
+		return NewMemoryRepositories(NewMemoryStore()), nil
+	case config.DriverPostgres, config.DriverSqlite:
+		db, err := Connect(cfg, migrate)
+		if err != nil {
+			return Repositories{}, err
+		}
+		return NewGormRepositories(db), nil
+	}
+	return Repositories{}, errors.New(fmt.Sprintf("unsupported database driver: %v", cfg.Driver))
+}
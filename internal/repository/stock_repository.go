@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"sort"
+)
+
+type (
+	StockRepository interface {
+		GetStock(productId int) (*models.Stock, error)
+		SetOnHand(productId, onHand int) (*models.Stock, error)
+	}
+	PgStockRepository struct {
+		DbClient IStockRepositoryDbClient
+	}
+	IStockRepositoryDbClient interface {
+		Find(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) (err error)
+	}
+)
+
+// GetStock returns the ledger of the product, or nil when its stock is not
+// tracked.
+func (sr *PgStockRepository) GetStock(productId int) (*models.Stock, error) {
+	stock := models.Stock{ProductId: productId}
+	stockResult := sr.DbClient.Find(&stock, "product_id = ?", productId)
+	if stockResult.Error != nil {
+		logger.Errorf("unable to get stock for product: %v, with error: %v", productId, stockResult.Error)
+		return nil, errors.New(fmt.Sprintf("unable to get the stock of the product: %v", productId))
+	}
+	if stockResult.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &stock, nil
+}
+
+func (sr *PgStockRepository) SetOnHand(productId, onHand int) (*models.Stock, error) {
+	stock := models.Stock{ProductId: productId}
+	err := sr.DbClient.Transaction(func(tx *gorm.DB) error {
+		stockResult := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&stock, "product_id = ?", productId)
+		if stockResult.Error != nil {
+			logger.Errorf("unable to lock stock for product: %v, with error: %v", productId, stockResult.Error)
+			return errors.New(fmt.Sprintf("unable to update the stock of the product: %v", productId))
+		}
+		if onHand < stock.Reserved {
+			return errors.New(fmt.Sprintf("on hand cannot be lower than the %v units reserved by placed orders", stock.Reserved))
+		}
+
+		stock.OnHand = onHand
+		saveResult := tx.Save(&stock)
+		if saveResult.Error != nil {
+			logger.Errorf("unable to save stock for product: %v, with error: %v", productId, saveResult.Error)
+			return errors.New(fmt.Sprintf("unable to update the stock of the product: %v", productId))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+// reserveStock holds units of every product in quantities for an order and
+// returns the products whose stock is not tracked, which are sold without
+// limit. Rows are locked in product id order so concurrent checkouts sharing
+// products cannot deadlock each other.
+func reserveStock(tx *gorm.DB, quantities map[int]int) (map[int]bool, error) {
+	untracked := make(map[int]bool)
+	for _, productId := range sortedProductIds(quantities) {
+		stock := models.Stock{ProductId: productId}
+		stockResult := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Find(&stock, "product_id = ?", productId)
+		if stockResult.Error != nil {
+			logger.Errorf("unable to lock stock for product: %v, with error: %v", productId, stockResult.Error)
+			return nil, errors.New(fmt.Sprintf("unable to reserve stock for the product: %v", productId))
+		}
+		if stockResult.RowsAffected == 0 {
+			untracked[productId] = true
+			continue
+		}
+		if stock.Available() < quantities[productId] {
+			return nil, models.NewInsufficientStockError(productId, stock.Available())
+		}
+
+		stock.Reserved += quantities[productId]
+		saveResult := tx.Save(&stock)
+		if saveResult.Error != nil {
+			logger.Errorf("unable to reserve stock for product: %v, with error: %v", productId, saveResult.Error)
+			return nil, errors.New(fmt.Sprintf("unable to reserve stock for the product: %v", productId))
+		}
+	}
+	return untracked, nil
+}
+
+// releaseStock gives back units reserved by reserveStock.
+func releaseStock(tx *gorm.DB, quantities map[int]int) error {
+	for _, productId := range sortedProductIds(quantities) {
+		releaseResult := tx.Exec("UPDATE stocks SET reserved = reserved - ? WHERE product_id = ?", quantities[productId], productId)
+		if releaseResult.Error != nil {
+			logger.Errorf("unable to release stock for product: %v, with error: %v", productId, releaseResult.Error)
+			return errors.New(fmt.Sprintf("unable to release stock for the product: %v", productId))
+		}
+	}
+	return nil
+}
+
+// commitStock takes reserved units out of the warehouse once they are shipped.
+func commitStock(tx *gorm.DB, quantities map[int]int) error {
+	for _, productId := range sortedProductIds(quantities) {
+		commitResult := tx.Exec("UPDATE stocks SET on_hand = on_hand - ?, reserved = reserved - ? WHERE product_id = ?",
+			quantities[productId], quantities[productId], productId)
+		if commitResult.Error != nil {
+			logger.Errorf("unable to commit stock for product: %v, with error: %v", productId, commitResult.Error)
+			return errors.New(fmt.Sprintf("unable to commit stock for the product: %v", productId))
+		}
+	}
+	return nil
+}
+
+func sortedProductIds(quantities map[int]int) []int {
+	productIds := make([]int, 0, len(quantities))
+	for productId := range quantities {
+		productIds = append(productIds, productId)
+	}
+	sort.Ints(productIds)
+	return productIds
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+type (
+	TokenRepository interface {
+		CreateRefreshToken(token *models.RefreshToken) error
+		FindRefreshToken(tokenHash string) (*models.RefreshToken, error)
+		// RevokeRefreshToken fails with auth.ErrInvalidToken when the token was
+		// already revoked, so a token cannot be redeemed twice.
+		RevokeRefreshToken(tokenId int) error
+		// RevokeClientRefreshTokens signs the client out of every session.
+		RevokeClientRefreshTokens(clientId int) error
+		CreatePasswordResetToken(token *models.PasswordResetToken) error
+		FindPasswordResetToken(tokenHash string) (*models.PasswordResetToken, error)
+		// UsePasswordResetToken fails with auth.ErrInvalidToken when the token
+		// was already used, so a token cannot be redeemed twice.
+		UsePasswordResetToken(tokenId int) error
+	}
+	PgTokenRepository struct {
+		DbClient ITokenRepositoryDbClient
+	}
+	ITokenRepositoryDbClient interface {
+		First(dest interface{}, conds ...interface{}) (tx *gorm.DB)
+		Create(value interface{}) (tx *gorm.DB)
+		Model(value interface{}) (tx *gorm.DB)
+	}
+)
+
+func (tr *PgTokenRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	createResult := tr.DbClient.Create(token)
+	if createResult.Error != nil {
+		logger.Errorf("unable to create refresh token for client: %v, with error: %v", token.ClientId, createResult.Error)
+		return errors.New("unable to create the refresh token")
+	}
+	return nil
+}
+
+func (tr *PgTokenRepository) FindRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	token := models.RefreshToken{}
+	tokenResult := tr.DbClient.First(&token, "token_hash = ?", tokenHash)
+	if tokenResult.Error != nil {
+		return nil, auth.ErrInvalidToken
+	}
+	return &token, nil
+}
+
+func (tr *PgTokenRepository) RevokeRefreshToken(tokenId int) error {
+	revokeResult := tr.DbClient.Model(&models.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", tokenId).
+		Update("revoked_at", time.Now())
+	if revokeResult.Error != nil {
+		logger.Errorf("unable to revoke refresh token: %v, with error: %v", tokenId, revokeResult.Error)
+		return errors.New("unable to revoke the refresh token")
+	}
+	if revokeResult.RowsAffected == 0 {
+		return auth.ErrInvalidToken
+	}
+	return nil
+}
+
+func (tr *PgTokenRepository) RevokeClientRefreshTokens(clientId int) error {
+	revokeResult := tr.DbClient.Model(&models.RefreshToken{}).
+		Where("client_id = ? AND revoked_at IS NULL", clientId).
+		Update("revoked_at", time.Now())
+	if revokeResult.Error != nil {
+		logger.Errorf("unable to revoke refresh tokens of client: %v, with error: %v", clientId, revokeResult.Error)
+		return errors.New("unable to revoke the refresh tokens")
+	}
+	return nil
+}
+
+func (tr *PgTokenRepository) CreatePasswordResetToken(token *models.PasswordResetToken) error {
+	createResult := tr.DbClient.Create(token)
+	if createResult.Error != nil {
+		logger.Errorf("unable to create password reset token for client: %v, with error: %v", token.ClientId, createResult.Error)
+		return errors.New("unable to create the password reset token")
+	}
+	return nil
+}
+
+func (tr *PgTokenRepository) FindPasswordResetToken(tokenHash string) (*models.PasswordResetToken, error) {
+	token := models.PasswordResetToken{}
+	tokenResult := tr.DbClient.First(&token, "token_hash = ?", tokenHash)
+	if tokenResult.Error != nil {
+		return nil, auth.ErrInvalidToken
+	}
+	return &token, nil
+}
+
+func (tr *PgTokenRepository) UsePasswordResetToken(tokenId int) error {
+	useResult := tr.DbClient.Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", tokenId).
+		Update("used_at", time.Now())
+	if useResult.Error != nil {
+		logger.Errorf("unable to use password reset token: %v, with error: %v", tokenId, useResult.Error)
+		return errors.New("unable to use the password reset token")
+	}
+	if useResult.RowsAffected == 0 {
+		return auth.ErrInvalidToken
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+const (
+	ApiKeyHeader = "X-API-Key"
+	// ApiKeyPrefixKey is where RequireScope leaves the prefix of the key in the
+	// gin context.
+	ApiKeyPrefixKey = handler.ApiKeyPrefixKey
+)
+
+// RequireScope lets through requests carrying an active API key that was
+// granted scope. Unknown or revoked keys get 401 and keys without the scope
+// 403.
+func RequireScope(apiKeys services.ApiKeyService, scope models.Scope) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		key := context.GetHeader(ApiKeyHeader)
+		if key == "" {
+			context.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse{Error: "missing api key"})
+			return
+		}
+
+		apiKey, err := apiKeys.Authenticate(key)
+		if errors.Is(err, auth.ErrInvalidApiKey) {
+			context.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse{Error: err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorf("unable to authenticate api key, with error: %v", err)
+			context.AbortWithStatusJSON(http.StatusInternalServerError, response.ErrorResponse{Error: "unable to authenticate the api key"})
+			return
+		}
+		if !apiKey.HasScope(scope) {
+			context.AbortWithStatusJSON(http.StatusForbidden, response.ErrorResponse{Error: fmt.Sprintf("%v: %v", auth.ErrMissingScope, scope)})
+			return
+		}
+
+		context.Set(ApiKeyPrefixKey, apiKey.Prefix)
+	}
+}
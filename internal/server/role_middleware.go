@@ -0,0 +1,44 @@
+package server
+
+import (
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// Authorize lets through signed-in clients whose role grants scope. It must
+// run after handler.Authenticate.
+func Authorize(scope models.Scope) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		role, _ := context.MustGet(handler.RoleKey).(models.Role)
+		if !role.Grants(scope) {
+			context.AbortWithStatusJSON(http.StatusForbidden, response.ErrorResponse{
+				Error: fmt.Sprintf("%v: the role %v lacks %v", auth.ErrMissingScope, role, scope),
+			})
+		}
+	}
+}
+
+// RequirePermission is for routes shared by back-office systems and staff: it
+// accepts an API key granted scope as well as a signed-in client whose role
+// grants it.
+func RequirePermission(apiKeys services.ApiKeyService, tokens *auth.TokenIssuer, scope models.Scope) gin.HandlerFunc {
+	byApiKey := RequireScope(apiKeys, scope)
+	byRole := []gin.HandlerFunc{handler.Authenticate(tokens), Authorize(scope)}
+	return func(context *gin.Context) {
+		if context.GetHeader(ApiKeyHeader) != "" {
+			byApiKey(context)
+			return
+		}
+		for _, e := range byRole {
+			if e(context); context.IsAborted() {
+				return
+			}
+		}
+	}
+}
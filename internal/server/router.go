@@ -1,50 +1,151 @@
 package server
 
 import (
-	"fmt"
-	"github.com/emiliocc5/online-store-api/internal/repository"
-	"github.com/emiliocc5/online-store-api/internal/services"
-	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/emiliocc5/online-store-api/internal/app"
+	"github.com/emiliocc5/online-store-api/internal/models"
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 )
 
 const (
 	BaseEndpoint = "/api"
 	Cart         = "/cart"
-	AddProduct   = "/products/:productId"
+	CartProduct  = "/products/:productId"
+	Orders       = "/orders"
+	OrderId      = "/:orderId"
+	Products     = "/products"
+	ProductId    = "/:productId"
+	Categories   = "/categories"
+	CategoryId   = "/:categoryId"
+	Admin        = "/admin"
+	Stock        = "/stock"
+	Transitions  = "/transitions"
+	Cancel       = "/cancel"
+	Payment      = "/payment"
+	Confirm      = "/confirm"
+	Quotes       = "/shipping-quotes"
+	Downloads    = "/downloads"
+	Clients      = "/clients"
+	Me           = "/me"
+	Addresses    = "/addresses"
+	AddressId    = "/:addressId"
+	Auth         = "/auth"
+	Login        = "/login"
+	Refresh      = "/refresh"
+	Logout       = "/logout"
+	ApiKeys      = "/api-keys"
+	ApiKeyId     = "/:apiKeyId"
+	ClientId     = "/:clientId"
+	Role         = "/role"
+	Password     = "/password-reset"
 )
 
-var (
-	logger      *logrus.Logger
-	cartHandler handler.CartHandler
-)
+func ConfigureRouter(engine *gin.Engine, a *app.App) {
+	configureCartRoutes(engine, a)
+	configureOrderRoutes(engine, a)
+	configureProductRoutes(engine, a)
+	configureCategoryRoutes(engine, a)
+	configureAdminProductRoutes(engine, a)
+	configureDownloadRoutes(engine, a)
+	configureClientRoutes(engine, a)
+	configureSupportRoutes(engine, a)
+	configureAuthRoutes(engine, a)
+	configureApiKeyRoutes(engine, a)
+}
+
+func configureCartRoutes(engine *gin.Engine, a *app.App) {
+	cart := engine.Group(BaseEndpoint+Cart, a.Authenticate, Authorize(models.ScopeShopping))
+	cart.GET("", a.CartHandler.HandleGetCart)
+	cart.POST(CartProduct, a.CartHandler.HandleAddProduct)
+	cart.PUT(CartProduct, a.CartHandler.HandleSetProductQuantity)
+	cart.DELETE(CartProduct, a.CartHandler.HandleRemoveProduct)
+	cart.DELETE("", a.CartHandler.HandleClearCart)
+	cart.GET(Quotes, a.CartHandler.HandleGetShippingQuotes)
+}
+
+func configureOrderRoutes(engine *gin.Engine, a *app.App) {
+	orders := engine.Group(BaseEndpoint+Orders, a.Authenticate, Authorize(models.ScopeShopping))
+	orders.POST("", a.OrderHandler.HandleCreateOrder)
+	orders.GET("", a.OrderHandler.HandleListOrders)
+	orders.GET(OrderId, a.OrderHandler.HandleGetOrder)
+	orders.POST(OrderId+Cancel, a.OrderHandler.HandleCancelOrder)
+	orders.POST(OrderId+Payment, a.OrderHandler.HandlePayOrder)
+	orders.POST(OrderId+Payment+Confirm, a.OrderHandler.HandleConfirmPayment)
+	orders.GET(OrderId+Downloads+ProductId, a.DownloadHandler.HandleIssueDownloadLink)
+
+	engine.POST(BaseEndpoint+Orders+OrderId+Transitions, requirePermission(a, models.ScopeOrdersWrite), a.OrderHandler.HandleTransitionOrder)
+	engine.GET(BaseEndpoint+Orders+OrderId+Transitions, requirePermission(a, models.ScopeOrdersRead), a.OrderHandler.HandleListOrderTransitions)
+}
+
+func configureProductRoutes(engine *gin.Engine, a *app.App) {
+	engine.GET(BaseEndpoint+Products, a.ProductHandler.HandleListProducts)
+	engine.GET(BaseEndpoint+Products+ProductId, a.ProductHandler.HandleGetProduct)
+}
+
+func configureCategoryRoutes(engine *gin.Engine, a *app.App) {
+	engine.GET(BaseEndpoint+Categories, a.CategoryHandler.HandleListCategories)
+	engine.GET(BaseEndpoint+Categories+CategoryId, a.CategoryHandler.HandleGetCategory)
+	engine.GET(BaseEndpoint+Categories+CategoryId+Products, a.CategoryHandler.HandleListCategoryProducts)
+
+	admin := engine.Group(BaseEndpoint+Admin+Categories, requirePermission(a, models.ScopeCatalogWrite))
+	admin.POST("", a.CategoryHandler.HandleCreateCategory)
+	admin.PUT(CategoryId, a.CategoryHandler.HandleUpdateCategory)
+	admin.DELETE(CategoryId, a.CategoryHandler.HandleDeleteCategory)
+}
+
+func configureAdminProductRoutes(engine *gin.Engine, a *app.App) {
+	admin := engine.Group(BaseEndpoint+Admin+Products, requirePermission(a, models.ScopeCatalogWrite))
+	admin.POST("", a.AdminProductHandler.HandleCreateProduct)
+	admin.PUT(ProductId, a.AdminProductHandler.HandleUpdateProduct)
+	admin.PATCH(ProductId, a.AdminProductHandler.HandlePatchProduct)
+	admin.DELETE(ProductId, a.AdminProductHandler.HandleArchiveProduct)
+
+	engine.GET(BaseEndpoint+Admin+Products+ProductId+Stock, requirePermission(a, models.ScopeInventoryRead), a.AdminProductHandler.HandleGetStock)
+	engine.PUT(BaseEndpoint+Admin+Products+ProductId+Stock, requirePermission(a, models.ScopeInventoryWrite), a.AdminProductHandler.HandleSetStock)
+}
+
+func configureDownloadRoutes(engine *gin.Engine, a *app.App) {
+	engine.GET(BaseEndpoint+Downloads+OrderId+ProductId, a.DownloadHandler.HandleDownload)
+}
+
+func configureClientRoutes(engine *gin.Engine, a *app.App) {
+	engine.POST(BaseEndpoint+Clients, a.ClientHandler.HandleSignUp)
+
+	me := engine.Group(BaseEndpoint+Clients+Me, a.Authenticate, Authorize(models.ScopeShopping))
+	me.GET("", a.ClientHandler.HandleGetProfile)
+	me.PUT("", a.ClientHandler.HandleUpdateProfile)
+	me.DELETE("", a.ClientHandler.HandleDeleteAccount)
+	me.GET(Addresses, a.AddressHandler.HandleListAddresses)
+	me.POST(Addresses, a.AddressHandler.HandleCreateAddress)
+	me.GET(Addresses+AddressId, a.AddressHandler.HandleGetAddress)
+	me.PUT(Addresses+AddressId, a.AddressHandler.HandleUpdateAddress)
+	me.DELETE(Addresses+AddressId, a.AddressHandler.HandleDeleteAddress)
+}
+
+// configureSupportRoutes exposes the carts and orders of any client to staff,
+// read only, and lets super admins hand out roles and password resets.
+func configureSupportRoutes(engine *gin.Engine, a *app.App) {
+	client := engine.Group(BaseEndpoint + Admin + Clients + ClientId)
+	client.GET(Cart, requirePermission(a, models.ScopeCartsRead), a.CartHandler.HandleGetClientCart)
+	client.GET(Orders, requirePermission(a, models.ScopeOrdersRead), a.OrderHandler.HandleListClientOrders)
+	client.GET(Orders+OrderId, requirePermission(a, models.ScopeOrdersRead), a.OrderHandler.HandleGetClientOrder)
+	client.PUT(Role, requirePermission(a, models.ScopeRolesManage), a.ClientHandler.HandleSetRole)
+	client.POST(Password, requirePermission(a, models.ScopeClientsManage), a.AuthHandler.HandleIssuePasswordReset)
+}
+
+func configureApiKeyRoutes(engine *gin.Engine, a *app.App) {
+	apiKeys := engine.Group(BaseEndpoint+Admin+ApiKeys, requirePermission(a, models.ScopeKeysManage))
+	apiKeys.POST("", a.ApiKeyHandler.HandleCreateApiKey)
+	apiKeys.GET("", a.ApiKeyHandler.HandleListApiKeys)
+	apiKeys.DELETE(ApiKeyId, a.ApiKeyHandler.HandleRevokeApiKey)
+}
+
+func configureAuthRoutes(engine *gin.Engine, a *app.App) {
+	engine.POST(BaseEndpoint+Auth+Login, a.AuthHandler.HandleLogin)
+	engine.POST(BaseEndpoint+Auth+Refresh, a.AuthHandler.HandleRefresh)
+	engine.POST(BaseEndpoint+Auth+Logout, a.AuthHandler.HandleLogout)
+	engine.POST(BaseEndpoint+Auth+Password, a.AuthHandler.HandleResetPassword)
+}
 
-func ConfigureRouter(engine *gin.Engine) {
-	configureCartRoutes(engine)
-}
-
-func configureCartRoutes(engine *gin.Engine) {
-	engine.GET(BaseEndpoint+Cart, cartHandler.HandleGetCart)
-	engine.POST(BaseEndpoint+Cart+AddProduct, cartHandler.HandleAddProduct)
-}
-
-func init() {
-	client, err := repository.GetClient()
-	if err != nil {
-		logger.Error(fmt.Sprintf("Error getting DbClient: %v", err.Error()))
-	}
-	cartHandler = &handler.CartHandlerImpl{
-		CartService: &services.CartServiceImpl{
-			CartRepository: &repository.PgCartRepository{
-				DbClient: client,
-			},
-			ClientRepository: &repository.PgClientRepository{
-				DbClient: client,
-			},
-			ProductRepository: &repository.PgProductRepository{
-				DbClient: client,
-			},
-		},
-	}
+func requirePermission(a *app.App, scope models.Scope) gin.HandlerFunc {
+	return RequirePermission(a.ApiKeyService, a.Tokens, scope)
 }
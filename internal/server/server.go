@@ -1,27 +1,104 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/app"
+	"github.com/emiliocc5/online-store-api/internal/config"
+	"github.com/emiliocc5/online-store-api/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
 )
 
+const (
+	abandonedOrdersInterval = time.Minute
+	shutdownTimeout         = 10 * time.Second
+)
+
+var logger *logrus.Logger
+
 type Server struct {
 	server *gin.Engine
+	config config.Config
+	app    *app.App
+}
+
+func init() {
+	logger = utils.GetLogger()
 }
 
-func New() Server {
-	s := &Server{}
+func New(cfg config.Config, a *app.App) Server {
+	s := &Server{config: cfg, app: a}
 	g := gin.Default()
 	s.server = g
 	return *s
 }
 
 func (s *Server) ConfigureRoutes() {
-	ConfigureRouter(s.server)
+	ConfigureRouter(s.server, s.app)
+}
+
+// ServeHTTP lets the server handle requests without listening, as tests do.
+func (s *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	s.server.ServeHTTP(writer, request)
 }
 
-func (s *Server) Run() {
-	err := s.server.Run()
-	if err != nil {
+// StartBackgroundJobs launches the periodic maintenance tasks of the store,
+// unless they are turned off. The tasks stop when ctx is done.
+func (s *Server) StartBackgroundJobs(ctx context.Context) {
+	if !s.config.Features.BackgroundJobs {
+		logger.Info("background jobs are disabled")
 		return
 	}
+	go s.releaseAbandonedOrders(ctx)
+}
+
+// Run serves requests until ctx is done, then lets the ones in flight finish
+// before returning.
+func (s *Server) Run(ctx context.Context) {
+	httpServer := &http.Server{
+		Addr:         fmt.Sprintf(":%v", s.config.Server.Port),
+		Handler:      s.server,
+		ReadTimeout:  s.config.Server.ReadTimeout,
+		WriteTimeout: s.config.Server.WriteTimeout,
+		IdleTimeout:  s.config.Server.IdleTimeout,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logger.Errorf("unable to shut the server down: %v", err)
+		}
+	}()
+
+	err := httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Errorf("server stopped: %v", err)
+	}
+}
+
+// releaseAbandonedOrders periodically gives back the stock held by orders
+// that were never paid, until ctx is done.
+func (s *Server) releaseAbandonedOrders(ctx context.Context) {
+	ticker := time.NewTicker(abandonedOrdersInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		released, err := s.app.OrderService.ReleaseAbandonedOrders()
+		if err != nil {
+			logger.Errorf("unable to release abandoned orders: %v", err)
+			continue
+		}
+		if released > 0 {
+			logger.Infof("released %v abandoned orders", released)
+		}
+	}
 }
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"regexp"
+	"strings"
+)
+
+var (
+	countryCodePattern = regexp.MustCompile("^[A-Z]{2}$")
+
+	// addressRulesByCountry lists what each country needs on top of a
+	// recipient, a street line and a city. Countries not listed only need
+	// those.
+	addressRulesByCountry = map[string]addressRules{
+		"AR": {requireRegion: true, postalCode: regexp.MustCompile(`^([A-Z]\d{4}[A-Z]{3}|\d{4})$`)},
+		"BR": {requireRegion: true, postalCode: regexp.MustCompile(`^\d{5}-?\d{3}$`)},
+		"CA": {requireRegion: true, postalCode: regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`)},
+		"DE": {postalCode: regexp.MustCompile(`^\d{5}$`)},
+		"ES": {postalCode: regexp.MustCompile(`^\d{5}$`)},
+		"FR": {postalCode: regexp.MustCompile(`^\d{5}$`)},
+		"GB": {postalCode: regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`)},
+		"MX": {requireRegion: true, postalCode: regexp.MustCompile(`^\d{5}$`)},
+		"US": {requireRegion: true, postalCode: regexp.MustCompile(`^\d{5}(-\d{4})?$`)},
+	}
+)
+
+type (
+	AddressService interface {
+		ListAddresses(clientId int) ([]response.AddressResponse, error)
+		GetAddress(clientId, addressId int) (response.AddressResponse, error)
+		CreateAddress(clientId int, req request.AddressRequest) (response.AddressResponse, error)
+		UpdateAddress(clientId, addressId int, req request.AddressRequest) (response.AddressResponse, error)
+		DeleteAddress(clientId, addressId int) error
+	}
+	AddressServiceImpl struct {
+		AddressRepository repository.AddressRepository
+		ClientRepository  repository.ClientRepository
+	}
+	addressRules struct {
+		requireRegion bool
+		postalCode    *regexp.Regexp
+	}
+)
+
+func (as *AddressServiceImpl) ListAddresses(clientId int) ([]response.AddressResponse, error) {
+	if !as.ClientRepository.IsClientInDataBase(clientId) {
+		return nil, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+
+	addresses, err := as.AddressRepository.ListAddresses(clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := []response.AddressResponse{}
+	for _, e := range *addresses {
+		resp = append(resp, parseAddress(e))
+	}
+	return resp, nil
+}
+
+func (as *AddressServiceImpl) GetAddress(clientId, addressId int) (response.AddressResponse, error) {
+	address, err := as.AddressRepository.FindAddress(clientId, addressId)
+	if err != nil {
+		return response.AddressResponse{}, err
+	}
+	return parseAddress(*address), nil
+}
+
+// CreateAddress adds the address to the book of the client. The first address
+// becomes the default for both shipping and billing.
+func (as *AddressServiceImpl) CreateAddress(clientId int, req request.AddressRequest) (response.AddressResponse, error) {
+	if !as.ClientRepository.IsClientInDataBase(clientId) {
+		return response.AddressResponse{}, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+
+	address := models.Address{ClientId: clientId}
+	if err := applyAddressRequest(&address, req); err != nil {
+		return response.AddressResponse{}, err
+	}
+
+	addresses, err := as.AddressRepository.ListAddresses(clientId)
+	if err != nil {
+		return response.AddressResponse{}, err
+	}
+	if len(*addresses) == 0 {
+		address.DefaultShipping = true
+		address.DefaultBilling = true
+	}
+
+	if err := as.AddressRepository.SaveAddress(&address); err != nil {
+		return response.AddressResponse{}, err
+	}
+	return parseAddress(address), nil
+}
+
+func (as *AddressServiceImpl) UpdateAddress(clientId, addressId int, req request.AddressRequest) (response.AddressResponse, error) {
+	address, err := as.AddressRepository.FindAddress(clientId, addressId)
+	if err != nil {
+		return response.AddressResponse{}, err
+	}
+
+	if err := applyAddressRequest(address, req); err != nil {
+		return response.AddressResponse{}, err
+	}
+
+	if err := as.AddressRepository.SaveAddress(address); err != nil {
+		return response.AddressResponse{}, err
+	}
+	return parseAddress(*address), nil
+}
+
+func (as *AddressServiceImpl) DeleteAddress(clientId, addressId int) error {
+	address, err := as.AddressRepository.FindAddress(clientId, addressId)
+	if err != nil {
+		return err
+	}
+	return as.AddressRepository.DeleteAddress(address)
+}
+
+func applyAddressRequest(address *models.Address, req request.AddressRequest) error {
+	address.PostalAddress = models.PostalAddress{
+		Recipient:  strings.TrimSpace(req.Recipient),
+		Line1:      strings.TrimSpace(req.Line1),
+		Line2:      strings.TrimSpace(req.Line2),
+		City:       strings.TrimSpace(req.City),
+		Region:     strings.TrimSpace(req.Region),
+		PostalCode: strings.ToUpper(strings.TrimSpace(req.PostalCode)),
+		Country:    strings.ToUpper(strings.TrimSpace(req.Country)),
+	}
+	if err := applyDefaultFlag(&address.DefaultShipping, req.DefaultShipping, "shipping"); err != nil {
+		return err
+	}
+	if err := applyDefaultFlag(&address.DefaultBilling, req.DefaultBilling, "billing"); err != nil {
+		return err
+	}
+	return validatePostalAddress(address.PostalAddress)
+}
+
+// applyDefaultFlag keeps the flag when the request omits it. A default can
+// only be moved by flagging another address, so a client with addresses
+// always has one to order with.
+func applyDefaultFlag(flag *bool, requested *bool, kind string) error {
+	if requested == nil {
+		return nil
+	}
+	if *flag && !*requested {
+		return errors.New(fmt.Sprintf("flag another address as the default %v address instead", kind))
+	}
+	*flag = *requested
+	return nil
+}
+
+func validatePostalAddress(address models.PostalAddress) error {
+	if !countryCodePattern.MatchString(address.Country) {
+		return errors.New("country must be an ISO 3166-1 alpha-2 code")
+	}
+
+	var missing []string
+	if address.Recipient == "" {
+		missing = append(missing, "recipient")
+	}
+	if address.Line1 == "" {
+		missing = append(missing, "line1")
+	}
+	if address.City == "" {
+		missing = append(missing, "city")
+	}
+
+	rules, ok := addressRulesByCountry[address.Country]
+	if ok && rules.requireRegion && address.Region == "" {
+		missing = append(missing, "region")
+	}
+	if ok && rules.postalCode != nil && address.PostalCode == "" {
+		missing = append(missing, "postalCode")
+	}
+	if len(missing) > 0 {
+		return errors.New(fmt.Sprintf("addresses in %v require: %v", address.Country, strings.Join(missing, ", ")))
+	}
+
+	if ok && rules.postalCode != nil && !rules.postalCode.MatchString(address.PostalCode) {
+		return errors.New(fmt.Sprintf("invalid postal code for %v: %v", address.Country, address.PostalCode))
+	}
+	return nil
+}
+
+func parseAddress(address models.Address) response.AddressResponse {
+	return response.AddressResponse{
+		Id:                    address.Id,
+		PostalAddressResponse: parsePostalAddress(address.PostalAddress),
+		DefaultShipping:       address.DefaultShipping,
+		DefaultBilling:        address.DefaultBilling,
+	}
+}
+
+func parsePostalAddress(address models.PostalAddress) response.PostalAddressResponse {
+	return response.PostalAddressResponse{
+		Recipient:  address.Recipient,
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"strings"
+	"time"
+)
+
+const (
+	bootstrapApiKeyName = "bootstrap"
+)
+
+type (
+	ApiKeyService interface {
+		CreateApiKey(req request.ApiKeyRequest) (response.CreatedApiKeyResponse, error)
+		ListApiKeys() ([]response.ApiKeyResponse, error)
+		RevokeApiKey(apiKeyId int) error
+		// Authenticate returns the active key matching the one presented.
+		Authenticate(key string) (*models.ApiKey, error)
+	}
+	ApiKeyServiceImpl struct {
+		ApiKeyRepository repository.ApiKeyRepository
+		// BootstrapKey, when set, is accepted with every scope so the first
+		// keys can be created.
+		BootstrapKey string
+	}
+)
+
+func (as *ApiKeyServiceImpl) CreateApiKey(req request.ApiKeyRequest) (response.CreatedApiKeyResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return response.CreatedApiKeyResponse{}, errors.New("api key name is required")
+	}
+	if len(req.Scopes) == 0 {
+		return response.CreatedApiKeyResponse{}, errors.New("at least one scope is required")
+	}
+
+	var scopes []models.Scope
+	seen := make(map[models.Scope]bool)
+	for _, e := range req.Scopes {
+		scope := models.Scope(strings.TrimSpace(e))
+		if !scope.IsGrantableToApiKey() {
+			return response.CreatedApiKeyResponse{}, errors.New(fmt.Sprintf("unsupported scope: %v", e))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	key, prefix, hash := auth.NewApiKey()
+	apiKey := models.ApiKey{Name: name, Prefix: prefix, KeyHash: hash, Scopes: models.JoinScopes(scopes)}
+	if err := as.ApiKeyRepository.CreateApiKey(&apiKey); err != nil {
+		return response.CreatedApiKeyResponse{}, err
+	}
+	return response.CreatedApiKeyResponse{ApiKeyResponse: parseApiKey(apiKey), Key: key}, nil
+}
+
+func (as *ApiKeyServiceImpl) ListApiKeys() ([]response.ApiKeyResponse, error) {
+	apiKeys, err := as.ApiKeyRepository.ListApiKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := []response.ApiKeyResponse{}
+	for _, e := range *apiKeys {
+		resp = append(resp, parseApiKey(e))
+	}
+	return resp, nil
+}
+
+func (as *ApiKeyServiceImpl) RevokeApiKey(apiKeyId int) error {
+	return as.ApiKeyRepository.RevokeApiKey(apiKeyId)
+}
+
+func (as *ApiKeyServiceImpl) Authenticate(key string) (*models.ApiKey, error) {
+	// The bootstrap key is named by a fixed prefix, which no generated key
+	// can have since theirs are hexadecimal, so the actions it takes are
+	// recorded under it.
+	if as.BootstrapKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(as.BootstrapKey)) == 1 {
+		return &models.ApiKey{Name: bootstrapApiKeyName, Prefix: bootstrapApiKeyName, Scopes: models.JoinScopes(models.ApiKeyScopes())}, nil
+	}
+
+	prefix, err := auth.ApiKeyPrefix(key)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := as.ApiKeyRepository.FindApiKeyByPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.IsRevoked() || subtle.ConstantTimeCompare([]byte(auth.HashApiKey(key)), []byte(apiKey.KeyHash)) != 1 {
+		return nil, auth.ErrInvalidApiKey
+	}
+
+	// Failing to record the use must not lock the integration out.
+	if err := as.ApiKeyRepository.TouchApiKey(apiKey.Id, time.Now()); err != nil {
+		logger.Errorf("unable to record the use of api key: %v, with error: %v", apiKey.Prefix, err)
+	}
+	return apiKey, nil
+}
+
+func parseApiKey(apiKey models.ApiKey) response.ApiKeyResponse {
+	scopes := []string{}
+	for _, e := range apiKey.ScopeList() {
+		scopes = append(scopes, string(e))
+	}
+	return response.ApiKeyResponse{
+		Id:         apiKey.Id,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     scopes,
+		CreatedAt:  apiKey.CreatedAt,
+		LastUsedAt: apiKey.LastUsedAt,
+		RevokedAt:  apiKey.RevokedAt,
+	}
+}
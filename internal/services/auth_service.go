@@ -0,0 +1,163 @@
+package services
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"strings"
+	"time"
+)
+
+const (
+	AccessTokenLifetime        = 15 * time.Minute
+	RefreshTokenLifetime       = 30 * 24 * time.Hour
+	PasswordResetTokenLifetime = 24 * time.Hour
+
+	tokenType = "Bearer"
+)
+
+var (
+	// missingClientHash is checked when the email is unknown, so a failed login
+	// takes as long whether or not the account exists.
+	missingClientHash, _ = auth.HashPassword("missing-client-password")
+)
+
+type (
+	AuthService interface {
+		Login(req request.LoginRequest) (response.TokenResponse, error)
+		// Refresh trades a refresh token for a new access token and a new
+		// refresh token; the one presented stops working.
+		Refresh(req request.RefreshTokenRequest) (response.TokenResponse, error)
+		Logout(req request.RefreshTokenRequest) error
+		// IssuePasswordReset hands staff a token the client can redeem once to
+		// choose a new password.
+		IssuePasswordReset(clientId int) (response.PasswordResetResponse, error)
+		// ResetPassword sets the password of the client a reset token was
+		// issued for and signs it out of every session.
+		ResetPassword(req request.ResetPasswordRequest) error
+	}
+	AuthServiceImpl struct {
+		ClientRepository repository.ClientRepository
+		TokenRepository  repository.TokenRepository
+		Tokens           *auth.TokenIssuer
+	}
+)
+
+func (as *AuthServiceImpl) Login(req request.LoginRequest) (response.TokenResponse, error) {
+	client, err := as.ClientRepository.FindClientByEmail(strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil {
+		return response.TokenResponse{}, err
+	}
+	if client == nil {
+		auth.CheckPassword(missingClientHash, req.Password)
+		return response.TokenResponse{}, auth.ErrInvalidCredentials
+	}
+	if !auth.CheckPassword(client.PasswordHash, req.Password) {
+		return response.TokenResponse{}, auth.ErrInvalidCredentials
+	}
+
+	return as.issueTokens(*client, time.Now())
+}
+
+func (as *AuthServiceImpl) Refresh(req request.RefreshTokenRequest) (response.TokenResponse, error) {
+	now := time.Now()
+	token, err := as.TokenRepository.FindRefreshToken(auth.HashRefreshToken(req.RefreshToken))
+	if err != nil {
+		return response.TokenResponse{}, err
+	}
+	if !token.IsActive(now) {
+		return response.TokenResponse{}, auth.ErrInvalidToken
+	}
+	// The account may have been deleted or changed role since the token was
+	// issued.
+	client, err := as.ClientRepository.FindClientById(token.ClientId)
+	if err != nil {
+		return response.TokenResponse{}, auth.ErrInvalidToken
+	}
+
+	if err := as.TokenRepository.RevokeRefreshToken(token.Id); err != nil {
+		return response.TokenResponse{}, err
+	}
+	return as.issueTokens(*client, now)
+}
+
+func (as *AuthServiceImpl) Logout(req request.RefreshTokenRequest) error {
+	token, err := as.TokenRepository.FindRefreshToken(auth.HashRefreshToken(req.RefreshToken))
+	if err != nil {
+		return err
+	}
+	return as.TokenRepository.RevokeRefreshToken(token.Id)
+}
+
+func (as *AuthServiceImpl) IssuePasswordReset(clientId int) (response.PasswordResetResponse, error) {
+	if _, err := as.ClientRepository.FindClientById(clientId); err != nil {
+		return response.PasswordResetResponse{}, err
+	}
+
+	resetToken, resetTokenHash := auth.NewPasswordResetToken()
+	token := models.PasswordResetToken{
+		ClientId:  clientId,
+		TokenHash: resetTokenHash,
+		ExpiresAt: time.Now().Add(PasswordResetTokenLifetime),
+	}
+	if err := as.TokenRepository.CreatePasswordResetToken(&token); err != nil {
+		return response.PasswordResetResponse{}, err
+	}
+	return response.PasswordResetResponse{ClientId: clientId, ResetToken: resetToken, ExpiresAt: token.ExpiresAt}, nil
+}
+
+func (as *AuthServiceImpl) ResetPassword(req request.ResetPasswordRequest) error {
+	passwordHash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+
+	token, err := as.TokenRepository.FindPasswordResetToken(auth.HashPasswordResetToken(req.ResetToken))
+	if err != nil {
+		return err
+	}
+	if !token.IsActive(time.Now()) {
+		return auth.ErrInvalidToken
+	}
+	// The account may have been deleted since the token was issued.
+	client, err := as.ClientRepository.FindClientById(token.ClientId)
+	if err != nil {
+		return auth.ErrInvalidToken
+	}
+
+	if err := as.TokenRepository.UsePasswordResetToken(token.Id); err != nil {
+		return err
+	}
+	client.PasswordHash = passwordHash
+	if err := as.ClientRepository.UpdateClient(client); err != nil {
+		return err
+	}
+	return as.TokenRepository.RevokeClientRefreshTokens(client.Id)
+}
+
+func (as *AuthServiceImpl) issueTokens(client models.Client, now time.Time) (response.TokenResponse, error) {
+	accessToken, expiresAt, err := as.Tokens.IssueAccessToken(client.Id, client.Role, now)
+	if err != nil {
+		logger.Errorf("unable to sign access token for client: %v, with error: %v", client.Id, err)
+		return response.TokenResponse{}, err
+	}
+
+	refreshToken, refreshTokenHash := auth.NewRefreshToken()
+	err = as.TokenRepository.CreateRefreshToken(&models.RefreshToken{
+		ClientId:  client.Id,
+		TokenHash: refreshTokenHash,
+		ExpiresAt: now.Add(RefreshTokenLifetime),
+	})
+	if err != nil {
+		return response.TokenResponse{}, err
+	}
+
+	return response.TokenResponse{
+		AccessToken:  accessToken,
+		TokenType:    tokenType,
+		ExpiresIn:    int(expiresAt.Sub(now.Truncate(time.Second)).Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
@@ -10,6 +10,10 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	MaxProductQuantity = models.MaxProductQuantity
+)
+
 var (
 	logger *logrus.Logger
 )
@@ -18,11 +22,15 @@ type (
 	CartService interface {
 		GetCart(clientId int) (response.GetCartResponse, error)
 		AddProductToCart(productId, clientId int) error
+		SetProductQuantity(productId, clientId, quantity int) error
+		RemoveProductFromCart(productId, clientId int) error
+		ClearCart(clientId int) error
 	}
 	CartServiceImpl struct {
 		CartRepository    repository.CartRepository
 		ClientRepository  repository.ClientRepository
 		ProductRepository repository.ProductRepository
+		StockRepository   repository.StockRepository
 	}
 )
 
@@ -45,12 +53,23 @@ func (c *CartServiceImpl) GetCart(clientId int) (response.GetCartResponse, error
 			clientId))
 	}
 
-	prods, errGetProds := c.ProductRepository.FindProductsFromCart(cart.Id)
-	if errGetProds != nil {
-		logger.Errorf("unable to get the list of products from the cart: %v, with error: %v", cart.Id, errGetProds)
+	items, errGetItems := c.CartRepository.GetCartItems(cart.Id)
+	if errGetItems != nil {
+		logger.Errorf("unable to get the list of products from the cart: %v, with error: %v", cart.Id, errGetItems)
 		return resp, errors.New(fmt.Sprintf("unable to get the list of products from the cart: %v", cart.Id))
 	}
-	resp.Products = parseProducts(*prods)
+
+	subtotal := models.Money{}
+	for _, e := range aggregateCartItems(*items) {
+		line := parseProductLine(e.Product, e.Product.Price, e.Quantity)
+		subtotal, errGetItems = subtotal.Add(e.Product.Price.Multiply(e.Quantity))
+		if errGetItems != nil {
+			logger.Errorf("unable to compute the subtotal of the cart: %v, with error: %v", cart.Id, errGetItems)
+			return resp, errors.New("the cart contains products priced in different currencies")
+		}
+		resp.Products = append(resp.Products, line)
+	}
+	resp.Subtotal = parseMoney(subtotal)
 
 	return resp, nil
 }
@@ -65,8 +84,18 @@ func (c *CartServiceImpl) AddProductToCart(productId, clientId int) error {
 		logger.Error(fmt.Sprintf("unable to get the product: %v", err.Error()))
 		return errors.New("unable to find the product in our db")
 	}
+	if product.IsArchived() {
+		return errors.New(fmt.Sprintf("the product: %v is no longer available", productId))
+	}
+	err = c.checkStock(*product, c.quantityInCart(productId, clientId)+1)
+	if err != nil {
+		return err
+	}
 
 	errAddProd := c.CartRepository.AddProductToCart(product.Id, clientId)
+	if errors.Is(errAddProd, models.ErrCartQuantityLimit) {
+		return errAddProd
+	}
 	if errAddProd != nil {
 		logger.Error(fmt.Sprintf("Failed trying to add product: %+v to cart to the client: %+v with error: %+v",
 			productId, clientId, errAddProd))
@@ -75,18 +104,160 @@ func (c *CartServiceImpl) AddProductToCart(productId, clientId int) error {
 	return nil
 }
 
-func parseProducts(dbProducts []models.Product) []response.ProductResponse {
-	var responseProducts []response.ProductResponse
-	for _, e := range dbProducts {
-		prod := response.ProductResponse{
-			Id:          e.Id,
-			Label:       e.Label,
-			Category:    e.Category.Label,
-			Type:        e.Type,
-			DownloadUrl: e.DownloadUrl,
-			Weight:      e.Weight,
+func (c *CartServiceImpl) SetProductQuantity(productId, clientId, quantity int) error {
+	if quantity < 1 || quantity > MaxProductQuantity {
+		return errors.New(fmt.Sprintf("quantity must be between 1 and %v", MaxProductQuantity))
+	}
+
+	if !c.ClientRepository.IsClientInDataBase(clientId) {
+		return errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+
+	product, err := c.ProductRepository.FindProductById(productId)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to get the product: %v", err.Error()))
+		return errors.New("unable to find the product in our db")
+	}
+	if product.IsArchived() {
+		return errors.New(fmt.Sprintf("the product: %v is no longer available", productId))
+	}
+	err = c.checkStock(*product, quantity)
+	if err != nil {
+		return err
+	}
+
+	errSetQuantity := c.CartRepository.SetProductQuantity(product.Id, clientId, quantity)
+	if errSetQuantity != nil {
+		logger.Error(fmt.Sprintf("Failed trying to set quantity of product: %+v in the cart of the client: %+v with error: %+v",
+			productId, clientId, errSetQuantity))
+		return errors.New("unable to update the product quantity in the cart")
+	}
+	return nil
+}
+
+func (c *CartServiceImpl) RemoveProductFromCart(productId, clientId int) error {
+	if !c.ClientRepository.IsClientInDataBase(clientId) {
+		return errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+
+	errRemoveProd := c.CartRepository.RemoveProductFromCart(productId, clientId)
+	if errRemoveProd != nil {
+		logger.Error(fmt.Sprintf("Failed trying to remove product: %+v from the cart of the client: %+v with error: %+v",
+			productId, clientId, errRemoveProd))
+		return errRemoveProd
+	}
+	return nil
+}
+
+func (c *CartServiceImpl) ClearCart(clientId int) error {
+	if !c.ClientRepository.IsClientInDataBase(clientId) {
+		return errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+
+	errClearCart := c.CartRepository.ClearCart(clientId)
+	if errClearCart != nil {
+		logger.Error(fmt.Sprintf("Failed trying to clear the cart of the client: %+v with error: %+v",
+			clientId, errClearCart))
+		return errClearCart
+	}
+	return nil
+}
+
+// checkStock rejects quantities above what is left to sell. It only guards the
+// cart; the reservation taken at checkout is what actually holds the units.
+func (c *CartServiceImpl) checkStock(product models.Product, quantity int) error {
+	if !product.Type.TracksStock() {
+		return nil
+	}
+
+	stock, err := c.StockRepository.GetStock(product.Id)
+	if err != nil {
+		logger.Errorf("unable to get the stock of the product: %v, with error: %v", product.Id, err)
+		return errors.New(fmt.Sprintf("unable to check the stock of the product: %v", product.Id))
+	}
+	if stock == nil {
+		return nil
+	}
+	if stock.Available() < quantity {
+		return models.NewInsufficientStockError(product.Id, stock.Available())
+	}
+	return nil
+}
+
+func (c *CartServiceImpl) quantityInCart(productId, clientId int) int {
+	cart, err := c.CartRepository.GetCartByClient(clientId)
+	if err != nil {
+		return 0
+	}
+	items, err := c.CartRepository.GetCartItems(cart.Id)
+	if err != nil {
+		return 0
+	}
+
+	quantity := 0
+	for _, e := range *items {
+		if e.ProductId == productId {
+			quantity += e.Quantity
+		}
+	}
+	return quantity
+}
+
+// aggregateCartItems merges rows for the same product, which older carts may
+// still hold from before quantities were tracked, keeping first-seen order.
+func aggregateCartItems(items []models.ProductCart) []models.ProductCart {
+	var aggregated []models.ProductCart
+	positions := make(map[int]int)
+	for _, e := range items {
+		if position, ok := positions[e.ProductId]; ok {
+			aggregated[position].Quantity += e.Quantity
+			continue
 		}
-		responseProducts = append(responseProducts, prod)
+		positions[e.ProductId] = len(aggregated)
+		aggregated = append(aggregated, e)
+	}
+	return aggregated
+}
+
+func parseProductLine(dbProduct models.Product, unitPrice models.Money, quantity int) response.ProductLineResponse {
+	product := parseProduct(dbProduct)
+	product.Price = parseMoney(unitPrice)
+	return response.ProductLineResponse{
+		ProductResponse: product,
+		Quantity:        quantity,
+		Subtotal:        parseMoney(unitPrice.Multiply(quantity)),
+	}
+}
+
+func parseProduct(dbProduct models.Product) response.ProductResponse {
+	product := response.ProductResponse{
+		Id:       dbProduct.Id,
+		Label:    dbProduct.Label,
+		Category: dbProduct.Category.Label,
+		Type:     dbProduct.Type.String(),
+		Price:    parseMoney(dbProduct.Price),
+		Archived: dbProduct.IsArchived(),
+	}
+	if dbProduct.Type.IsShippable() {
+		product.Shipping = &response.ShippingDetailsResponse{Weight: dbProduct.Weight}
+	}
+	return product
+}
+
+// parseAdminProduct also exposes the download url, which clients only get
+// through the signed links of a paid order.
+func parseAdminProduct(dbProduct models.Product) response.ProductResponse {
+	product := parseProduct(dbProduct)
+	if dbProduct.Type == models.ProductTypeDigital {
+		product.Download = &response.DownloadDetailsResponse{Url: dbProduct.DownloadUrl}
+	}
+	return product
+}
+
+func parseMoney(money models.Money) response.MoneyResponse {
+	return response.MoneyResponse{
+		Amount:    money.Amount,
+		Currency:  money.Currency,
+		Formatted: money.String(),
 	}
-	return responseProducts
 }
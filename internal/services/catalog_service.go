@@ -0,0 +1,56 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+)
+
+type (
+	CatalogService interface {
+		ListProducts(filter models.ProductFilter) (response.ListProductsResponse, error)
+		GetProduct(productId int) (response.ProductResponse, error)
+	}
+	CatalogServiceImpl struct {
+		ProductRepository repository.ProductRepository
+	}
+)
+
+func (cs *CatalogServiceImpl) ListProducts(filter models.ProductFilter) (response.ListProductsResponse, error) {
+	resp := response.ListProductsResponse{Products: []response.ProductResponse{}}
+
+	if filter.Sort == "" {
+		filter.Sort = models.ProductSortById
+	}
+	if !models.IsValidProductSort(filter.Sort) {
+		return resp, errors.New(fmt.Sprintf("unsupported sort: %v", filter.Sort))
+	}
+	filter.Page, filter.PageSize = normalizePagination(filter.Page, filter.PageSize)
+
+	products, total, err := cs.ProductRepository.ListProducts(filter)
+	if err != nil {
+		logger.Errorf("unable to list products with error: %v", err)
+		return resp, errors.New("unable to retrieve the list of products")
+	}
+
+	for _, e := range *products {
+		resp.Products = append(resp.Products, parseProduct(e))
+	}
+	resp.Pagination = buildPagination(filter.Page, filter.PageSize, total)
+
+	return resp, nil
+}
+
+func (cs *CatalogServiceImpl) GetProduct(productId int) (response.ProductResponse, error) {
+	product, err := cs.ProductRepository.FindProductById(productId)
+	if err == nil && product.IsArchived() {
+		err = errors.New("the product is archived")
+	}
+	if err != nil {
+		logger.Errorf("unable to get the product: %v, with error: %v", productId, err)
+		return response.ProductResponse{}, errors.New(fmt.Sprintf("product with id: %v not found", productId))
+	}
+	return parseProduct(*product), nil
+}
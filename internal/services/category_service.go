@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"strings"
+)
+
+type (
+	CategoryService interface {
+		ListCategoryTree() ([]response.CategoryResponse, error)
+		GetCategory(categoryId int) (response.CategoryResponse, error)
+		CreateCategory(req request.CategoryRequest) (response.CategoryResponse, error)
+		UpdateCategory(categoryId int, req request.CategoryRequest) (response.CategoryResponse, error)
+		DeleteCategory(categoryId int) error
+		ListCategoryProducts(categoryId int, filter models.ProductFilter) (response.ListProductsResponse, error)
+	}
+	CategoryServiceImpl struct {
+		CategoryRepository repository.CategoryRepository
+		CatalogService     CatalogService
+	}
+)
+
+func (cs *CategoryServiceImpl) ListCategoryTree() ([]response.CategoryResponse, error) {
+	categories, err := cs.CategoryRepository.ListCategories()
+	if err != nil {
+		return nil, err
+	}
+
+	childrenByParent := groupCategoriesByParent(*categories)
+	roots := []response.CategoryResponse{}
+	for _, e := range childrenByParent[0] {
+		roots = append(roots, buildCategoryTree(e, childrenByParent))
+	}
+	return roots, nil
+}
+
+func (cs *CategoryServiceImpl) GetCategory(categoryId int) (response.CategoryResponse, error) {
+	categories, err := cs.CategoryRepository.ListCategories()
+	if err != nil {
+		return response.CategoryResponse{}, err
+	}
+
+	for _, e := range *categories {
+		if e.Id == categoryId {
+			return buildCategoryTree(e, groupCategoriesByParent(*categories)), nil
+		}
+	}
+	return response.CategoryResponse{}, errors.New(fmt.Sprintf("category with id: %v not found", categoryId))
+}
+
+func (cs *CategoryServiceImpl) CreateCategory(req request.CategoryRequest) (response.CategoryResponse, error) {
+	label := strings.TrimSpace(req.Label)
+	if label == "" {
+		return response.CategoryResponse{}, errors.New("category label is required")
+	}
+
+	if req.ParentId != nil {
+		if _, err := cs.CategoryRepository.FindCategoryById(*req.ParentId); err != nil {
+			return response.CategoryResponse{}, errors.New(fmt.Sprintf("parent category with id: %v not found", *req.ParentId))
+		}
+	}
+
+	category := models.Category{Label: label, ParentId: req.ParentId}
+	if err := cs.CategoryRepository.CreateCategory(&category); err != nil {
+		return response.CategoryResponse{}, err
+	}
+	return parseCategory(category), nil
+}
+
+func (cs *CategoryServiceImpl) UpdateCategory(categoryId int, req request.CategoryRequest) (response.CategoryResponse, error) {
+	label := strings.TrimSpace(req.Label)
+	if label == "" {
+		return response.CategoryResponse{}, errors.New("category label is required")
+	}
+
+	category, err := cs.CategoryRepository.FindCategoryById(categoryId)
+	if err != nil {
+		return response.CategoryResponse{}, err
+	}
+
+	if req.ParentId != nil {
+		categories, err := cs.CategoryRepository.ListCategories()
+		if err != nil {
+			return response.CategoryResponse{}, err
+		}
+		if !containsCategory(*categories, *req.ParentId) {
+			return response.CategoryResponse{}, errors.New(fmt.Sprintf("parent category with id: %v not found", *req.ParentId))
+		}
+		for _, descendantId := range collectDescendantIds(categoryId, groupCategoriesByParent(*categories)) {
+			if descendantId == *req.ParentId {
+				return response.CategoryResponse{}, errors.New("a category cannot be moved under itself or one of its subcategories")
+			}
+		}
+	}
+
+	category.Label = label
+	category.ParentId = req.ParentId
+	if err := cs.CategoryRepository.UpdateCategory(category); err != nil {
+		return response.CategoryResponse{}, err
+	}
+	return parseCategory(*category), nil
+}
+
+func (cs *CategoryServiceImpl) DeleteCategory(categoryId int) error {
+	return cs.CategoryRepository.DeleteCategory(categoryId)
+}
+
+func (cs *CategoryServiceImpl) ListCategoryProducts(categoryId int, filter models.ProductFilter) (response.ListProductsResponse, error) {
+	categories, err := cs.CategoryRepository.ListCategories()
+	if err != nil {
+		return response.ListProductsResponse{}, err
+	}
+	if !containsCategory(*categories, categoryId) {
+		return response.ListProductsResponse{}, errors.New(fmt.Sprintf("category with id: %v not found", categoryId))
+	}
+
+	filter.CategoryIds = collectDescendantIds(categoryId, groupCategoriesByParent(*categories))
+	return cs.CatalogService.ListProducts(filter)
+}
+
+// groupCategoriesByParent indexes categories by parent id; root categories
+// are stored under 0.
+func groupCategoriesByParent(categories []models.Category) map[int][]models.Category {
+	childrenByParent := make(map[int][]models.Category)
+	for _, e := range categories {
+		parentId := 0
+		if e.ParentId != nil {
+			parentId = *e.ParentId
+		}
+		childrenByParent[parentId] = append(childrenByParent[parentId], e)
+	}
+	return childrenByParent
+}
+
+// collectDescendantIds returns the category id followed by the ids of all of
+// its subcategories, at any depth.
+func collectDescendantIds(categoryId int, childrenByParent map[int][]models.Category) []int {
+	ids := []int{categoryId}
+	visited := map[int]bool{categoryId: true}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range childrenByParent[ids[i]] {
+			if !visited[child.Id] {
+				visited[child.Id] = true
+				ids = append(ids, child.Id)
+			}
+		}
+	}
+	return ids
+}
+
+func buildCategoryTree(category models.Category, childrenByParent map[int][]models.Category) response.CategoryResponse {
+	node := parseCategory(category)
+	for _, child := range childrenByParent[category.Id] {
+		node.Children = append(node.Children, buildCategoryTree(child, childrenByParent))
+	}
+	return node
+}
+
+func containsCategory(categories []models.Category, categoryId int) bool {
+	for _, e := range categories {
+		if e.Id == categoryId {
+			return true
+		}
+	}
+	return false
+}
+
+func parseCategory(category models.Category) response.CategoryResponse {
+	return response.CategoryResponse{
+		Id:       category.Id,
+		Label:    category.Label,
+		ParentId: category.ParentId,
+	}
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+const (
+	MaxClientNameLength = 100
+)
+
+var (
+	phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{5,18}[0-9]$`)
+)
+
+type (
+	ClientService interface {
+		SignUp(req request.SignUpRequest) (response.ClientResponse, error)
+		GetProfile(clientId int) (response.ClientResponse, error)
+		UpdateProfile(clientId int, req request.ClientRequest) (response.ClientResponse, error)
+		DeleteAccount(clientId int) error
+		SetRole(clientId int, req request.SetRoleRequest) (response.ClientResponse, error)
+	}
+	ClientServiceImpl struct {
+		ClientRepository repository.ClientRepository
+	}
+)
+
+func (cs *ClientServiceImpl) SignUp(req request.SignUpRequest) (response.ClientResponse, error) {
+	client := models.Client{Role: models.RoleCustomer}
+	if err := applyClientRequest(&client, req.ClientRequest); err != nil {
+		return response.ClientResponse{}, err
+	}
+	passwordHash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		return response.ClientResponse{}, err
+	}
+	client.PasswordHash = passwordHash
+	if err := cs.checkEmailAvailable(0, client.Email); err != nil {
+		return response.ClientResponse{}, err
+	}
+
+	if err := cs.ClientRepository.CreateClient(&client); err != nil {
+		return response.ClientResponse{}, err
+	}
+	return parseClient(client), nil
+}
+
+func (cs *ClientServiceImpl) GetProfile(clientId int) (response.ClientResponse, error) {
+	client, err := cs.ClientRepository.FindClientById(clientId)
+	if err != nil {
+		return response.ClientResponse{}, err
+	}
+	return parseClient(*client), nil
+}
+
+func (cs *ClientServiceImpl) UpdateProfile(clientId int, req request.ClientRequest) (response.ClientResponse, error) {
+	client, err := cs.ClientRepository.FindClientById(clientId)
+	if err != nil {
+		return response.ClientResponse{}, err
+	}
+
+	if err := applyClientRequest(client, req); err != nil {
+		return response.ClientResponse{}, err
+	}
+	if err := cs.checkEmailAvailable(clientId, client.Email); err != nil {
+		return response.ClientResponse{}, err
+	}
+
+	if err := cs.ClientRepository.UpdateClient(client); err != nil {
+		return response.ClientResponse{}, err
+	}
+	return parseClient(*client), nil
+}
+
+func (cs *ClientServiceImpl) DeleteAccount(clientId int) error {
+	return cs.ClientRepository.DeleteClient(clientId)
+}
+
+func (cs *ClientServiceImpl) SetRole(clientId int, req request.SetRoleRequest) (response.ClientResponse, error) {
+	if !req.Role.IsValid() {
+		return response.ClientResponse{}, errors.New(fmt.Sprintf("unsupported role: %v", req.Role))
+	}
+
+	client, err := cs.ClientRepository.FindClientById(clientId)
+	if err != nil {
+		return response.ClientResponse{}, err
+	}
+	client.Role = req.Role
+	if err := cs.ClientRepository.UpdateClient(client); err != nil {
+		return response.ClientResponse{}, err
+	}
+	return parseClient(*client), nil
+}
+
+// checkEmailAvailable rejects emails used by an account other than clientId.
+func (cs *ClientServiceImpl) checkEmailAvailable(clientId int, email string) error {
+	owner, err := cs.ClientRepository.FindClientByEmail(email)
+	if err != nil {
+		return err
+	}
+	if owner != nil && owner.Id != clientId {
+		return models.NewEmailTakenError(email)
+	}
+	return nil
+}
+
+func applyClientRequest(client *models.Client, req request.ClientRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > MaxClientNameLength {
+		return errors.New("name is too long")
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return errors.New("a valid email is required")
+	}
+
+	phone := strings.TrimSpace(req.Phone)
+	if phone != "" && !phonePattern.MatchString(phone) {
+		return errors.New("invalid phone number")
+	}
+
+	client.Name = name
+	client.Email = email
+	client.Phone = phone
+	return nil
+}
+
+func parseClient(client models.Client) response.ClientResponse {
+	return response.ClientResponse{
+		Id:        client.Id,
+		Name:      client.Name,
+		Email:     client.Email,
+		Phone:     client.Phone,
+		Role:      string(client.Role),
+		CreatedAt: client.CreatedAt,
+		UpdatedAt: client.UpdatedAt,
+	}
+}
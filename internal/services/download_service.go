@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/downloads"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"time"
+)
+
+const (
+	MaxDownloadsPerItem  = 5
+	DownloadLinkLifetime = 15 * time.Minute
+
+	downloadLinkFormat = "/api/downloads/%v/%v?expires=%v&signature=%v"
+)
+
+type (
+	DownloadService interface {
+		IssueDownloadLink(clientId, orderId, productId int) (response.DownloadLinkResponse, error)
+		// RedeemDownloadLink checks a signed link and returns the url of the
+		// file it gives access to.
+		RedeemDownloadLink(req request.RedeemDownloadRequest) (string, error)
+	}
+	DownloadServiceImpl struct {
+		OrderRepository    repository.OrderRepository
+		ProductRepository  repository.ProductRepository
+		DownloadRepository repository.DownloadRepository
+		Signer             *downloads.Signer
+	}
+)
+
+func (ds *DownloadServiceImpl) IssueDownloadLink(clientId, orderId, productId int) (response.DownloadLinkResponse, error) {
+	resp := response.DownloadLinkResponse{}
+
+	order, err := ds.OrderRepository.FindOrderForClient(clientId, orderId)
+	if err != nil {
+		return resp, err
+	}
+	if !order.Status.HasCollectedPayment() {
+		return resp, fmt.Errorf("%w: the order: %v is %v", downloads.ErrDownloadNotAllowed, orderId, order.Status)
+	}
+
+	item, err := ds.DownloadRepository.FindOrderItem(orderId, productId)
+	if err != nil {
+		return resp, err
+	}
+	_, err = ds.findDigitalProduct(productId)
+	if err != nil {
+		return resp, err
+	}
+	if item.Downloads >= MaxDownloadsPerItem {
+		return resp, fmt.Errorf("%w: the product: %v of the order: %v can be downloaded %v times",
+			models.ErrDownloadLimitReached, productId, orderId, MaxDownloadsPerItem)
+	}
+
+	link := ds.Signer.Sign(orderId, productId, time.Now())
+	resp.OrderId = orderId
+	resp.ProductId = productId
+	resp.Url = fmt.Sprintf(downloadLinkFormat, orderId, productId, link.ExpiresAt.Unix(), link.Signature)
+	resp.ExpiresAt = link.ExpiresAt
+	resp.RemainingDownloads = MaxDownloadsPerItem - item.Downloads
+
+	return resp, nil
+}
+
+func (ds *DownloadServiceImpl) RedeemDownloadLink(req request.RedeemDownloadRequest) (string, error) {
+	link := downloads.SignedLink{
+		OrderId:   req.OrderId,
+		ProductId: req.ProductId,
+		ExpiresAt: time.Unix(req.Expires, 0),
+		Signature: req.Signature,
+	}
+	err := ds.Signer.Verify(link, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	// The order may have been cancelled or refunded since the link was issued.
+	order, err := ds.OrderRepository.FindOrderById(req.OrderId)
+	if err != nil {
+		return "", err
+	}
+	if !order.Status.HasCollectedPayment() {
+		return "", fmt.Errorf("%w: the order: %v is %v", downloads.ErrDownloadNotAllowed, req.OrderId, order.Status)
+	}
+
+	product, err := ds.findDigitalProduct(req.ProductId)
+	if err != nil {
+		return "", err
+	}
+
+	download := models.Download{OrderId: req.OrderId, ProductId: req.ProductId, IpAddress: req.IpAddress, UserAgent: req.UserAgent}
+	err = ds.DownloadRepository.RecordDownload(&download, MaxDownloadsPerItem)
+	if err != nil {
+		return "", err
+	}
+
+	return product.DownloadUrl, nil
+}
+
+func (ds *DownloadServiceImpl) findDigitalProduct(productId int) (*models.Product, error) {
+	product, err := ds.ProductRepository.FindProductById(productId)
+	if err != nil {
+		logger.Errorf("unable to get the product: %v, with error: %v", productId, err)
+		return nil, errors.New(fmt.Sprintf("product with id: %v not found", productId))
+	}
+	if product.Type != models.ProductTypeDigital {
+		return nil, errors.New(fmt.Sprintf("the product: %v has nothing to download", productId))
+	}
+	return product, nil
+}
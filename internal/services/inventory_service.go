@@ -0,0 +1,75 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+)
+
+type (
+	InventoryService interface {
+		GetStock(productId int) (response.StockResponse, error)
+		SetStock(productId int, req request.SetStockRequest) (response.StockResponse, error)
+	}
+	InventoryServiceImpl struct {
+		ProductRepository repository.ProductRepository
+		StockRepository   repository.StockRepository
+	}
+)
+
+func (is *InventoryServiceImpl) GetStock(productId int) (response.StockResponse, error) {
+	if err := is.checkStockedProduct(productId); err != nil {
+		return response.StockResponse{}, err
+	}
+
+	stock, err := is.StockRepository.GetStock(productId)
+	if err != nil {
+		return response.StockResponse{}, err
+	}
+	if stock == nil {
+		return response.StockResponse{ProductId: productId}, nil
+	}
+	return parseStock(*stock), nil
+}
+
+func (is *InventoryServiceImpl) SetStock(productId int, req request.SetStockRequest) (response.StockResponse, error) {
+	if req.OnHand == nil {
+		return response.StockResponse{}, errors.New("onHand is required")
+	}
+	if *req.OnHand < 0 {
+		return response.StockResponse{}, errors.New("onHand cannot be negative")
+	}
+	if err := is.checkStockedProduct(productId); err != nil {
+		return response.StockResponse{}, err
+	}
+
+	stock, err := is.StockRepository.SetOnHand(productId, *req.OnHand)
+	if err != nil {
+		return response.StockResponse{}, err
+	}
+	return parseStock(*stock), nil
+}
+
+func (is *InventoryServiceImpl) checkStockedProduct(productId int) error {
+	product, err := is.ProductRepository.FindProductById(productId)
+	if err != nil {
+		return errors.New(fmt.Sprintf("product with id: %v not found", productId))
+	}
+	if !product.Type.TracksStock() {
+		return errors.New(fmt.Sprintf("%v products do not track stock", product.Type))
+	}
+	return nil
+}
+
+func parseStock(stock models.Stock) response.StockResponse {
+	return response.StockResponse{
+		ProductId: stock.ProductId,
+		Tracked:   true,
+		OnHand:    stock.OnHand,
+		Reserved:  stock.Reserved,
+		Available: stock.Available(),
+	}
+}
@@ -1,18 +1,40 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
 	"github.com/emiliocc5/online-store-api/internal/repository"
 	"github.com/emiliocc5/online-store-api/internal/utils"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	MaxCancellationReasonLength = 500
+
+	// AbandonedOrderTimeout is how long a pending order keeps its stock
+	// reserved before it is considered abandoned.
+	AbandonedOrderTimeout = 30 * time.Minute
 )
 
 type (
 	OrderService interface {
-		CreateOrder(clientId, cartId int) error
-		GetProductsFromOrder(clientId, orderId int) (*[]models.Product, error)
+		CreateOrder(clientId int, req request.CreateOrderRequest) (response.CreateOrderResponse, error)
+		GetProductsFromOrder(clientId, orderId int) (response.GetOrderProductsResponse, error)
+		ListOrdersForClient(clientId, page, pageSize int) (response.ListOrdersResponse, error)
+		TransitionOrder(orderId int, actor string, req request.OrderTransitionRequest) (response.OrderTransitionResponse, error)
+		ListOrderTransitions(orderId int) ([]response.OrderTransitionResponse, error)
+		CancelOrder(clientId, orderId int, req request.CancelOrderRequest) (response.CancelOrderResponse, error)
+		ReleaseAbandonedOrders() (int, error)
 	}
 	OrderServiceImpl struct {
 		OrderRepository repository.OrderRepository
+		PaymentService  PaymentService
+		ShippingService ShippingService
 	}
 )
 
@@ -20,12 +42,221 @@ func init() {
 	logger = utils.GetLogger()
 }
 
-func (os *OrderServiceImpl) CreateOrder(clientId, cartId int) error {
-	createOrderErr := os.OrderRepository.CreateOrder(clientId, cartId)
+func (os *OrderServiceImpl) CreateOrder(clientId int, req request.CreateOrderRequest) (response.CreateOrderResponse, error) {
+	resp := response.CreateOrderResponse{}
+
+	orderShipping, shippingErr := os.ShippingService.SelectShippingOption(clientId, req.ShippingAddressId, req.ShippingOption)
+	if shippingErr != nil {
+		return resp, shippingErr
+	}
 
+	order, createOrderErr := os.OrderRepository.CreateOrder(clientId, orderShipping)
 	if createOrderErr != nil {
-		return createOrderErr
+		logger.Errorf("unable to create order for the client: %v, with error: %v", clientId, createOrderErr)
+		return resp, createOrderErr
+	}
+	resp.OrderId = order.Id
+	resp.Status = string(order.Status)
+	resp.Total = parseMoney(order.Total)
+	resp.Shipping = parseOrderShipping(order.Shipping)
+
+	// The order is placed either way, so a failed payment is reported in the
+	// response rather than as an error the client would take for a failed
+	// checkout.
+	payment, payErr := os.PaymentService.PayOrder(clientId, order.Id)
+	if payErr != nil {
+		logger.Errorf("unable to collect the payment of order: %v, with error: %v", order.Id, payErr)
+		resp.Payment = &response.PaymentResponse{
+			OrderId:     order.Id,
+			OrderStatus: string(order.Status),
+			Status:      string(paymentFailureStatus(payErr)),
+			Amount:      resp.Total,
+		}
+		resp.PaymentError = payErr.Error()
+		return resp, nil
+	}
+	resp.Status = payment.OrderStatus
+	resp.Payment = &payment
+
+	return resp, nil
+}
+
+func (os *OrderServiceImpl) GetProductsFromOrder(clientId, orderId int) (response.GetOrderProductsResponse, error) {
+	resp := response.GetOrderProductsResponse{}
+
+	order, err := os.OrderRepository.GetOrderWithItems(clientId, orderId)
+	if err != nil {
+		logger.Errorf("unable to get the list of products from the order: %v, with error: %v", orderId, err)
+		return resp, errors.New(fmt.Sprintf("unable to get the list of products from the order: %v", orderId))
+	}
+
+	for _, e := range order.Items {
+		line := parseProductLine(e.Product, e.UnitPrice, e.Quantity)
+		line.Id = e.ProductId
+		resp.Products = append(resp.Products, line)
+	}
+	resp.Status = string(order.Status)
+	resp.CancellationReason = order.CancellationReason
+	resp.RefundPending = order.RefundPending
+	resp.Shipping = parseOrderShipping(order.Shipping)
+	resp.Total = parseMoney(order.Total)
+
+	return resp, nil
+}
+
+func (os *OrderServiceImpl) ListOrdersForClient(clientId, page, pageSize int) (response.ListOrdersResponse, error) {
+	resp := response.ListOrdersResponse{Orders: []response.OrderSummaryResponse{}}
+	page, pageSize = normalizePagination(page, pageSize)
+
+	summaries, total, err := os.OrderRepository.ListOrdersForClient(clientId, page, pageSize)
+	if err != nil {
+		logger.Errorf("unable to list the orders for the client: %v, with error: %v", clientId, err)
+		return resp, errors.New(fmt.Sprintf("unable to list the orders for the client: %v", clientId))
+	}
+
+	for _, e := range *summaries {
+		resp.Orders = append(resp.Orders, response.OrderSummaryResponse{
+			Id:        e.Order.Id,
+			Status:    string(e.Order.Status),
+			CreatedAt: e.Order.CreatedAt,
+			ItemCount: e.ItemCount,
+			Total:     parseMoney(e.Order.Total),
+		})
+	}
+	resp.Pagination = buildPagination(page, pageSize, total)
+
+	return resp, nil
+}
+
+// TransitionOrder moves the order to the requested status on behalf of actor,
+// who is recorded in its history. Refunding gives the money back before the
+// order is marked refunded, and cancelling reverses the payment the way a
+// client cancellation does.
+func (os *OrderServiceImpl) TransitionOrder(orderId int, actor string, req request.OrderTransitionRequest) (response.OrderTransitionResponse, error) {
+	if !req.Status.IsValid() {
+		return response.OrderTransitionResponse{}, errors.New(fmt.Sprintf("unsupported order status: %v", req.Status))
+	}
+	if strings.TrimSpace(actor) == "" {
+		return response.OrderTransitionResponse{}, errors.New("actor is required")
+	}
+
+	order, err := os.OrderRepository.FindOrderById(orderId)
+	if err != nil {
+		return response.OrderTransitionResponse{}, err
+	}
+	if !order.Status.CanTransitionTo(req.Status) {
+		return response.OrderTransitionResponse{}, models.NewInvalidOrderTransitionError(order.Status, req.Status)
+	}
+
+	if req.Status == models.OrderStatusRefunded {
+		if err := os.PaymentService.RefundPayment(orderId); err != nil {
+			logger.Errorf("unable to refund the payment of order: %v, with error: %v", orderId, err)
+			return response.OrderTransitionResponse{}, err
+		}
+	}
+
+	transition, err := os.OrderRepository.TransitionOrder(orderId, order.Status, req.Status, actor)
+	if err != nil {
+		logger.Errorf("unable to move order: %v to %v, with error: %v", orderId, req.Status, err)
+		return response.OrderTransitionResponse{}, err
+	}
+
+	// As for CancelOrder, the cancellation stands when the payment cannot be
+	// reversed now and the order keeps its refund flag.
+	if req.Status == models.OrderStatusCancelled {
+		if err := os.PaymentService.ReversePayment(orderId); err != nil {
+			logger.Errorf("unable to reverse the payment of cancelled order: %v, with error: %v", orderId, err)
+		}
+	}
+	return parseOrderTransition(*transition), nil
+}
+
+func (os *OrderServiceImpl) ListOrderTransitions(orderId int) ([]response.OrderTransitionResponse, error) {
+	if _, err := os.OrderRepository.FindOrderById(orderId); err != nil {
+		return nil, err
+	}
+
+	transitions, err := os.OrderRepository.ListOrderTransitions(orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(*transitions, func(i, j int) bool {
+		return (*transitions)[i].Id < (*transitions)[j].Id
+	})
+	resp := make([]response.OrderTransitionResponse, 0, len(*transitions))
+	for _, e := range *transitions {
+		resp = append(resp, parseOrderTransition(e))
+	}
+	return resp, nil
+}
+
+// CancelOrder lets a client cancel one of their orders as long as it has not
+// shipped yet.
+func (os *OrderServiceImpl) CancelOrder(clientId, orderId int, req request.CancelOrderRequest) (response.CancelOrderResponse, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return response.CancelOrderResponse{}, errors.New("a cancellation reason is required")
+	}
+	if len(reason) > MaxCancellationReasonLength {
+		return response.CancelOrderResponse{}, errors.New(fmt.Sprintf("the cancellation reason cannot exceed %v characters", MaxCancellationReasonLength))
+	}
+
+	order, err := os.OrderRepository.FindOrderForClient(clientId, orderId)
+	if err != nil {
+		return response.CancelOrderResponse{}, err
+	}
+	if !order.Status.CanTransitionTo(models.OrderStatusCancelled) {
+		return response.CancelOrderResponse{}, models.NewInvalidOrderTransitionError(order.Status, models.OrderStatusCancelled)
+	}
+
+	cancelled, err := os.OrderRepository.CancelOrder(orderId, order.Status, fmt.Sprintf("client:%v", clientId), reason)
+	if err != nil {
+		logger.Errorf("unable to cancel order: %v for the client: %v, with error: %v", orderId, clientId, err)
+		return response.CancelOrderResponse{}, err
+	}
+
+	// The cancellation stands even when the payment cannot be reversed now; the
+	// order keeps its refund flag so the refund can be retried.
+	err = os.PaymentService.ReversePayment(orderId)
+	if err != nil {
+		logger.Errorf("unable to reverse the payment of cancelled order: %v, with error: %v", orderId, err)
+	} else if cancelled.RefundPending {
+		cancelled.Status = models.OrderStatusRefunded
+		cancelled.RefundPending = false
 	}
 
-	return nil
+	return response.CancelOrderResponse{
+		OrderId:       cancelled.Id,
+		Status:        string(cancelled.Status),
+		Reason:        cancelled.CancellationReason,
+		RefundPending: cancelled.RefundPending,
+	}, nil
+}
+
+// ReleaseAbandonedOrders cancels the orders left pending past the timeout and
+// voids the authorizations they still hold, which could no longer be
+// confirmed once the order is cancelled.
+func (os *OrderServiceImpl) ReleaseAbandonedOrders() (int, error) {
+	released, err := os.OrderRepository.ReleaseAbandonedOrders(time.Now().Add(-AbandonedOrderTimeout))
+	for _, orderId := range released {
+		if errReverse := os.PaymentService.ReversePayment(orderId); errReverse != nil {
+			logger.Errorf("unable to void the payment of abandoned order: %v, with error: %v", orderId, errReverse)
+		}
+	}
+	if err != nil {
+		logger.Errorf("unable to release abandoned orders, released: %v, with error: %v", len(released), err)
+		return len(released), err
+	}
+	return len(released), nil
+}
+
+func parseOrderTransition(transition models.OrderTransition) response.OrderTransitionResponse {
+	return response.OrderTransitionResponse{
+		OrderId:   transition.OrderId,
+		From:      string(transition.FromStatus),
+		To:        string(transition.ToStatus),
+		Actor:     transition.Actor,
+		CreatedAt: transition.CreatedAt,
+	}
 }
@@ -0,0 +1,31 @@
+package services
+
+import "github.com/emiliocc5/online-store-api/internal/models/response"
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+func normalizePagination(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	return page, pageSize
+}
+
+func buildPagination(page, pageSize int, totalItems int64) response.PaginationResponse {
+	totalPages := int((totalItems + int64(pageSize) - 1) / int64(pageSize))
+	return response.PaginationResponse{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	}
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/payment"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+)
+
+const (
+	// PaymentsActor is recorded on the order transitions caused by payments.
+	PaymentsActor = "payments"
+)
+
+type (
+	PaymentService interface {
+		PayOrder(clientId, orderId int) (response.PaymentResponse, error)
+		ConfirmPayment(clientId, orderId int) (response.PaymentResponse, error)
+		ReversePayment(orderId int) error
+		RefundPayment(orderId int) error
+	}
+	PaymentServiceImpl struct {
+		OrderRepository   repository.OrderRepository
+		PaymentRepository repository.PaymentRepository
+		PaymentProvider   payment.PaymentProvider
+	}
+)
+
+// PayOrder charges the total of a pending order. The order moves to paid once
+// the amount is captured; when the provider asks the customer to confirm the
+// payment the order stays pending until ConfirmPayment is called.
+func (ps *PaymentServiceImpl) PayOrder(clientId, orderId int) (response.PaymentResponse, error) {
+	order, err := ps.findPendingOrder(clientId, orderId)
+	if err != nil {
+		return response.PaymentResponse{}, err
+	}
+
+	existing, err := ps.PaymentRepository.FindPaymentByOrderId(orderId)
+	if err != nil {
+		return response.PaymentResponse{}, err
+	}
+	pay := models.Payment{OrderId: orderId}
+	if existing != nil {
+		if existing.IsOpen() {
+			ps.voidAuthorization(existing)
+		}
+		pay = *existing
+	}
+	pay.Provider = ps.PaymentProvider.Name()
+	pay.Amount = order.Total
+	pay.AuthorizationId = ""
+
+	if order.Total.IsZero() {
+		pay.Status = models.PaymentStatusCaptured
+		return ps.completePayment(order, &pay)
+	}
+
+	authorization, err := ps.PaymentProvider.Authorize(payment.AuthorizationRequest{OrderId: orderId, Amount: order.Total})
+	if err != nil {
+		pay.Status = paymentFailureStatus(err)
+		ps.savePayment(&pay)
+		return response.PaymentResponse{}, err
+	}
+	pay.AuthorizationId = authorization.Id
+
+	if authorization.Status == payment.AuthorizationStatusRequiresConfirmation {
+		pay.Status = models.PaymentStatusRequiresConfirmation
+		if err := ps.PaymentRepository.SavePayment(&pay); err != nil {
+			return response.PaymentResponse{}, err
+		}
+		return parsePayment(*order, pay), nil
+	}
+
+	pay.Status = models.PaymentStatusAuthorized
+	return ps.capturePayment(order, &pay)
+}
+
+func (ps *PaymentServiceImpl) ConfirmPayment(clientId, orderId int) (response.PaymentResponse, error) {
+	order, err := ps.findPendingOrder(clientId, orderId)
+	if err != nil {
+		return response.PaymentResponse{}, err
+	}
+
+	pay, err := ps.PaymentRepository.FindPaymentByOrderId(orderId)
+	if err != nil {
+		return response.PaymentResponse{}, err
+	}
+	if pay == nil || pay.Status != models.PaymentStatusRequiresConfirmation {
+		return response.PaymentResponse{}, errors.New(fmt.Sprintf("the payment of the order: %v does not require confirmation", orderId))
+	}
+
+	_, err = ps.PaymentProvider.Confirm(pay.AuthorizationId)
+	if err != nil {
+		pay.Status = models.PaymentStatusFailed
+		ps.savePayment(pay)
+		return response.PaymentResponse{}, err
+	}
+
+	pay.Status = models.PaymentStatusAuthorized
+	return ps.capturePayment(order, pay)
+}
+
+// ReversePayment gives the money of a cancelled order back: open
+// authorizations are voided and captured payments refunded, which completes the
+// refund the cancellation flagged.
+func (ps *PaymentServiceImpl) ReversePayment(orderId int) error {
+	refunded, err := ps.refundPayment(orderId)
+	if err != nil || !refunded {
+		return err
+	}
+	_, err = ps.OrderRepository.TransitionOrder(orderId, models.OrderStatusCancelled, models.OrderStatusRefunded, PaymentsActor)
+	return err
+}
+
+// RefundPayment gives the money of an order back without moving the order,
+// for staff refunding it by hand. Payments already given back are left alone,
+// so a failed refund can be retried.
+func (ps *PaymentServiceImpl) RefundPayment(orderId int) error {
+	_, err := ps.refundPayment(orderId)
+	return err
+}
+
+// refundPayment voids the open authorization or refunds the captured payment
+// of the order, and reports whether captured money was refunded.
+func (ps *PaymentServiceImpl) refundPayment(orderId int) (bool, error) {
+	pay, err := ps.PaymentRepository.FindPaymentByOrderId(orderId)
+	if err != nil || pay == nil {
+		return false, err
+	}
+
+	switch {
+	case pay.IsOpen():
+		ps.voidAuthorization(pay)
+		return false, ps.PaymentRepository.SavePayment(pay)
+	case pay.Status == models.PaymentStatusCaptured:
+		if pay.AuthorizationId != "" {
+			if err := ps.PaymentProvider.Refund(pay.AuthorizationId, pay.Amount); err != nil {
+				logger.Errorf("unable to refund the payment of order: %v, with error: %v", orderId, err)
+				return false, err
+			}
+		}
+		pay.Status = models.PaymentStatusRefunded
+		if err := ps.PaymentRepository.SavePayment(pay); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (ps *PaymentServiceImpl) findPendingOrder(clientId, orderId int) (*models.Order, error) {
+	order, err := ps.OrderRepository.FindOrderForClient(clientId, orderId)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.OrderStatusPending {
+		return nil, models.NewInvalidOrderTransitionError(order.Status, models.OrderStatusPaid)
+	}
+	return order, nil
+}
+
+func (ps *PaymentServiceImpl) capturePayment(order *models.Order, pay *models.Payment) (response.PaymentResponse, error) {
+	err := ps.PaymentProvider.Capture(pay.AuthorizationId)
+	if err != nil {
+		ps.voidAuthorization(pay)
+		pay.Status = models.PaymentStatusFailed
+		ps.savePayment(pay)
+		return response.PaymentResponse{}, err
+	}
+	pay.Status = models.PaymentStatusCaptured
+	return ps.completePayment(order, pay)
+}
+
+// completePayment marks the order as paid. Should the order have left pending
+// in the meantime, for instance cancelled as abandoned, the captured money is
+// refunded right away.
+func (ps *PaymentServiceImpl) completePayment(order *models.Order, pay *models.Payment) (response.PaymentResponse, error) {
+	if err := ps.PaymentRepository.SavePayment(pay); err != nil {
+		return response.PaymentResponse{}, err
+	}
+
+	_, err := ps.OrderRepository.TransitionOrder(order.Id, models.OrderStatusPending, models.OrderStatusPaid, PaymentsActor)
+	if err != nil {
+		logger.Errorf("unable to mark order: %v as paid, with error: %v", order.Id, err)
+		if pay.AuthorizationId != "" {
+			if errRefund := ps.PaymentProvider.Refund(pay.AuthorizationId, pay.Amount); errRefund != nil {
+				logger.Errorf("unable to refund the payment of order: %v, with error: %v", order.Id, errRefund)
+				return response.PaymentResponse{}, err
+			}
+		}
+		pay.Status = models.PaymentStatusRefunded
+		ps.savePayment(pay)
+		return response.PaymentResponse{}, err
+	}
+
+	order.Status = models.OrderStatusPaid
+	return parsePayment(*order, *pay), nil
+}
+
+func (ps *PaymentServiceImpl) voidAuthorization(pay *models.Payment) {
+	if err := ps.PaymentProvider.Void(pay.AuthorizationId); err != nil {
+		logger.Errorf("unable to void authorization: %v of order: %v, with error: %v", pay.AuthorizationId, pay.OrderId, err)
+	}
+	pay.Status = models.PaymentStatusVoided
+}
+
+func (ps *PaymentServiceImpl) savePayment(pay *models.Payment) {
+	if err := ps.PaymentRepository.SavePayment(pay); err != nil {
+		logger.Errorf("unable to record the payment of order: %v, with error: %v", pay.OrderId, err)
+	}
+}
+
+func parsePayment(order models.Order, pay models.Payment) response.PaymentResponse {
+	return response.PaymentResponse{
+		OrderId:         order.Id,
+		OrderStatus:     string(order.Status),
+		Status:          string(pay.Status),
+		AuthorizationId: pay.AuthorizationId,
+		Amount:          parseMoney(pay.Amount),
+	}
+}
+
+// paymentFailureStatus tells a declined card from a provider that could not
+// be reached.
+func paymentFailureStatus(err error) models.PaymentStatus {
+	if errors.Is(err, payment.ErrPaymentDeclined) {
+		return models.PaymentStatusDeclined
+	}
+	return models.PaymentStatusFailed
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type (
+	ProductAdminService interface {
+		CreateProduct(req request.ProductRequest) (response.ProductResponse, error)
+		UpdateProduct(productId int, req request.ProductRequest) (response.ProductResponse, error)
+		PatchProduct(productId int, req request.PatchProductRequest) (response.ProductResponse, error)
+		ArchiveProduct(productId int) error
+	}
+	ProductAdminServiceImpl struct {
+		ProductRepository  repository.ProductRepository
+		CategoryRepository repository.CategoryRepository
+		// Currencies lists the codes prices can be in; any code is accepted
+		// when empty.
+		Currencies []string
+	}
+)
+
+func (ps *ProductAdminServiceImpl) CreateProduct(req request.ProductRequest) (response.ProductResponse, error) {
+	product := models.Product{}
+	if err := applyProductRequest(&product, req); err != nil {
+		return response.ProductResponse{}, err
+	}
+	if err := ps.validateProduct(&product); err != nil {
+		return response.ProductResponse{}, err
+	}
+
+	if err := ps.ProductRepository.CreateProduct(&product); err != nil {
+		return response.ProductResponse{}, err
+	}
+	return parseAdminProduct(product), nil
+}
+
+func (ps *ProductAdminServiceImpl) UpdateProduct(productId int, req request.ProductRequest) (response.ProductResponse, error) {
+	product, err := ps.findActiveProduct(productId)
+	if err != nil {
+		return response.ProductResponse{}, err
+	}
+
+	if err := applyProductRequest(product, req); err != nil {
+		return response.ProductResponse{}, err
+	}
+	return ps.saveProduct(product)
+}
+
+func (ps *ProductAdminServiceImpl) PatchProduct(productId int, req request.PatchProductRequest) (response.ProductResponse, error) {
+	product, err := ps.findActiveProduct(productId)
+	if err != nil {
+		return response.ProductResponse{}, err
+	}
+
+	if req.CategoryId != nil {
+		product.CategoryId = *req.CategoryId
+	}
+	if req.Label != nil {
+		product.Label = strings.TrimSpace(*req.Label)
+	}
+	if req.Type != nil {
+		product.Type = *req.Type
+	}
+	if req.DownloadUrl != nil {
+		product.DownloadUrl = strings.TrimSpace(*req.DownloadUrl)
+	}
+	if req.Weight != nil {
+		product.Weight = *req.Weight
+	}
+	if req.Price != nil {
+		price, err := models.NewMoney(req.Price.Amount, req.Price.Currency)
+		if err != nil {
+			return response.ProductResponse{}, err
+		}
+		product.Price = price
+	}
+	return ps.saveProduct(product)
+}
+
+func (ps *ProductAdminServiceImpl) ArchiveProduct(productId int) error {
+	product, err := ps.ProductRepository.FindProductById(productId)
+	if err != nil {
+		return errors.New(fmt.Sprintf("product with id: %v not found", productId))
+	}
+	if product.IsArchived() {
+		return nil
+	}
+
+	archivedAt := time.Now().UTC()
+	product.ArchivedAt = &archivedAt
+	return ps.ProductRepository.UpdateProduct(product)
+}
+
+func (ps *ProductAdminServiceImpl) findActiveProduct(productId int) (*models.Product, error) {
+	product, err := ps.ProductRepository.FindProductById(productId)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("product with id: %v not found", productId))
+	}
+	if product.IsArchived() {
+		return nil, errors.New(fmt.Sprintf("product with id: %v is archived", productId))
+	}
+	return product, nil
+}
+
+func (ps *ProductAdminServiceImpl) saveProduct(product *models.Product) (response.ProductResponse, error) {
+	if err := ps.validateProduct(product); err != nil {
+		return response.ProductResponse{}, err
+	}
+	if err := ps.ProductRepository.UpdateProduct(product); err != nil {
+		return response.ProductResponse{}, err
+	}
+	return parseAdminProduct(*product), nil
+}
+
+// validateProduct checks the fields every product needs plus the invariants of
+// its type: digital products are delivered through a download URL and weigh
+// nothing, physical products and bundles ship and therefore need a positive
+// weight, services are neither shipped nor downloaded.
+func (ps *ProductAdminServiceImpl) validateProduct(product *models.Product) error {
+	if product.Label == "" {
+		return errors.New("product label is required")
+	}
+	if product.Price.Amount < 0 {
+		return errors.New("product price cannot be negative")
+	}
+	if !ps.isCatalogCurrency(product.Price.Currency) {
+		return errors.New(fmt.Sprintf("product price must be in one of: %v", strings.Join(ps.Currencies, ", ")))
+	}
+	if !product.Type.IsValid() {
+		return errors.New("product type must be one of: physical, digital, service, bundle")
+	}
+
+	switch product.Type {
+	case models.ProductTypeDigital:
+		parsedUrl, err := url.ParseRequestURI(product.DownloadUrl)
+		if err != nil || (parsedUrl.Scheme != "https" && parsedUrl.Scheme != "http") || parsedUrl.Host == "" {
+			return errors.New("digital products require a valid download url")
+		}
+		if product.Weight != 0 {
+			return errors.New("digital products cannot have a weight")
+		}
+	case models.ProductTypePhysical, models.ProductTypeBundle:
+		if product.Weight <= 0 {
+			return errors.New(fmt.Sprintf("%v products require a positive weight", product.Type))
+		}
+		if product.DownloadUrl != "" {
+			return errors.New(fmt.Sprintf("%v products cannot have a download url", product.Type))
+		}
+	case models.ProductTypeService:
+		if product.Weight != 0 || product.DownloadUrl != "" {
+			return errors.New("service products cannot have a weight or a download url")
+		}
+	}
+
+	category, err := ps.CategoryRepository.FindCategoryById(product.CategoryId)
+	if err != nil {
+		return errors.New(fmt.Sprintf("category with id: %v not found", product.CategoryId))
+	}
+	product.Category = *category
+
+	return nil
+}
+
+func (ps *ProductAdminServiceImpl) isCatalogCurrency(currency string) bool {
+	if len(ps.Currencies) == 0 {
+		return true
+	}
+	for _, allowed := range ps.Currencies {
+		if allowed == currency {
+			return true
+		}
+	}
+	return false
+}
+
+func applyProductRequest(product *models.Product, req request.ProductRequest) error {
+	price, err := models.NewMoney(req.Price.Amount, req.Price.Currency)
+	if err != nil {
+		return err
+	}
+
+	product.CategoryId = req.CategoryId
+	product.Label = strings.TrimSpace(req.Label)
+	product.Type = req.Type
+	product.DownloadUrl = strings.TrimSpace(req.DownloadUrl)
+	product.Weight = req.Weight
+	product.Price = price
+	return nil
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/emiliocc5/online-store-api/internal/shipping"
+	"strings"
+)
+
+type (
+	ShippingService interface {
+		GetShippingQuotes(clientId int, req request.ShippingQuotesRequest) (response.ShippingQuotesResponse, error)
+		// SelectShippingOption prices the chosen option for the current cart of
+		// the client, shipped to one of its addresses or, when addressId is 0,
+		// to its default shipping address. It returns an empty selection when
+		// nothing in the cart has to be shipped.
+		SelectShippingOption(clientId, addressId int, option string) (models.OrderShipping, error)
+	}
+	ShippingServiceImpl struct {
+		CartRepository    repository.CartRepository
+		ClientRepository  repository.ClientRepository
+		AddressRepository repository.AddressRepository
+		Calculator        *shipping.Calculator
+		Zones             shipping.CountryZones
+	}
+)
+
+func (ss *ShippingServiceImpl) GetShippingQuotes(clientId int, req request.ShippingQuotesRequest) (response.ShippingQuotesResponse, error) {
+	resp := response.ShippingQuotesResponse{Quotes: []response.ShippingQuoteResponse{}}
+
+	weight, shippable, err := ss.cartWeight(clientId)
+	if err != nil {
+		return resp, err
+	}
+	if !shippable {
+		return resp, nil
+	}
+
+	zone := strings.TrimSpace(req.Zone)
+	if zone == "" {
+		address, err := ss.findShippingAddress(clientId, req.AddressId)
+		if err != nil {
+			return resp, err
+		}
+		if address == nil {
+			return resp, errors.New("a zone or a shipping address is required")
+		}
+		zone = ss.Zones.ZoneFor(address.Country)
+	}
+
+	quotes, err := ss.Calculator.Quote(zone, weight)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.ShippingRequired = true
+	resp.Zone = zone
+	resp.Weight = weight
+	for _, e := range quotes {
+		resp.Quotes = append(resp.Quotes, response.ShippingQuoteResponse{
+			Option:        e.Option,
+			Label:         e.Label,
+			EstimatedDays: e.Days,
+			Cost:          parseMoney(e.Cost),
+		})
+	}
+
+	return resp, nil
+}
+
+func (ss *ShippingServiceImpl) SelectShippingOption(clientId, addressId int, option string) (models.OrderShipping, error) {
+	weight, shippable, err := ss.cartWeight(clientId)
+	if err != nil || !shippable {
+		return models.OrderShipping{}, err
+	}
+
+	address, err := ss.findShippingAddress(clientId, addressId)
+	if err != nil {
+		return models.OrderShipping{}, err
+	}
+	option = strings.TrimSpace(option)
+	if address == nil || option == "" {
+		return models.OrderShipping{}, errors.New("a shipping address and option are required for carts with physical products")
+	}
+
+	quote, err := ss.Calculator.QuoteOption(ss.Zones.ZoneFor(address.Country), option, weight)
+	if err != nil {
+		return models.OrderShipping{}, err
+	}
+
+	return models.OrderShipping{Option: quote.Option, Zone: quote.Zone, Weight: weight, Cost: quote.Cost, Address: address.PostalAddress}, nil
+}
+
+func (ss *ShippingServiceImpl) findShippingAddress(clientId, addressId int) (*models.Address, error) {
+	if addressId == 0 {
+		return ss.AddressRepository.FindDefaultShippingAddress(clientId)
+	}
+	return ss.AddressRepository.FindAddress(clientId, addressId)
+}
+
+// cartWeight sums the weight of the products in the cart that have to be
+// shipped and reports whether there is any.
+func (ss *ShippingServiceImpl) cartWeight(clientId int) (float64, bool, error) {
+	if !ss.ClientRepository.IsClientInDataBase(clientId) {
+		return 0, false, errors.New(fmt.Sprintf("client with id: %v not found", clientId))
+	}
+
+	cart, err := ss.CartRepository.GetCartByClient(clientId)
+	if err != nil {
+		logger.Errorf("unable to get cart for the client: %v, with error: %v", clientId, err)
+		return 0, false, errors.New(fmt.Sprintf("unable to get the cart of the client: %v", clientId))
+	}
+
+	items, err := ss.CartRepository.GetCartItems(cart.Id)
+	if err != nil {
+		logger.Errorf("unable to get the list of products from the cart: %v, with error: %v", cart.Id, err)
+		return 0, false, errors.New(fmt.Sprintf("unable to get the list of products from the cart: %v", cart.Id))
+	}
+
+	weight := 0.0
+	shippable := false
+	for _, e := range *items {
+		if !e.Product.Type.IsShippable() {
+			continue
+		}
+		shippable = true
+		weight += e.Product.Weight * float64(e.Quantity)
+	}
+	return weight, shippable, nil
+}
+
+func parseOrderShipping(orderShipping models.OrderShipping) *response.OrderShippingResponse {
+	if orderShipping.IsEmpty() {
+		return nil
+	}
+	return &response.OrderShippingResponse{
+		Option:  orderShipping.Option,
+		Zone:    orderShipping.Zone,
+		Cost:    parseMoney(orderShipping.Cost),
+		Address: parsePostalAddress(orderShipping.Address),
+	}
+}
@@ -0,0 +1,180 @@
+package shipping
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"sort"
+	"strings"
+)
+
+const (
+	ZoneDomestic      = "domestic"
+	ZoneRegional      = "regional"
+	ZoneInternational = "international"
+
+	OptionStandard = "standard"
+	OptionExpress  = "express"
+)
+
+var (
+	ErrUnknownZone       = errors.New("unsupported shipping zone")
+	ErrUnavailableOption = errors.New("shipping option not available")
+)
+
+type (
+	// RateTable prices one shipping option to one zone. Rates are weight
+	// brackets sorted by MaxWeight; a parcel is charged the first bracket it
+	// fits in and the option is not offered above the last one.
+	RateTable struct {
+		Option string `json:"option" yaml:"option"`
+		Label  string `json:"label" yaml:"label"`
+		Zone   string `json:"zone" yaml:"zone"`
+		Days   int    `json:"days" yaml:"days"`
+		Rates  []Rate `json:"rates" yaml:"rates"`
+	}
+	Rate struct {
+		MaxWeight float64      `json:"maxWeight" yaml:"maxWeight"`
+		Cost      models.Money `json:"cost" yaml:"cost"`
+	}
+	Quote struct {
+		Option string
+		Label  string
+		Zone   string
+		Days   int
+		Cost   models.Money
+	}
+	Calculator struct {
+		tables []RateTable
+	}
+)
+
+// NewCalculator validates the rate tables and sorts their brackets by weight.
+// Every rate must be priced in one of currencies, the ones of the catalog, so
+// a cost that cannot be added to an order total is caught on startup.
+func NewCalculator(tables []RateTable, currencies []string) (*Calculator, error) {
+	allowed := make(map[string]bool, len(currencies))
+	for _, currency := range currencies {
+		allowed[strings.ToUpper(currency)] = true
+	}
+
+	seen := make(map[string]bool)
+	sorted := make([]RateTable, 0, len(tables))
+	for _, table := range tables {
+		if table.Option == "" || table.Zone == "" {
+			return nil, errors.New("shipping rate tables need an option and a zone")
+		}
+		key := table.Zone + "/" + table.Option
+		if seen[key] {
+			return nil, errors.New(fmt.Sprintf("duplicated shipping rate table: %v", key))
+		}
+		seen[key] = true
+		if len(table.Rates) == 0 {
+			return nil, errors.New(fmt.Sprintf("the shipping rate table: %v has no rates", key))
+		}
+
+		rates := append([]Rate(nil), table.Rates...)
+		sort.Slice(rates, func(i, j int) bool { return rates[i].MaxWeight < rates[j].MaxWeight })
+		for i, rate := range rates {
+			if rate.MaxWeight <= 0 || rate.Cost.Amount < 0 {
+				return nil, errors.New(fmt.Sprintf("the shipping rate table: %v has an invalid rate", key))
+			}
+			currency := strings.ToUpper(rate.Cost.Currency)
+			if !allowed[currency] {
+				return nil, errors.New(fmt.Sprintf("the shipping rate table: %v is priced in %v, which is not one of the currencies: %v",
+					key, rate.Cost.Currency, strings.Join(currencies, ", ")))
+			}
+			rates[i].Cost.Currency = currency
+		}
+		table.Rates = rates
+		sorted = append(sorted, table)
+	}
+	return &Calculator{tables: sorted}, nil
+}
+
+// Quote lists every option able to carry weight to zone, cheapest first.
+func (c *Calculator) Quote(zone string, weight float64) ([]Quote, error) {
+	if !c.hasZone(zone) {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownZone, zone)
+	}
+
+	var quotes []Quote
+	for _, table := range c.tables {
+		if table.Zone != zone {
+			continue
+		}
+		for _, rate := range table.Rates {
+			if weight <= rate.MaxWeight {
+				quotes = append(quotes, Quote{Option: table.Option, Label: table.Label, Zone: zone, Days: table.Days, Cost: rate.Cost})
+				break
+			}
+		}
+	}
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("%w: no option can carry %v kg to zone: %v", ErrUnavailableOption, weight, zone)
+	}
+
+	sort.SliceStable(quotes, func(i, j int) bool { return quotes[i].Cost.Amount < quotes[j].Cost.Amount })
+	return quotes, nil
+}
+
+func (c *Calculator) QuoteOption(zone, option string, weight float64) (Quote, error) {
+	quotes, err := c.Quote(zone, weight)
+	if err != nil {
+		return Quote{}, err
+	}
+	for _, quote := range quotes {
+		if quote.Option == option {
+			return quote, nil
+		}
+	}
+	return Quote{}, fmt.Errorf("%w: the option: %v cannot carry %v kg to zone: %v", ErrUnavailableOption, option, weight, zone)
+}
+
+// CheckZones makes sure every zone countries can fall in has a rate table, so
+// a misconfigured zone is caught on startup instead of at checkout.
+func (c *Calculator) CheckZones(zones CountryZones) error {
+	if zones.Fallback == "" {
+		return errors.New("the shipping zones need a fallback zone")
+	}
+	if !c.hasZone(zones.Fallback) {
+		return fmt.Errorf("%w: the fallback zone: %v has no rate table", ErrUnknownZone, zones.Fallback)
+	}
+	for country, zone := range zones.Countries {
+		if !c.hasZone(zone) {
+			return fmt.Errorf("%w: the zone: %v of the country: %v has no rate table", ErrUnknownZone, zone, country)
+		}
+	}
+	return nil
+}
+
+func (c *Calculator) hasZone(zone string) bool {
+	for _, table := range c.tables {
+		if table.Zone == zone {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRateTables are the rates used when none are configured. Weights are
+// in kilograms and costs in USD.
+func DefaultRateTables() []RateTable {
+	return []RateTable{
+		{Option: OptionStandard, Label: "Standard", Zone: ZoneDomestic, Days: 5, Rates: usdRates(1, 499, 5, 899, 20, 1499, 30, 2499)},
+		{Option: OptionExpress, Label: "Express", Zone: ZoneDomestic, Days: 2, Rates: usdRates(1, 1299, 5, 1999, 20, 3499)},
+		{Option: OptionStandard, Label: "Standard", Zone: ZoneRegional, Days: 8, Rates: usdRates(1, 999, 5, 1699, 20, 2999)},
+		{Option: OptionExpress, Label: "Express", Zone: ZoneRegional, Days: 3, Rates: usdRates(1, 1999, 5, 3499, 20, 5999)},
+		{Option: OptionStandard, Label: "Standard", Zone: ZoneInternational, Days: 15, Rates: usdRates(1, 1999, 5, 3999, 20, 7999)},
+		{Option: OptionExpress, Label: "Express", Zone: ZoneInternational, Days: 5, Rates: usdRates(1, 3999, 5, 6999)},
+	}
+}
+
+// usdRates builds brackets from (max weight, cost in cents) pairs.
+func usdRates(pairs ...float64) []Rate {
+	rates := make([]Rate, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		rates = append(rates, Rate{MaxWeight: pairs[i], Cost: models.Money{Amount: int64(pairs[i+1]), Currency: "USD"}})
+	}
+	return rates
+}
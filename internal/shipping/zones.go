@@ -0,0 +1,48 @@
+package shipping
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CountryZones tells which zone of the rate tables a destination country
+// belongs to. Countries not listed fall in Fallback.
+type CountryZones struct {
+	Countries map[string]string `json:"countries" yaml:"countries"`
+	Fallback  string            `json:"fallback" yaml:"fallback"`
+}
+
+func (cz CountryZones) ZoneFor(country string) string {
+	if zone, ok := cz.Countries[strings.ToUpper(country)]; ok {
+		return zone
+	}
+	return cz.Fallback
+}
+
+// Normalized upper-cases the country codes, which ZoneFor looks up
+// upper-cased, and rejects codes listed twice once upper-cased.
+func (cz CountryZones) Normalized() (CountryZones, error) {
+	countries := make(map[string]string, len(cz.Countries))
+	for country, zone := range cz.Countries {
+		code := strings.ToUpper(strings.TrimSpace(country))
+		if _, ok := countries[code]; ok {
+			return CountryZones{}, errors.New(fmt.Sprintf("duplicated shipping zone country: %v", code))
+		}
+		countries[code] = zone
+	}
+	return CountryZones{Countries: countries, Fallback: cz.Fallback}, nil
+}
+
+// DefaultCountryZones matches DefaultRateTables for a store shipping from the
+// US.
+func DefaultCountryZones() CountryZones {
+	return CountryZones{
+		Countries: map[string]string{
+			"US": ZoneDomestic,
+			"CA": ZoneRegional,
+			"MX": ZoneRegional,
+		},
+		Fallback: ZoneInternational,
+	}
+}
@@ -6,7 +6,24 @@ import (
 	"time"
 )
 
+// logger is shared by every package so the configured level applies to all.
+var logger = newLogger()
+
 func GetLogger() *logrus.Logger {
+	return logger
+}
+
+// SetLogLevel changes the level of the shared logger.
+func SetLogLevel(level string) error {
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	logger.SetLevel(parsed)
+	return nil
+}
+
+func newLogger() *logrus.Logger {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
 	logger.WithTime(time.Now())
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type (
+	AddressHandler interface {
+		HandleListAddresses(context *gin.Context)
+		HandleGetAddress(context *gin.Context)
+		HandleCreateAddress(context *gin.Context)
+		HandleUpdateAddress(context *gin.Context)
+		HandleDeleteAddress(context *gin.Context)
+	}
+	AddressHandlerImpl struct {
+		AddressService services.AddressService
+	}
+)
+
+func (ah *AddressHandlerImpl) HandleListAddresses(context *gin.Context) {
+	resp, err := ah.AddressService.ListAddresses(getClientIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ah *AddressHandlerImpl) HandleGetAddress(context *gin.Context) {
+	resp, err := ah.AddressService.GetAddress(getClientIdFromContext(context), getAddressIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusNotFound, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ah *AddressHandlerImpl) HandleCreateAddress(context *gin.Context) {
+	var body request.AddressRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ah.AddressService.CreateAddress(getClientIdFromContext(context), body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, resp)
+}
+
+func (ah *AddressHandlerImpl) HandleUpdateAddress(context *gin.Context) {
+	var body request.AddressRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ah.AddressService.UpdateAddress(getClientIdFromContext(context), getAddressIdFromContext(context), body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ah *AddressHandlerImpl) HandleDeleteAddress(context *gin.Context) {
+	err := ah.AddressService.DeleteAddress(getClientIdFromContext(context), getAddressIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+func getAddressIdFromContext(context *gin.Context) int {
+	addressId := context.Param("addressId")
+	intAddressId, err := strconv.Atoi(addressId)
+	if err != nil {
+		context.AbortWithStatus(http.StatusBadRequest)
+	}
+	return intAddressId
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type (
+	AdminProductHandler interface {
+		HandleCreateProduct(context *gin.Context)
+		HandleUpdateProduct(context *gin.Context)
+		HandlePatchProduct(context *gin.Context)
+		HandleArchiveProduct(context *gin.Context)
+		HandleGetStock(context *gin.Context)
+		HandleSetStock(context *gin.Context)
+	}
+	AdminProductHandlerImpl struct {
+		ProductAdminService services.ProductAdminService
+		InventoryService    services.InventoryService
+	}
+)
+
+func (ah *AdminProductHandlerImpl) HandleCreateProduct(context *gin.Context) {
+	var body request.ProductRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ah.ProductAdminService.CreateProduct(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, resp)
+}
+
+func (ah *AdminProductHandlerImpl) HandleUpdateProduct(context *gin.Context) {
+	var body request.ProductRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ah.ProductAdminService.UpdateProduct(getProductIdFromContext(context), body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ah *AdminProductHandlerImpl) HandlePatchProduct(context *gin.Context) {
+	var body request.PatchProductRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ah.ProductAdminService.PatchProduct(getProductIdFromContext(context), body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ah *AdminProductHandlerImpl) HandleArchiveProduct(context *gin.Context) {
+	err := ah.ProductAdminService.ArchiveProduct(getProductIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+func (ah *AdminProductHandlerImpl) HandleGetStock(context *gin.Context) {
+	resp, err := ah.InventoryService.GetStock(getProductIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ah *AdminProductHandlerImpl) HandleSetStock(context *gin.Context) {
+	var body request.SetStockRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ah.InventoryService.SetStock(getProductIdFromContext(context), body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type (
+	ApiKeyHandler interface {
+		HandleCreateApiKey(context *gin.Context)
+		HandleListApiKeys(context *gin.Context)
+		HandleRevokeApiKey(context *gin.Context)
+	}
+	ApiKeyHandlerImpl struct {
+		ApiKeyService services.ApiKeyService
+	}
+)
+
+func (ah *ApiKeyHandlerImpl) HandleCreateApiKey(context *gin.Context) {
+	var body request.ApiKeyRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ah.ApiKeyService.CreateApiKey(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, resp)
+}
+
+func (ah *ApiKeyHandlerImpl) HandleListApiKeys(context *gin.Context) {
+	resp, err := ah.ApiKeyService.ListApiKeys()
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ah *ApiKeyHandlerImpl) HandleRevokeApiKey(context *gin.Context) {
+	err := ah.ApiKeyService.RevokeApiKey(getApiKeyIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusNotFound, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+func getApiKeyIdFromContext(context *gin.Context) int {
+	apiKeyId := context.Param("apiKeyId")
+	intApiKeyId, err := strconv.Atoi(apiKeyId)
+	if err != nil {
+		context.AbortWithStatus(http.StatusBadRequest)
+	}
+	return intApiKeyId
+}
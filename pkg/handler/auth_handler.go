@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type (
+	AuthHandler interface {
+		HandleLogin(context *gin.Context)
+		HandleRefresh(context *gin.Context)
+		HandleLogout(context *gin.Context)
+		HandleResetPassword(context *gin.Context)
+		HandleIssuePasswordReset(context *gin.Context)
+	}
+	AuthHandlerImpl struct {
+		AuthService services.AuthService
+	}
+)
+
+func (ah *AuthHandlerImpl) HandleLogin(context *gin.Context) {
+	var body request.LoginRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ah.AuthService.Login(body)
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusInternalServerError), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ah *AuthHandlerImpl) HandleRefresh(context *gin.Context) {
+	var body request.RefreshTokenRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ah.AuthService.Refresh(body)
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusInternalServerError), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ah *AuthHandlerImpl) HandleLogout(context *gin.Context) {
+	var body request.RefreshTokenRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	err := ah.AuthService.Logout(body)
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusInternalServerError), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+func (ah *AuthHandlerImpl) HandleResetPassword(context *gin.Context) {
+	var body request.ResetPasswordRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	err := ah.AuthService.ResetPassword(body)
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+// HandleIssuePasswordReset lets staff give a client, such as one whose account
+// predates passwords, a way to set a new password.
+func (ah *AuthHandlerImpl) HandleIssuePasswordReset(context *gin.Context) {
+	clientId := getTargetClientIdFromContext(context)
+	if context.IsAborted() {
+		return
+	}
+
+	resp, err := ah.AuthService.IssuePasswordReset(clientId)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, resp)
+}
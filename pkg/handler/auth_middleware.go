@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+const (
+	// ClientIdKey and RoleKey are where Authenticate leaves the id and role of
+	// the client in the gin context.
+	ClientIdKey = "clientId"
+	RoleKey     = "role"
+	// ApiKeyPrefixKey is where the api key middleware leaves the prefix of the
+	// key, to tell integrations apart in logs and in the order history.
+	ApiKeyPrefixKey = "apiKeyPrefix"
+
+	bearerPrefix = "Bearer "
+)
+
+// Authenticate rejects requests without a valid access token in the
+// Authorization header and records who sent the others.
+func Authenticate(tokens *auth.TokenIssuer) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		header := context.GetHeader("Authorization")
+		if !strings.HasPrefix(header, bearerPrefix) {
+			context.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse{Error: "missing access token"})
+			return
+		}
+
+		clientId, role, err := tokens.ParseAccessToken(strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix)))
+		if err != nil {
+			context.AbortWithStatusJSON(http.StatusUnauthorized, response.ErrorResponse{Error: err.Error()})
+			return
+		}
+		context.Set(ClientIdKey, clientId)
+		context.Set(RoleKey, role)
+	}
+}
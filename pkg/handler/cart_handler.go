@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"github.com/emiliocc5/online-store-api/internal/models/request"
 	"github.com/emiliocc5/online-store-api/internal/models/response"
 	"github.com/emiliocc5/online-store-api/internal/services"
 	"github.com/gin-gonic/gin"
@@ -11,15 +12,34 @@ import (
 type (
 	CartHandler interface {
 		HandleGetCart(context *gin.Context)
+		HandleGetClientCart(context *gin.Context)
 		HandleAddProduct(context *gin.Context)
+		HandleSetProductQuantity(context *gin.Context)
+		HandleRemoveProduct(context *gin.Context)
+		HandleClearCart(context *gin.Context)
+		HandleGetShippingQuotes(context *gin.Context)
 	}
 	CartHandlerImpl struct {
-		CartService services.CartService
+		CartService     services.CartService
+		ShippingService services.ShippingService
 	}
 )
 
 func (ch *CartHandlerImpl) HandleGetCart(context *gin.Context) {
-	resp, err := ch.CartService.GetCart(getClientIdFromContext(context))
+	ch.getCart(context, getClientIdFromContext(context))
+}
+
+// HandleGetClientCart lets staff look at the cart of any client.
+func (ch *CartHandlerImpl) HandleGetClientCart(context *gin.Context) {
+	clientId := getTargetClientIdFromContext(context)
+	if context.IsAborted() {
+		return
+	}
+	ch.getCart(context, clientId)
+}
+
+func (ch *CartHandlerImpl) getCart(context *gin.Context, clientId int) {
+	resp, err := ch.CartService.GetCart(clientId)
 	if err != nil {
 		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
 		return
@@ -29,6 +49,39 @@ func (ch *CartHandlerImpl) HandleGetCart(context *gin.Context) {
 
 func (ch *CartHandlerImpl) HandleAddProduct(context *gin.Context) {
 	err := ch.CartService.AddProductToCart(getProductIdFromContext(context), getClientIdFromContext(context))
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+func (ch *CartHandlerImpl) HandleSetProductQuantity(context *gin.Context) {
+	var body request.SetProductQuantityRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	err := ch.CartService.SetProductQuantity(getProductIdFromContext(context), getClientIdFromContext(context), body.Quantity)
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+func (ch *CartHandlerImpl) HandleRemoveProduct(context *gin.Context) {
+	err := ch.CartService.RemoveProductFromCart(getProductIdFromContext(context), getClientIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+func (ch *CartHandlerImpl) HandleClearCart(context *gin.Context) {
+	err := ch.CartService.ClearCart(getClientIdFromContext(context))
 	if err != nil {
 		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
 		return
@@ -36,13 +89,28 @@ func (ch *CartHandlerImpl) HandleAddProduct(context *gin.Context) {
 	context.Status(http.StatusOK)
 }
 
+func (ch *CartHandlerImpl) HandleGetShippingQuotes(context *gin.Context) {
+	var query request.ShippingQuotesRequest
+	if err := context.ShouldBindQuery(&query); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid query parameters"})
+		return
+	}
+
+	resp, err := ch.ShippingService.GetShippingQuotes(getClientIdFromContext(context), query)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+// getClientIdFromContext returns the client authenticated by Authenticate.
 func getClientIdFromContext(context *gin.Context) int {
-	clientId := context.GetHeader("clientId")
-	intClientId, err1 := strconv.Atoi(clientId)
-	if err1 != nil {
-		context.AbortWithStatus(http.StatusBadRequest)
+	clientId := context.GetInt(ClientIdKey)
+	if clientId == 0 {
+		context.AbortWithStatus(http.StatusUnauthorized)
 	}
-	return intClientId
+	return clientId
 }
 
 func getProductIdFromContext(context *gin.Context) int {
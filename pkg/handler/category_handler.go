@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type (
+	CategoryHandler interface {
+		HandleListCategories(context *gin.Context)
+		HandleGetCategory(context *gin.Context)
+		HandleCreateCategory(context *gin.Context)
+		HandleUpdateCategory(context *gin.Context)
+		HandleDeleteCategory(context *gin.Context)
+		HandleListCategoryProducts(context *gin.Context)
+	}
+	CategoryHandlerImpl struct {
+		CategoryService services.CategoryService
+	}
+)
+
+func (ch *CategoryHandlerImpl) HandleListCategories(context *gin.Context) {
+	resp, err := ch.CategoryService.ListCategoryTree()
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ch *CategoryHandlerImpl) HandleGetCategory(context *gin.Context) {
+	resp, err := ch.CategoryService.GetCategory(getCategoryIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusNotFound, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ch *CategoryHandlerImpl) HandleCreateCategory(context *gin.Context) {
+	var body request.CategoryRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ch.CategoryService.CreateCategory(body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, resp)
+}
+
+func (ch *CategoryHandlerImpl) HandleUpdateCategory(context *gin.Context) {
+	var body request.CategoryRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ch.CategoryService.UpdateCategory(getCategoryIdFromContext(context), body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ch *CategoryHandlerImpl) HandleDeleteCategory(context *gin.Context) {
+	err := ch.CategoryService.DeleteCategory(getCategoryIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+func (ch *CategoryHandlerImpl) HandleListCategoryProducts(context *gin.Context) {
+	filter, err := getProductFilterFromContext(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	resp, err := ch.CategoryService.ListCategoryProducts(getCategoryIdFromContext(context), filter)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func getCategoryIdFromContext(context *gin.Context) int {
+	categoryId := context.Param("categoryId")
+	intCategoryId, err := strconv.Atoi(categoryId)
+	if err != nil {
+		context.AbortWithStatus(http.StatusBadRequest)
+	}
+	return intCategoryId
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type (
+	ClientHandler interface {
+		HandleSignUp(context *gin.Context)
+		HandleGetProfile(context *gin.Context)
+		HandleUpdateProfile(context *gin.Context)
+		HandleDeleteAccount(context *gin.Context)
+		HandleSetRole(context *gin.Context)
+	}
+	ClientHandlerImpl struct {
+		ClientService services.ClientService
+	}
+)
+
+func (ch *ClientHandlerImpl) HandleSignUp(context *gin.Context) {
+	var body request.SignUpRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ch.ClientService.SignUp(body)
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, resp)
+}
+
+func (ch *ClientHandlerImpl) HandleGetProfile(context *gin.Context) {
+	resp, err := ch.ClientService.GetProfile(getClientIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusNotFound, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ch *ClientHandlerImpl) HandleUpdateProfile(context *gin.Context) {
+	var body request.ClientRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ch.ClientService.UpdateProfile(getClientIdFromContext(context), body)
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ch *ClientHandlerImpl) HandleDeleteAccount(context *gin.Context) {
+	err := ch.ClientService.DeleteAccount(getClientIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.Status(http.StatusOK)
+}
+
+func (ch *ClientHandlerImpl) HandleSetRole(context *gin.Context) {
+	clientId := getTargetClientIdFromContext(context)
+	if context.IsAborted() {
+		return
+	}
+
+	var body request.SetRoleRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := ch.ClientService.SetRole(clientId, body)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+// getTargetClientIdFromContext returns the client a staff route acts upon, as
+// opposed to the one signed in.
+func getTargetClientIdFromContext(context *gin.Context) int {
+	clientId := context.Param("clientId")
+	intClientId, err := strconv.Atoi(clientId)
+	if err != nil {
+		context.AbortWithStatus(http.StatusBadRequest)
+	}
+	return intClientId
+}
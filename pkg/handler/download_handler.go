@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/downloads"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+type (
+	DownloadHandler interface {
+		HandleIssueDownloadLink(context *gin.Context)
+		HandleDownload(context *gin.Context)
+	}
+	DownloadHandlerImpl struct {
+		DownloadService services.DownloadService
+	}
+)
+
+func (dh *DownloadHandlerImpl) HandleIssueDownloadLink(context *gin.Context) {
+	resp, err := dh.DownloadService.IssueDownloadLink(getClientIdFromContext(context), getOrderIdFromContext(context),
+		getProductIdFromContext(context))
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+// HandleDownload follows a signed link, which is what grants access, and
+// redirects to the file when its signature holds.
+func (dh *DownloadHandlerImpl) HandleDownload(context *gin.Context) {
+	expires, err := strconv.ParseInt(context.Query("expires"), 10, 64)
+	if err != nil {
+		context.JSON(http.StatusForbidden, response.ErrorResponse{Error: downloads.ErrInvalidLink.Error()})
+		return
+	}
+
+	url, err := dh.DownloadService.RedeemDownloadLink(request.RedeemDownloadRequest{
+		OrderId:   getOrderIdFromContext(context),
+		ProductId: getProductIdFromContext(context),
+		Expires:   expires,
+		Signature: context.Query("signature"),
+		IpAddress: context.ClientIP(),
+		UserAgent: context.GetHeader("User-Agent"),
+	})
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.Redirect(http.StatusFound, url)
+}
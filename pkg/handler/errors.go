@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/downloads"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/payment"
+	"net/http"
+)
+
+// getErrorStatus maps errors that are not about the request itself to their
+// status: the current state of a resource, such as running out of stock, a
+// full cart line, an order that already moved on or a cart that changed after
+// shipping was quoted, gives 409, payment failures 402 or 504,
+// downloads that are not allowed 403 and failed logins or bad tokens 401.
+// Anything else gets fallback.
+func getErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, models.ErrInsufficientStock), errors.Is(err, models.ErrInvalidOrderTransition),
+		errors.Is(err, models.ErrEmailTaken), errors.Is(err, models.ErrStaleShippingQuote),
+		errors.Is(err, models.ErrCartQuantityLimit):
+		return http.StatusConflict
+	case errors.Is(err, payment.ErrPaymentDeclined):
+		return http.StatusPaymentRequired
+	case errors.Is(err, payment.ErrPaymentTimeout):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, downloads.ErrInvalidLink), errors.Is(err, downloads.ErrDownloadNotAllowed),
+		errors.Is(err, models.ErrDownloadLimitReached):
+		return http.StatusForbidden
+	case errors.Is(err, auth.ErrInvalidCredentials), errors.Is(err, auth.ErrInvalidToken):
+		return http.StatusUnauthorized
+	}
+	return fallback
+}
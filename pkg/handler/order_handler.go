@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+type (
+	OrderHandler interface {
+		HandleCreateOrder(context *gin.Context)
+		HandleGetOrder(context *gin.Context)
+		HandleListOrders(context *gin.Context)
+		HandleGetClientOrder(context *gin.Context)
+		HandleListClientOrders(context *gin.Context)
+		// HandleTransitionOrder records the authenticated principal as the actor.
+		HandleTransitionOrder(context *gin.Context)
+		HandleListOrderTransitions(context *gin.Context)
+		HandleCancelOrder(context *gin.Context)
+		HandlePayOrder(context *gin.Context)
+		HandleConfirmPayment(context *gin.Context)
+	}
+	OrderHandlerImpl struct {
+		OrderService   services.OrderService
+		PaymentService services.PaymentService
+	}
+)
+
+// HandleCreateOrder accepts an empty body for carts that need no shipping.
+func (oh *OrderHandlerImpl) HandleCreateOrder(context *gin.Context) {
+	var body request.CreateOrderRequest
+	if err := context.ShouldBindJSON(&body); err != nil && !errors.Is(err, io.EOF) {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := oh.OrderService.CreateOrder(getClientIdFromContext(context), body)
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, resp)
+}
+
+func (oh *OrderHandlerImpl) HandleGetOrder(context *gin.Context) {
+	oh.getOrder(context, getClientIdFromContext(context))
+}
+
+func (oh *OrderHandlerImpl) HandleListOrders(context *gin.Context) {
+	oh.listOrders(context, getClientIdFromContext(context))
+}
+
+// HandleGetClientOrder lets staff look at an order of any client.
+func (oh *OrderHandlerImpl) HandleGetClientOrder(context *gin.Context) {
+	clientId := getTargetClientIdFromContext(context)
+	if context.IsAborted() {
+		return
+	}
+	oh.getOrder(context, clientId)
+}
+
+// HandleListClientOrders lets staff list the orders of any client.
+func (oh *OrderHandlerImpl) HandleListClientOrders(context *gin.Context) {
+	clientId := getTargetClientIdFromContext(context)
+	if context.IsAborted() {
+		return
+	}
+	oh.listOrders(context, clientId)
+}
+
+func (oh *OrderHandlerImpl) getOrder(context *gin.Context, clientId int) {
+	resp, err := oh.OrderService.GetProductsFromOrder(clientId, getOrderIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (oh *OrderHandlerImpl) listOrders(context *gin.Context, clientId int) {
+	page, pageSize, err := getPaginationFromContext(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	resp, err := oh.OrderService.ListOrdersForClient(clientId, page, pageSize)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (oh *OrderHandlerImpl) HandleTransitionOrder(context *gin.Context) {
+	var body request.OrderTransitionRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	actor := getActorFromContext(context)
+	if context.IsAborted() {
+		return
+	}
+
+	resp, err := oh.OrderService.TransitionOrder(getOrderIdFromContext(context), actor, body)
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusCreated, resp)
+}
+
+func (oh *OrderHandlerImpl) HandleListOrderTransitions(context *gin.Context) {
+	resp, err := oh.OrderService.ListOrderTransitions(getOrderIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (oh *OrderHandlerImpl) HandleCancelOrder(context *gin.Context) {
+	var body request.CancelOrderRequest
+	if err := context.ShouldBindJSON(&body); err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: "invalid request body"})
+		return
+	}
+
+	resp, err := oh.OrderService.CancelOrder(getClientIdFromContext(context), getOrderIdFromContext(context), body)
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (oh *OrderHandlerImpl) HandlePayOrder(context *gin.Context) {
+	resp, err := oh.PaymentService.PayOrder(getClientIdFromContext(context), getOrderIdFromContext(context))
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (oh *OrderHandlerImpl) HandleConfirmPayment(context *gin.Context) {
+	resp, err := oh.PaymentService.ConfirmPayment(getClientIdFromContext(context), getOrderIdFromContext(context))
+	if err != nil {
+		context.JSON(getErrorStatus(err, http.StatusBadRequest), response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+// getActorFromContext names who sent the request for the order history: the
+// api key when one was used, otherwise the signed-in client and their role.
+func getActorFromContext(context *gin.Context) string {
+	if prefix := context.GetString(ApiKeyPrefixKey); prefix != "" {
+		return models.ApiKeyActor(prefix)
+	}
+	role, _ := context.Get(RoleKey)
+	roleValue, _ := role.(models.Role)
+	return models.StaffActor(getClientIdFromContext(context), roleValue)
+}
+
+func getOrderIdFromContext(context *gin.Context) int {
+	orderId := context.Param("orderId")
+	intOrderId, err := strconv.Atoi(orderId)
+	if err != nil {
+		context.AbortWithStatus(http.StatusBadRequest)
+	}
+	return intOrderId
+}
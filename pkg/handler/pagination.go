@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"strconv"
+)
+
+func getPaginationFromContext(context *gin.Context) (int, int, error) {
+	page, err := getOptionalIntQuery(context, "page")
+	if err != nil {
+		return 0, 0, errors.New("page must be a number")
+	}
+	pageSize, err := getOptionalIntQuery(context, "pageSize")
+	if err != nil {
+		return 0, 0, errors.New("pageSize must be a number")
+	}
+	return page, pageSize, nil
+}
+
+func getOptionalIntQuery(context *gin.Context, key string) (int, error) {
+	value := context.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
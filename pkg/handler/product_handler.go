@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type (
+	ProductHandler interface {
+		HandleListProducts(context *gin.Context)
+		HandleGetProduct(context *gin.Context)
+	}
+	ProductHandlerImpl struct {
+		CatalogService services.CatalogService
+	}
+)
+
+func (ph *ProductHandlerImpl) HandleListProducts(context *gin.Context) {
+	filter, err := getProductFilterFromContext(context)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	resp, err := ph.CatalogService.ListProducts(filter)
+	if err != nil {
+		context.JSON(http.StatusBadRequest, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func (ph *ProductHandlerImpl) HandleGetProduct(context *gin.Context) {
+	resp, err := ph.CatalogService.GetProduct(getProductIdFromContext(context))
+	if err != nil {
+		context.JSON(http.StatusNotFound, response.ErrorResponse{Error: err.Error()})
+		return
+	}
+	context.JSON(http.StatusOK, resp)
+}
+
+func getProductFilterFromContext(context *gin.Context) (models.ProductFilter, error) {
+	filter := models.ProductFilter{
+		Label: context.Query("label"),
+		Sort:  context.Query("sort"),
+	}
+
+	categoryId, err := getOptionalIntQuery(context, "categoryId")
+	if err != nil {
+		return filter, errors.New("categoryId must be a number")
+	}
+	if categoryId != 0 {
+		filter.CategoryIds = []int{categoryId}
+	}
+
+	if context.Query("type") != "" {
+		productType, err := models.ParseProductType(context.Query("type"))
+		if err != nil {
+			return filter, err
+		}
+		filter.Type = &productType
+	}
+
+	filter.Page, filter.PageSize, err = getPaginationFromContext(context)
+	if err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/app"
+	"github.com/emiliocc5/online-store-api/internal/config"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_GivenAValidConfig_ThenBuildTheApp(t *testing.T) {
+	a, err := app.New(config.Default(), repository.Repositories{})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, a.CartHandler)
+	assert.NotNil(t, a.Authenticate)
+	assert.NotNil(t, a.Tokens)
+}
+
+func Test_GivenAnUnknownPaymentBehavior_ThenUnableToBuildTheApp(t *testing.T) {
+	cfg := config.Default()
+	cfg.Payment.FakeBehavior = "maybe"
+
+	_, err := app.New(cfg, repository.Repositories{})
+
+	assert.EqualError(t, err, "unable to create the payment provider: unsupported fake payment behavior: maybe")
+}
+
+func Test_GivenAShortSigningSecret_ThenUnableToBuildTheApp(t *testing.T) {
+	cfg := config.Default()
+	cfg.Auth.SigningSecret = "short"
+
+	_, err := app.New(cfg, repository.Repositories{})
+
+	assert.EqualError(t, err, "unable to create the token issuer: the token signing secret must have at least 32 bytes")
+}
+
+func Test_GivenACatalogInAnotherCurrencyThanTheRates_ThenUnableToBuildTheApp(t *testing.T) {
+	cfg := config.Default()
+	cfg.Catalog.Currencies = []string{"EUR"}
+
+	_, err := app.New(cfg, repository.Repositories{})
+
+	assert.EqualError(t, err, "unable to load the shipping rates: the shipping rate table: domestic/standard is priced in USD, which is not one of the currencies: EUR")
+}
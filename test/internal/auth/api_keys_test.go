@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func Test_GivenANewApiKey_ThenItsPrefixIdentifiesIt(t *testing.T) {
+	key, prefix, hash := auth.NewApiKey()
+
+	assert.True(t, strings.HasPrefix(key, "osk_"+prefix+"_"))
+	assert.Equal(t, hash, auth.HashApiKey(key))
+
+	parsed, err := auth.ApiKeyPrefix(key)
+
+	assert.Nil(t, err)
+	assert.Equal(t, prefix, parsed)
+}
+
+func Test_GivenAMalformedApiKey_ThenUnableToGetItsPrefix(t *testing.T) {
+	for _, key := range []string{"", "osk_short_secret", "key_a1b2c3d4_secret", "osk_a1b2c3d4_"} {
+		_, err := auth.ApiKeyPrefix(key)
+
+		assert.Equal(t, auth.ErrInvalidApiKey, err, key)
+	}
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const (
+	aValidClientId = 42
+)
+
+var (
+	aValidSecret = []byte("0123456789abcdef0123456789abcdef")
+)
+
+func Test_GivenAnIssuedToken_ThenParseReturnsTheClient(t *testing.T) {
+	issuer, _ := auth.NewTokenIssuer(aValidSecret, time.Minute)
+
+	token, expiresAt, err := issuer.IssueAccessToken(aValidClientId, models.RoleCustomer, time.Now())
+	assert.Nil(t, err)
+	assert.True(t, expiresAt.After(time.Now()))
+
+	clientId, role, err := issuer.ParseAccessToken(token)
+
+	assert.Nil(t, err)
+	assert.Equal(t, aValidClientId, clientId)
+	assert.Equal(t, models.RoleCustomer, role)
+}
+
+func Test_GivenAnExpiredToken_ThenUnableToParse(t *testing.T) {
+	issuer, _ := auth.NewTokenIssuer(aValidSecret, time.Minute)
+
+	token, _, _ := issuer.IssueAccessToken(aValidClientId, models.RoleCustomer, time.Now().Add(-time.Hour))
+
+	_, _, err := issuer.ParseAccessToken(token)
+
+	assert.Equal(t, auth.ErrInvalidToken, err)
+}
+
+func Test_GivenATokenSignedWithAnotherSecret_ThenUnableToParse(t *testing.T) {
+	issuer, _ := auth.NewTokenIssuer(aValidSecret, time.Minute)
+	other, _ := auth.NewTokenIssuer([]byte("fedcba9876543210fedcba9876543210"), time.Minute)
+
+	token, _, _ := other.IssueAccessToken(aValidClientId, models.RoleCustomer, time.Now())
+
+	_, _, err := issuer.ParseAccessToken(token)
+
+	assert.Equal(t, auth.ErrInvalidToken, err)
+}
+
+func Test_GivenAnUnsignedToken_ThenUnableToParse(t *testing.T) {
+	issuer, _ := auth.NewTokenIssuer(aValidSecret, time.Minute)
+
+	claims := jwt.RegisteredClaims{Issuer: "online-store-api", Subject: "42", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+	_, _, err := issuer.ParseAccessToken(token)
+
+	assert.Equal(t, auth.ErrInvalidToken, err)
+}
+
+func Test_GivenAShortSecret_ThenUnableToCreateIssuer(t *testing.T) {
+	_, err := auth.NewTokenIssuer([]byte("short"), time.Minute)
+
+	assert.EqualError(t, err, "the token signing secret must have at least 32 bytes")
+}
+
+func Test_GivenARefreshToken_ThenItsHashIsStable(t *testing.T) {
+	token, hash := auth.NewRefreshToken()
+
+	assert.NotEqual(t, token, hash)
+	assert.Equal(t, hash, auth.HashRefreshToken(token))
+}
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/config"
+	"github.com/emiliocc5/online-store-api/internal/shipping"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_GivenNoSources_ThenLoadTheDefaults(t *testing.T) {
+	cfg, err := config.LoadFrom(filepath.Join(t.TempDir(), ".env"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, config.Default(), cfg)
+}
+
+func Test_GivenADotEnvFile_ThenItsValuesAreLoaded(t *testing.T) {
+	dotEnv := writeFile(t, ".env", `
+# database
+DB_HOST=db.internal
+DB_PORT=6543
+export DB_PASSWORD="a secret"
+HTTP_READ_TIMEOUT=3s
+FEATURE_BACKGROUND_JOBS=false
+`)
+
+	cfg, err := config.LoadFrom(dotEnv)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, 6543, cfg.Database.Port)
+	assert.Equal(t, "a secret", cfg.Database.Password)
+	assert.Equal(t, 3*time.Second, cfg.Server.ReadTimeout)
+	assert.False(t, cfg.Features.BackgroundJobs)
+}
+
+func Test_GivenTheSameSettingEverywhere_ThenTheEnvironmentWins(t *testing.T) {
+	yamlFile := writeFile(t, "config.yaml", "server:\n  port: 7000\nlog:\n  level: warn\ndatabase:\n  name: FromYaml\n")
+	dotEnv := writeFile(t, ".env", "CONFIG_FILE="+yamlFile+"\nPORT=7001\nLOG_LEVEL=error\n")
+	t.Setenv("PORT", "7002")
+
+	cfg, err := config.LoadFrom(dotEnv)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 7002, cfg.Server.Port)
+	assert.Equal(t, "error", cfg.Log.Level)
+	assert.Equal(t, "FromYaml", cfg.Database.Name)
+}
+
+func Test_GivenAYamlFile_ThenDurationsAndTogglesAreLoaded(t *testing.T) {
+	yamlFile := writeFile(t, "config.yaml", `
+server:
+  writeTimeout: 45s
+database:
+  connectTimeout: 2s
+features:
+  autoMigrate: false
+payment:
+  fakeBehavior: decline
+`)
+	t.Setenv(config.ConfigFileEnv, yamlFile)
+
+	cfg, err := config.LoadFrom(filepath.Join(t.TempDir(), ".env"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 45*time.Second, cfg.Server.WriteTimeout)
+	assert.Equal(t, 2*time.Second, cfg.Database.ConnectTimeout)
+	assert.False(t, cfg.Features.AutoMigrate)
+	assert.Equal(t, "decline", cfg.Payment.FakeBehavior)
+}
+
+func Test_GivenAnUnknownYamlKey_ThenUnableToLoad(t *testing.T) {
+	t.Setenv(config.ConfigFileEnv, writeFile(t, "config.yaml", "server:\n  prot: 8080\n"))
+
+	_, err := config.LoadFrom(filepath.Join(t.TempDir(), ".env"))
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "field prot not found")
+}
+
+func Test_GivenAMissingYamlFile_ThenUnableToLoad(t *testing.T) {
+	t.Setenv(config.ConfigFileEnv, filepath.Join(t.TempDir(), "missing.yaml"))
+
+	_, err := config.LoadFrom(filepath.Join(t.TempDir(), ".env"))
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "unable to read the config file")
+}
+
+func Test_GivenAMalformedEnvValue_ThenUnableToLoad(t *testing.T) {
+	t.Setenv("HTTP_IDLE_TIMEOUT", "forever")
+
+	_, err := config.LoadFrom(filepath.Join(t.TempDir(), ".env"))
+
+	assert.EqualError(t, err, "invalid value for HTTP_IDLE_TIMEOUT: forever")
+}
+
+func Test_GivenAMalformedDotEnvLine_ThenUnableToLoad(t *testing.T) {
+	dotEnv := writeFile(t, ".env", "PORT=8080\nDB_HOST\n")
+
+	_, err := config.LoadFrom(dotEnv)
+
+	assert.EqualError(t, err, dotEnv+":2: expected KEY=VALUE")
+}
+
+func Test_GivenInvalidSettings_ThenReportThemAll(t *testing.T) {
+	cfg := config.Default()
+	cfg.Server.Port = 0
+	cfg.Database.Host = ""
+	cfg.Log.Level = "loud"
+	cfg.Auth.SigningSecret = "short"
+	cfg.Payment.FakeBehavior = "maybe"
+
+	err := cfg.Validate()
+
+	assert.EqualError(t, err, "invalid configuration: server.port must be between 1 and 65535; "+
+		"database.host is required; unsupported log.level: loud; "+
+		"auth.signingSecret must have at least 32 characters; unsupported payment.fakeBehavior: maybe")
+}
+
+func Test_GivenDatabaseSettings_ThenBuildTheDSN(t *testing.T) {
+	db := config.Default().Database
+	db.Password = "it's secret"
+
+	assert.Equal(t, `host=localhost port=5432 user=goApiUser password='it\'s secret' dbname=OnlineStore sslmode=disable connect_timeout=5`, db.DSN())
+}
+
+func Test_GivenTheMemoryDriver_ThenTheDatabaseSettingsAreNotRequired(t *testing.T) {
+	t.Setenv("DB_DRIVER", config.DriverMemory)
+	t.Setenv("DB_HOST", "")
+
+	cfg, err := config.LoadFrom(filepath.Join(t.TempDir(), ".env"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, config.DriverMemory, cfg.Database.Driver)
+	assert.Nil(t, cfg.Validate())
+}
+
+func Test_GivenAnUnknownDriver_ThenReportIt(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database.Driver = "oracle"
+
+	err := cfg.Validate()
+
+	assert.EqualError(t, err, "invalid configuration: unsupported database.driver: oracle")
+}
+
+func Test_GivenTheSqliteDriver_ThenOnlyThePathIsRequired(t *testing.T) {
+	cfg := config.Default()
+	cfg.Database.Driver = config.DriverSqlite
+	cfg.Database.Host = ""
+	cfg.Database.Path = " "
+
+	err := cfg.Validate()
+
+	assert.EqualError(t, err, "invalid configuration: database.path is required")
+}
+
+func Test_GivenSqliteSettings_ThenBuildTheDSN(t *testing.T) {
+	db := config.Default().Database
+	db.Driver = config.DriverSqlite
+	db.Path = "/tmp/store.db"
+
+	assert.Equal(t, "file:/tmp/store.db?_foreign_keys=1&_busy_timeout=5000", db.DSN())
+}
+
+func Test_GivenShippingRatesInTheYamlFile_ThenTheyReplaceTheBuiltInOnes(t *testing.T) {
+	yamlFile := writeFile(t, "config.yaml", `
+catalog:
+  currencies: [eur]
+shipping:
+  rateTables:
+    - option: standard
+      label: Standard
+      zone: local
+      days: 1
+      rates:
+        - maxWeight: 10
+          cost: {amount: 300, currency: EUR}
+  zones:
+    countries:
+      es: local
+    fallback: local
+`)
+	t.Setenv(config.ConfigFileEnv, yamlFile)
+
+	cfg, err := config.LoadFrom(filepath.Join(t.TempDir(), ".env"))
+
+	assert.Nil(t, err)
+	tables := cfg.Shipping.RateTablesOrDefault()
+	assert.Len(t, tables, 1)
+	assert.Equal(t, "local", tables[0].Zone)
+	assert.Equal(t, 10.0, tables[0].Rates[0].MaxWeight)
+	assert.Equal(t, int64(300), tables[0].Rates[0].Cost.Amount)
+	assert.Equal(t, "local", cfg.Shipping.ZonesOrDefault().ZoneFor("es"))
+	assert.Equal(t, "local", cfg.Shipping.ZonesOrDefault().Countries["ES"])
+	assert.Equal(t, []string{"EUR"}, cfg.Catalog.Currencies)
+}
+
+func Test_GivenACountryListedTwiceInTheZones_ThenUnableToLoad(t *testing.T) {
+	yamlFile := writeFile(t, "config.yaml", `
+shipping:
+  zones:
+    countries:
+      ES: local
+      es: international
+    fallback: international
+`)
+	t.Setenv(config.ConfigFileEnv, yamlFile)
+
+	_, err := config.LoadFrom(filepath.Join(t.TempDir(), ".env"))
+
+	assert.EqualError(t, err, "invalid config file "+yamlFile+": duplicated shipping zone country: ES")
+}
+
+func Test_GivenNoCatalogCurrencies_ThenReportThem(t *testing.T) {
+	cfg := config.Default()
+	cfg.Catalog.Currencies = nil
+
+	assert.EqualError(t, cfg.Validate(), "invalid configuration: catalog.currencies needs at least one currency")
+
+	cfg.Catalog.Currencies = []string{"EURO"}
+
+	assert.EqualError(t, cfg.Validate(), "invalid configuration: unsupported catalog.currencies entry: EURO")
+}
+
+func Test_GivenNoShippingSettings_ThenUseTheBuiltInRates(t *testing.T) {
+	cfg := config.Default()
+
+	assert.Equal(t, shipping.DefaultRateTables(), cfg.Shipping.RateTablesOrDefault())
+	assert.Equal(t, shipping.DefaultCountryZones(), cfg.Shipping.ZonesOrDefault())
+}
@@ -0,0 +1,63 @@
+package downloads
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/downloads"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+var (
+	aSecret   = []byte("a-secret-long-enough-to-sign")
+	issuedAt  = time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+	aLifetime = 15 * time.Minute
+)
+
+func Test_GivenASignedLink_ThenVerifyBeforeItExpires(t *testing.T) {
+	signer, _ := downloads.NewSigner(aSecret, aLifetime)
+
+	link := signer.Sign(10, 7, issuedAt)
+
+	assert.Equal(t, issuedAt.Add(aLifetime), link.ExpiresAt)
+	assert.Nil(t, signer.Verify(link, issuedAt.Add(time.Minute)))
+}
+
+func Test_GivenAnExpiredLink_ThenUnableToVerify(t *testing.T) {
+	signer, _ := downloads.NewSigner(aSecret, aLifetime)
+
+	link := signer.Sign(10, 7, issuedAt)
+
+	err := signer.Verify(link, issuedAt.Add(aLifetime))
+
+	assert.True(t, errors.Is(err, downloads.ErrInvalidLink))
+	assert.EqualError(t, err, "invalid download link: the link expired at 2022-03-01T10:15:00Z")
+}
+
+func Test_GivenATamperedLink_ThenUnableToVerify(t *testing.T) {
+	signer, _ := downloads.NewSigner(aSecret, aLifetime)
+
+	link := signer.Sign(10, 7, issuedAt)
+	otherProduct := link
+	otherProduct.ProductId = 8
+	extended := link
+	extended.ExpiresAt = link.ExpiresAt.Add(time.Hour)
+
+	assert.True(t, errors.Is(signer.Verify(otherProduct, issuedAt), downloads.ErrInvalidLink))
+	assert.True(t, errors.Is(signer.Verify(extended, issuedAt), downloads.ErrInvalidLink))
+}
+
+func Test_GivenAnotherSecret_ThenUnableToVerify(t *testing.T) {
+	signer, _ := downloads.NewSigner(aSecret, aLifetime)
+	otherSigner, _ := downloads.NewSigner([]byte("another-secret-long-enough"), aLifetime)
+
+	link := signer.Sign(10, 7, issuedAt)
+
+	assert.True(t, errors.Is(otherSigner.Verify(link, issuedAt), downloads.ErrInvalidLink))
+}
+
+func Test_GivenAShortSecret_ThenUnableToCreateSigner(t *testing.T) {
+	_, err := downloads.NewSigner([]byte("short"), aLifetime)
+
+	assert.EqualError(t, err, "the download signing secret must have at least 16 bytes")
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_GivenAValidCurrencyCode_ThenCreateMoney(t *testing.T) {
+	money, err := models.NewMoney(1050, "usd")
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.Money{Amount: 1050, Currency: "USD"}, money)
+}
+
+func Test_GivenAnInvalidCurrencyCode_ThenUnableToCreateMoney(t *testing.T) {
+	_, err := models.NewMoney(1050, "dollars")
+
+	assert.EqualError(t, err, "invalid currency code: DOLLARS")
+}
+
+func Test_GivenAmountsInTheSameCurrency_ThenAddThem(t *testing.T) {
+	total, err := models.Money{}.Add(models.Money{Amount: 10, Currency: "EUR"})
+	assert.Nil(t, err)
+
+	total, err = total.Add(models.Money{Amount: 25, Currency: "EUR"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.Money{Amount: 35, Currency: "EUR"}, total)
+}
+
+func Test_GivenAmountsInDifferentCurrencies_ThenUnableToAddThem(t *testing.T) {
+	_, err := models.Money{Amount: 10, Currency: "EUR"}.Add(models.Money{Amount: 10, Currency: "USD"})
+
+	assert.ErrorIs(t, err, models.ErrCurrencyMismatch)
+}
+
+func Test_GivenAQuantity_ThenMultiplyAmount(t *testing.T) {
+	assert.Equal(t, models.Money{Amount: 30, Currency: "USD"}, models.Money{Amount: 10, Currency: "USD"}.Multiply(3))
+}
+
+func Test_GivenDifferentCurrencies_ThenFormatWithTheirMinorUnits(t *testing.T) {
+	assert.Equal(t, "12.05 USD", models.Money{Amount: 1205, Currency: "USD"}.String())
+	assert.Equal(t, "-0.99 EUR", models.Money{Amount: -99, Currency: "EUR"}.String())
+	assert.Equal(t, "1205 JPY", models.Money{Amount: 1205, Currency: "JPY"}.String())
+	assert.Equal(t, "1.205 KWD", models.Money{Amount: 1205, Currency: "KWD"}.String())
+	assert.Equal(t, "0.00", models.Money{}.String())
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_GivenAPendingOrder_ThenItCanBePaidOrCancelled(t *testing.T) {
+	assert.True(t, models.OrderStatusPending.CanTransitionTo(models.OrderStatusPaid))
+	assert.True(t, models.OrderStatusPending.CanTransitionTo(models.OrderStatusCancelled))
+	assert.False(t, models.OrderStatusPending.CanTransitionTo(models.OrderStatusShipped))
+}
+
+func Test_GivenAShippedOrder_ThenItCannotBeCancelled(t *testing.T) {
+	assert.False(t, models.OrderStatusShipped.CanTransitionTo(models.OrderStatusCancelled))
+	assert.True(t, models.OrderStatusShipped.CanTransitionTo(models.OrderStatusDelivered))
+}
+
+func Test_GivenARefundedOrder_ThenItIsFinal(t *testing.T) {
+	for _, status := range []models.OrderStatus{models.OrderStatusPending, models.OrderStatusPaid, models.OrderStatusFulfilled,
+		models.OrderStatusShipped, models.OrderStatusDelivered, models.OrderStatusCancelled, models.OrderStatusRefunded} {
+		assert.False(t, models.OrderStatusRefunded.CanTransitionTo(status))
+	}
+}
+
+func Test_GivenAnUnknownStatus_ThenItIsInvalid(t *testing.T) {
+	assert.False(t, models.OrderStatus("lost").IsValid())
+	assert.True(t, models.OrderStatusDelivered.IsValid())
+}
+
+func Test_GivenAPaidStatus_ThenPaymentWasCollected(t *testing.T) {
+	assert.True(t, models.OrderStatusPaid.HasCollectedPayment())
+	assert.True(t, models.OrderStatusFulfilled.HasCollectedPayment())
+	assert.False(t, models.OrderStatusPending.HasCollectedPayment())
+}
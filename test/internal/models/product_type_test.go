@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_GivenAProductType_ThenMarshalAsName(t *testing.T) {
+	data, err := json.Marshal(models.ProductTypeBundle)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `"bundle"`, string(data))
+}
+
+func Test_GivenAnUnknownProductType_ThenUnableToMarshal(t *testing.T) {
+	_, err := json.Marshal(models.ProductType(9))
+
+	assert.NotNil(t, err)
+}
+
+func Test_GivenAProductTypeName_ThenUnmarshalProductType(t *testing.T) {
+	var productType models.ProductType
+
+	err := json.Unmarshal([]byte(`"service"`), &productType)
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.ProductTypeService, productType)
+}
+
+func Test_GivenAnUnknownProductTypeName_ThenUnableToUnmarshal(t *testing.T) {
+	var productType models.ProductType
+
+	err := json.Unmarshal([]byte(`"gadget"`), &productType)
+
+	assert.EqualError(t, err, "unsupported product type: gadget")
+}
+
+func Test_GivenANumericProductType_ThenUnableToUnmarshal(t *testing.T) {
+	var productType models.ProductType
+
+	err := json.Unmarshal([]byte(`1`), &productType)
+
+	assert.EqualError(t, err, "product type must be a string")
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_GivenACustomer_ThenItCanOnlyShop(t *testing.T) {
+	assert.True(t, models.RoleCustomer.Grants(models.ScopeShopping))
+	assert.False(t, models.RoleCustomer.Grants(models.ScopeCartsRead))
+	assert.False(t, models.RoleCustomer.Grants(models.ScopeOrdersRead))
+}
+
+func Test_GivenSupport_ThenItCanReadCartsAndOrdersOnly(t *testing.T) {
+	assert.True(t, models.RoleSupport.Grants(models.ScopeCartsRead))
+	assert.True(t, models.RoleSupport.Grants(models.ScopeOrdersRead))
+	assert.False(t, models.RoleSupport.Grants(models.ScopeOrdersWrite))
+	assert.False(t, models.RoleSupport.Grants(models.ScopeCatalogWrite))
+}
+
+func Test_GivenACatalogAdmin_ThenItCanManageTheCatalogOnly(t *testing.T) {
+	assert.True(t, models.RoleCatalogAdmin.Grants(models.ScopeCatalogWrite))
+	assert.True(t, models.RoleCatalogAdmin.Grants(models.ScopeInventoryWrite))
+	assert.False(t, models.RoleCatalogAdmin.Grants(models.ScopeCartsRead))
+	assert.False(t, models.RoleCatalogAdmin.Grants(models.ScopeRolesManage))
+}
+
+func Test_GivenASuperAdmin_ThenItHasEveryScope(t *testing.T) {
+	assert.True(t, models.RoleSuperAdmin.Grants(models.ScopeRolesManage))
+	assert.True(t, models.RoleSuperAdmin.Grants(models.ScopeKeysManage))
+}
+
+func Test_GivenAnUnknownRole_ThenItIsInvalidAndGrantsNothing(t *testing.T) {
+	assert.False(t, models.Role("owner").IsValid())
+	assert.False(t, models.Role("owner").Grants(models.ScopeShopping))
+}
@@ -0,0 +1,80 @@
+package payment
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/payment"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var anAmount = models.Money{Amount: 4550, Currency: "USD"}
+
+func Test_GivenAnApprovingProvider_ThenAuthorizeAndCapture(t *testing.T) {
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorApprove)
+
+	authorization, err := provider.Authorize(payment.AuthorizationRequest{OrderId: 1, Amount: anAmount})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "fake_auth_000001", authorization.Id)
+	assert.Equal(t, payment.AuthorizationStatusAuthorized, authorization.Status)
+	assert.Nil(t, provider.Capture(authorization.Id))
+}
+
+func Test_GivenADecliningProvider_ThenUnableToAuthorize(t *testing.T) {
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorDecline)
+
+	_, err := provider.Authorize(payment.AuthorizationRequest{OrderId: 1, Amount: anAmount})
+
+	assert.True(t, errors.Is(err, payment.ErrPaymentDeclined))
+}
+
+func Test_GivenATimingOutProvider_ThenUnableToAuthorize(t *testing.T) {
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorTimeout)
+
+	_, err := provider.Authorize(payment.AuthorizationRequest{OrderId: 1, Amount: anAmount})
+
+	assert.True(t, errors.Is(err, payment.ErrPaymentTimeout))
+}
+
+func Test_GivenAnAuthorizationRequiringConfirmation_ThenCaptureOnlyAfterConfirm(t *testing.T) {
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorRequireConfirmation)
+
+	authorization, err := provider.Authorize(payment.AuthorizationRequest{OrderId: 1, Amount: anAmount})
+
+	assert.Nil(t, err)
+	assert.Equal(t, payment.AuthorizationStatusRequiresConfirmation, authorization.Status)
+	assert.True(t, errors.Is(provider.Capture(authorization.Id), payment.ErrInvalidAuthorizationOp))
+
+	confirmed, err := provider.Confirm(authorization.Id)
+
+	assert.Nil(t, err)
+	assert.Equal(t, payment.AuthorizationStatusAuthorized, confirmed.Status)
+	assert.Nil(t, provider.Capture(authorization.Id))
+}
+
+func Test_GivenACapturedPayment_ThenRefundUpToTheCapturedAmount(t *testing.T) {
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorApprove)
+
+	authorization, _ := provider.Authorize(payment.AuthorizationRequest{OrderId: 1, Amount: anAmount})
+	_ = provider.Capture(authorization.Id)
+
+	assert.Nil(t, provider.Refund(authorization.Id, models.Money{Amount: 4000, Currency: "USD"}))
+	assert.True(t, errors.Is(provider.Refund(authorization.Id, models.Money{Amount: 1000, Currency: "USD"}), payment.ErrInvalidAuthorizationOp))
+}
+
+func Test_GivenAVoidedAuthorization_ThenUnableToCapture(t *testing.T) {
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorApprove)
+
+	authorization, _ := provider.Authorize(payment.AuthorizationRequest{OrderId: 1, Amount: anAmount})
+
+	assert.Nil(t, provider.Void(authorization.Id))
+	assert.True(t, errors.Is(provider.Capture(authorization.Id), payment.ErrInvalidAuthorizationOp))
+	assert.True(t, errors.Is(provider.Capture("fake_auth_999999"), payment.ErrUnknownAuthorization))
+}
+
+func Test_GivenAnUnknownBehavior_ThenUnableToCreateProvider(t *testing.T) {
+	_, err := payment.NewFakeProvider("flaky")
+
+	assert.EqualError(t, err, "unsupported fake payment behavior: flaky")
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func Test_GivenAClientWithoutDefaultShippingAddress_ThenFindDefaultReturnsNil(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("Find", mock.Anything, []interface{}{"client_id = ? AND default_shipping = ?", aValidClientId, true}).
+		Return(getMockedDbObjectWithRows(nil, 0))
+
+	repo := repository.PgAddressRepository{DbClient: dbClientMock}
+
+	address, err := repo.FindDefaultShippingAddress(aValidClientId)
+
+	assert.Nil(t, err)
+	assert.Nil(t, address)
+}
+
+func Test_GivenAnAddressOfAnotherClient_ThenUnableToFindAddress(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("First", mock.Anything, []interface{}{"id = ? AND client_id = ?", aValidAddressId, aValidClientId}).
+		Return(getMockedDbObject(nil, errors.New("record not found")))
+
+	repo := repository.PgAddressRepository{DbClient: dbClientMock}
+
+	address, err := repo.FindAddress(aValidClientId, aValidAddressId)
+
+	assert.Nil(t, address)
+	assert.EqualError(t, err, "address with id: 3 not found")
+}
@@ -68,9 +68,13 @@ func Test_AddProductToACart_Successful(t *testing.T) {
 		arg.Client = getMockedClientCart().Client
 	})
 
+	existingProductCart := models.ProductCart{}
+	dbClientMock.On("Find", &existingProductCart, getMockedProductCartQuery()).Return(getMockedDbObjectWithRows(nil, 0))
+
 	productCart := models.ProductCart{
 		ProductId: aValidProductId,
 		CartId:    aValidCartId,
+		Quantity:  1,
 	}
 	dbClientMock.On("Create", &productCart).Return(getMockedDbObject(getMockedProductCart(), nil)).Run(func(args mock.Arguments) {
 		arg := args.Get(0).(*models.ProductCart)
@@ -123,9 +127,13 @@ func Test_GivenValidClientId_ValidProductById_ThenUnableToCreateProductCartInDb(
 		arg.Client = getMockedClientCart().Client
 	})
 
+	existingProductCart := models.ProductCart{}
+	dbClientMock.On("Find", &existingProductCart, getMockedProductCartQuery()).Return(getMockedDbObjectWithRows(nil, 0))
+
 	productCart := models.ProductCart{
 		ProductId: aValidProductId,
 		CartId:    aValidCartId,
+		Quantity:  1,
 	}
 	dbClientMock.On("Create", &productCart).Return(getMockedDbObject(nil, errors.New("unable to create association")))
 
@@ -137,3 +145,282 @@ func Test_GivenValidClientId_ValidProductById_ThenUnableToCreateProductCartInDb(
 	dbClientMock.AssertNumberOfCalls(t, "FirstOrCreate", 1)
 	dbClientMock.AssertNumberOfCalls(t, "Create", 1)
 }
+
+func Test_AddProductAlreadyInTheCart_ThenIncrementQuantity(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("FirstOrCreate", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Cart)
+		*arg = getMockedClientCart()
+	})
+
+	existingProductCart := models.ProductCart{}
+	dbClientMock.On("Find", &existingProductCart, getMockedProductCartQuery()).Return(getMockedDbObjectWithRows(nil, 1)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.ProductCart)
+		*arg = getMockedProductCart()
+		arg.Id = aValidProductCartId
+	})
+	dbClientMock.On("Exec", getMockedIncrementQuery(), []interface{}{aValidProductCartId, models.MaxProductQuantity}).Return(getMockedDbObjectWithRows(nil, 1))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	err := repo.AddProductToCart(aValidProductId, aValidClientId)
+
+	assert.Nil(t, err)
+	dbClientMock.AssertNumberOfCalls(t, "Exec", 1)
+	dbClientMock.AssertNumberOfCalls(t, "Create", 0)
+}
+
+func Test_AddProductAtTheQuantityLimit_ThenUnableToIncrement(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("FirstOrCreate", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Cart)
+		*arg = getMockedClientCart()
+	})
+
+	existingProductCart := models.ProductCart{}
+	dbClientMock.On("Find", &existingProductCart, getMockedProductCartQuery()).Return(getMockedDbObjectWithRows(nil, 1)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.ProductCart)
+		*arg = getMockedProductCart()
+		arg.Id = aValidProductCartId
+	})
+	dbClientMock.On("Exec", getMockedIncrementQuery(), []interface{}{aValidProductCartId, models.MaxProductQuantity}).Return(getMockedDbObjectWithRows(nil, 0))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	err := repo.AddProductToCart(aValidProductId, aValidClientId)
+
+	assert.True(t, errors.Is(err, models.ErrCartQuantityLimit))
+}
+
+func Test_AddProductConcurrentlyAddedToTheCart_ThenIncrementTheOtherLine(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("FirstOrCreate", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Cart)
+		*arg = getMockedClientCart()
+	})
+
+	existingProductCart := models.ProductCart{}
+	dbClientMock.On("Find", &existingProductCart, getMockedProductCartQuery()).Return(getMockedDbObjectWithRows(nil, 0)).Once()
+	dbClientMock.On("Find", &existingProductCart, getMockedProductCartQuery()).Return(getMockedDbObjectWithRows(nil, 1)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.ProductCart)
+		*arg = getMockedProductCart()
+		arg.Id = aValidProductCartId
+	})
+	productCart := models.ProductCart{ProductId: aValidProductId, CartId: aValidCartId, Quantity: 1}
+	dbClientMock.On("Create", &productCart).Return(getMockedDbObject(nil, errors.New("duplicate key value violates unique constraint")))
+	dbClientMock.On("Exec", getMockedIncrementQuery(), []interface{}{aValidProductCartId, models.MaxProductQuantity}).Return(getMockedDbObjectWithRows(nil, 1))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	err := repo.AddProductToCart(aValidProductId, aValidClientId)
+
+	assert.Nil(t, err)
+	dbClientMock.AssertNumberOfCalls(t, "Exec", 1)
+}
+
+func Test_SetQuantityOfAProductInTheCart_Successful(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("FirstOrCreate", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Cart)
+		*arg = getMockedClientCart()
+	})
+
+	existingProductCart := models.ProductCart{}
+	dbClientMock.On("Find", &existingProductCart, getMockedProductCartQuery()).Return(getMockedDbObjectWithRows(nil, 1)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.ProductCart)
+		arg.Id = aValidProductCartId
+		arg.CartId = aValidCartId
+		arg.ProductId = aValidProductId
+		arg.Quantity = 1
+	})
+
+	updatedProductCart := models.ProductCart{Id: aValidProductCartId, CartId: aValidCartId, ProductId: aValidProductId, Quantity: 4}
+	dbClientMock.On("Save", &updatedProductCart).Return(getMockedDbObject(nil, nil))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	err := repo.SetProductQuantity(aValidProductId, aValidClientId, 4)
+
+	assert.Nil(t, err)
+	dbClientMock.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func Test_SetQuantityOfAProductInTheCart_ThenUnableToSave(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("FirstOrCreate", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Cart)
+		*arg = getMockedClientCart()
+	})
+
+	existingProductCart := models.ProductCart{}
+	dbClientMock.On("Find", &existingProductCart, getMockedProductCartQuery()).Return(getMockedDbObjectWithRows(nil, 0))
+
+	newProductCart := models.ProductCart{CartId: aValidCartId, ProductId: aValidProductId, Quantity: 2}
+	dbClientMock.On("Save", &newProductCart).Return(getMockedDbObject(nil, errors.New("unable to save")))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	err := repo.SetProductQuantity(aValidProductId, aValidClientId, 2)
+
+	assert.EqualError(t, err, "unable to update the product quantity in the cart")
+}
+
+func Test_GivenAValidCartId_ThenReturnCartItemsWithProducts(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	var productsCarts []models.ProductCart
+	dbClientMock.On("Find", &productsCarts, getMockedQuery("cart_id = ?", aValidCartId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*[]models.ProductCart)
+		*arg = getMockedProductCartsList()
+	})
+
+	product := models.Product{}
+	dbClientMock.On("Find", &product, getMockedQuery("id = ?", aValidProductId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Product)
+		*arg = getMockedProduct()
+	})
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	resp, err := repo.GetCartItems(aValidCartId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(*resp))
+	assert.Equal(t, getMockedProduct(), (*resp)[0].Product)
+	assert.Equal(t, 1, (*resp)[0].Quantity)
+	dbClientMock.AssertNumberOfCalls(t, "Find", 2)
+}
+
+func Test_GivenAValidCartId_ThenNotFoundCartItems(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	var productsCarts []models.ProductCart
+	dbClientMock.On("Find", &productsCarts, getMockedQuery("cart_id = ?", aValidCartId)).Return(getMockedDbObject(nil, errors.New("unable to find list of products")))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	resp, err := repo.GetCartItems(aValidCartId)
+
+	assert.EqualError(t, err, "unable to retrieve the list of products")
+	assert.Nil(t, resp)
+	dbClientMock.AssertNumberOfCalls(t, "Find", 1)
+}
+
+func Test_GivenAValidCartId_AndItemsFound_NotFoundProductsById_ThenReturnEmptyList(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	var productsCarts []models.ProductCart
+	dbClientMock.On("Find", &productsCarts, getMockedQuery("cart_id = ?", aValidCartId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*[]models.ProductCart)
+		*arg = getMockedProductCartsList()
+	})
+
+	product := models.Product{}
+	dbClientMock.On("Find", &product, getMockedQuery("id = ?", aValidProductId)).Return(getMockedDbObject(nil, errors.New("unable to find by id")))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	resp, err := repo.GetCartItems(aValidCartId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(*resp))
+	dbClientMock.AssertNumberOfCalls(t, "Find", 2)
+}
+
+func Test_RemoveProductFromTheCart_Successful(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("First", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Cart)
+		*arg = getMockedClientCart()
+	})
+	dbClientMock.On("Delete", &models.ProductCart{}, getMockedProductCartQuery()).Return(getMockedDbObjectWithRows(nil, 1))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	err := repo.RemoveProductFromCart(aValidProductId, aValidClientId)
+
+	assert.Nil(t, err)
+	dbClientMock.AssertNumberOfCalls(t, "Delete", 1)
+}
+
+func Test_RemoveProductNotInTheCart_ThenReturnError(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("First", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Cart)
+		*arg = getMockedClientCart()
+	})
+	dbClientMock.On("Delete", &models.ProductCart{}, getMockedProductCartQuery()).Return(getMockedDbObjectWithRows(nil, 0))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	err := repo.RemoveProductFromCart(aValidProductId, aValidClientId)
+
+	assert.EqualError(t, err, fmt.Sprintf("product with id: %v is not in the cart", aValidProductId))
+}
+
+func Test_RemoveProductFromTheCart_CartNotFound(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("First", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, errors.New("record not found")))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	err := repo.RemoveProductFromCart(aValidProductId, aValidClientId)
+
+	assert.EqualError(t, err, fmt.Sprintf("cart for client id: %v not found", aValidClientId))
+	dbClientMock.AssertNumberOfCalls(t, "Delete", 0)
+}
+
+func Test_ClearTheCart_Successful(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("First", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Cart)
+		*arg = getMockedClientCart()
+	})
+	dbClientMock.On("Delete", &models.ProductCart{}, getMockedQuery("cart_id = ?", aValidCartId)).Return(getMockedDbObjectWithRows(nil, 3))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	err := repo.ClearCart(aValidClientId)
+
+	assert.Nil(t, err)
+	dbClientMock.AssertNumberOfCalls(t, "Delete", 1)
+}
+
+func Test_ClearTheCart_ThenUnableToDelete(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("First", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Cart)
+		*arg = getMockedClientCart()
+	})
+	dbClientMock.On("Delete", &models.ProductCart{}, getMockedQuery("cart_id = ?", aValidCartId)).Return(getMockedDbObject(nil, errors.New("connection lost")))
+
+	repo := repository.PgCartRepository{DbClient: dbClientMock}
+
+	err := repo.ClearCart(aValidClientId)
+
+	assert.EqualError(t, err, "unable to clear the cart")
+}
+
+func getMockedIncrementQuery() string {
+	return "UPDATE product_carts SET quantity = quantity + 1 WHERE id = ? AND quantity < ?"
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func Test_GivenAValidCategory_ThenCreateCategory(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	category := models.Category{Label: "Peripherals"}
+	dbClientMock.On("Create", &category).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Category)
+		arg.Id = aValidCategoryId
+	})
+
+	repo := repository.PgCategoryRepository{DbClient: dbClientMock}
+
+	err := repo.CreateCategory(&category)
+
+	assert.Nil(t, err)
+	assert.Equal(t, aValidCategoryId, category.Id)
+}
+
+func Test_GivenAValidCategory_ThenUnableToCreateCategory(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	category := models.Category{Label: "Peripherals"}
+	dbClientMock.On("Create", &category).Return(getMockedDbObject(nil, errors.New("duplicated key")))
+
+	repo := repository.PgCategoryRepository{DbClient: dbClientMock}
+
+	err := repo.CreateCategory(&category)
+
+	assert.EqualError(t, err, "unable to create the category")
+}
+
+func Test_GivenAValidCategoryId_ThenReturnCategory(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	category := models.Category{}
+	dbClientMock.On("First", &category, getMockedQuery("id = ?", aValidCategoryId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Category)
+		*arg = getMockedCategory()
+	})
+
+	repo := repository.PgCategoryRepository{DbClient: dbClientMock}
+
+	resp, err := repo.FindCategoryById(aValidCategoryId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, getMockedCategory(), *resp)
+}
+
+func Test_GivenAnUnknownCategoryId_ThenCategoryNotFound(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	category := models.Category{}
+	dbClientMock.On("First", &category, getMockedQuery("id = ?", aValidCategoryId)).Return(getMockedDbObject(nil, errors.New("record not found")))
+
+	repo := repository.PgCategoryRepository{DbClient: dbClientMock}
+
+	resp, err := repo.FindCategoryById(aValidCategoryId)
+
+	assert.Nil(t, resp)
+	assert.EqualError(t, err, fmt.Sprintf("category with id: %v not found", aValidCategoryId))
+}
+
+func Test_ListCategories_Successful(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	var categories []models.Category
+	dbClientMock.On("Find", &categories, []interface{}(nil)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*[]models.Category)
+		*arg = []models.Category{getMockedCategory()}
+	})
+
+	repo := repository.PgCategoryRepository{DbClient: dbClientMock}
+
+	resp, err := repo.ListCategories()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []models.Category{getMockedCategory()}, *resp)
+}
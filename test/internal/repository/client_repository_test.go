@@ -15,7 +15,7 @@ func Test_GivenAValidClientId_ThenReturnTrue(t *testing.T) {
 	findClientByIdQuery := getMockedQuery("id = ?", aValidClientId)
 
 	client := models.Client{}
-	dbClientMock.On("Find", &client, findClientByIdQuery).Return(getMockedDbObject(getMockedClient(), nil)).Run(func(args mock.Arguments) {
+	dbClientMock.On("Find", &client, findClientByIdQuery).Return(getMockedDbObjectWithRows(getMockedClient(), 1)).Run(func(args mock.Arguments) {
 		arg := args.Get(0).(*models.Client)
 		arg.Name = getMockedClient().Name
 		arg.Id = getMockedClient().Id
@@ -42,3 +42,61 @@ func Test_GivenANotValidClientId_ThenReturnFalse(t *testing.T) {
 
 	assert.False(t, resp)
 }
+
+func Test_GivenADeletedClient_ThenReturnFalse(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	client := models.Client{}
+	dbClientMock.On("Find", &client, getMockedQuery("id = ?", aValidClientId)).Return(getMockedDbObjectWithRows(nil, 0))
+
+	repo := repository.PgClientRepository{DbClient: dbClientMock}
+
+	resp := repo.IsClientInDataBase(aValidClientId)
+
+	assert.False(t, resp)
+}
+
+func Test_GivenAnUnusedEmail_ThenFindClientByEmailReturnsNil(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("Find", mock.Anything, []interface{}{"email = ?", "ada@example.com"}).Return(getMockedDbObjectWithRows(nil, 0))
+
+	repo := repository.PgClientRepository{DbClient: dbClientMock}
+
+	client, err := repo.FindClientByEmail("ada@example.com")
+
+	assert.Nil(t, err)
+	assert.Nil(t, client)
+}
+
+func Test_GivenARacedSignUp_ThenCreateClientReturnsEmailTaken(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	client := models.Client{Email: "ada@example.com"}
+	dbClientMock.On("Create", &client).Return(getMockedDbObject(nil, errors.New("duplicate key value violates unique constraint \"idx_clients_email\"")))
+	dbClientMock.On("Find", mock.Anything, []interface{}{"email = ?", "ada@example.com"}).Return(getMockedDbObjectWithRows(nil, 1)).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Client).Id = aValidClientId
+	})
+
+	repo := repository.PgClientRepository{DbClient: dbClientMock}
+
+	err := repo.CreateClient(&client)
+
+	assert.True(t, errors.Is(err, models.ErrEmailTaken))
+}
+
+func Test_GivenAFailedUpdate_ThenUpdateClientReturnsError(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	client := models.Client{Id: aValidClientId, Email: "ada@example.com"}
+	dbClientMock.On("Save", &client).Return(getMockedDbObject(nil, errors.New("connection refused")))
+	dbClientMock.On("Find", mock.Anything, []interface{}{"email = ?", "ada@example.com"}).Return(getMockedDbObjectWithRows(nil, 1)).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Client).Id = aValidClientId
+	})
+
+	repo := repository.PgClientRepository{DbClient: dbClientMock}
+
+	err := repo.UpdateClient(&client)
+
+	assert.EqualError(t, err, "unable to update the client")
+}
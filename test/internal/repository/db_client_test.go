@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/config"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func Test_GivenAnExistingSqliteFile_ThenMigrateItAgainKeepingItsRows(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.Driver = config.DriverSqlite
+	cfg.Path = filepath.Join(t.TempDir(), "store.db")
+
+	repos, err := repository.Open(cfg, true)
+	assert.Nil(t, err)
+	givenAClient(t, repos, "ada@example.com")
+
+	db, err := repository.Connect(cfg, true)
+
+	assert.Nil(t, err)
+	found, _ := repository.NewGormRepositories(db).Client.FindClientByEmail("ada@example.com")
+	assert.NotNil(t, found)
+}
+
+func Test_GivenAnUnknownDriver_ThenUnableToConnect(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.Driver = config.DriverMemory
+
+	_, err := repository.Connect(cfg, true)
+
+	assert.EqualError(t, err, "unsupported database driver: memory")
+}
+
+func Test_GivenLegacyDuplicatedCartLines_ThenMigrationMergesThem(t *testing.T) {
+	cfg := config.Default().Database
+	cfg.Driver = config.DriverSqlite
+	cfg.Path = filepath.Join(t.TempDir(), "store.db")
+
+	db, err := repository.Connect(cfg, true)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Migrator().DropIndex(&models.ProductCart{}, "idx_product_carts_cart_product"))
+	assert.Nil(t, db.Exec("PRAGMA foreign_keys = OFF").Error)
+	assert.Nil(t, db.Exec("INSERT INTO product_carts (product_id, cart_id, quantity) VALUES (1, 1, 1), (1, 1, 60), (1, 1, 60), (2, 1, 1), (1, 2, 1)").Error)
+	sqlDB, _ := db.DB()
+	_ = sqlDB.Close()
+
+	db, err = repository.Connect(cfg, true)
+
+	if !assert.Nil(t, err) {
+		return
+	}
+	var lines []models.ProductCart
+	db.Order("id").Find(&lines)
+	assert.Equal(t, []models.ProductCart{
+		{Id: 1, ProductId: 1, CartId: 1, Quantity: models.MaxProductQuantity},
+		{Id: 4, ProductId: 2, CartId: 1, Quantity: 1},
+		{Id: 5, ProductId: 1, CartId: 2, Quantity: 1},
+	}, lines)
+	assert.True(t, db.Migrator().HasIndex(&models.ProductCart{}, "idx_product_carts_cart_product"))
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func Test_GivenAnOrderedProduct_ThenFindOrderItem(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("First", mock.Anything, []interface{}{"order_id = ? AND product_id = ?", aValidOrderId, aValidProductId}).
+		Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.OrderItem)
+		arg.OrderId = aValidOrderId
+		arg.ProductId = aValidProductId
+		arg.Downloads = 2
+	})
+
+	repo := repository.PgDownloadRepository{DbClient: dbClientMock}
+
+	item, err := repo.FindOrderItem(aValidOrderId, aValidProductId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, item.Downloads)
+}
+
+func Test_GivenAProductNotInTheOrder_ThenUnableToFindOrderItem(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("First", mock.Anything, []interface{}{"order_id = ? AND product_id = ?", aValidOrderId, aValidProductId}).
+		Return(getMockedDbObject(nil, errors.New("record not found")))
+
+	repo := repository.PgDownloadRepository{DbClient: dbClientMock}
+
+	item, err := repo.FindOrderItem(aValidOrderId, aValidProductId)
+
+	assert.Nil(t, item)
+	assert.EqualError(t, err, fmt.Sprintf("the product: %v is not part of the order: %v", aValidProductId, aValidOrderId))
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/config"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func getMemoryRepositories() repository.Repositories {
+	return repository.NewMemoryRepositories(repository.NewMemoryStore())
+}
+
+func Test_GivenTheMemoryDriver_ThenOpenMemoryRepositories(t *testing.T) {
+	db := config.Default().Database
+	db.Driver = config.DriverMemory
+
+	repos, err := repository.Open(db, true)
+
+	assert.Nil(t, err)
+	assert.IsType(t, &repository.MemoryClientRepository{}, repos.Client)
+}
+
+func Test_GivenAnUnknownDriver_ThenUnableToOpen(t *testing.T) {
+	db := config.Default().Database
+	db.Driver = "oracle"
+
+	_, err := repository.Open(db, true)
+
+	assert.EqualError(t, err, "unsupported database driver: oracle")
+}
+
+func Test_Memory_GivenAReturnedRow_ThenChangingItDoesNotChangeTheStore(t *testing.T) {
+	repos := getMemoryRepositories()
+	product := givenAStockedProduct(t, repos, "Keyboard", 5)
+
+	found, _ := repos.Product.FindProductById(product.Id)
+	found.Label = "Changed"
+
+	again, _ := repos.Product.FindProductById(product.Id)
+	assert.Equal(t, "Keyboard", again.Label)
+}
+
+func Test_Memory_GivenConcurrentAdds_ThenEveryUnitIsCounted(t *testing.T) {
+	repos := getMemoryRepositories()
+	client := givenAClient(t, repos, "ada@example.com")
+	product := givenAStockedProduct(t, repos, "Keyboard", 5)
+
+	var wait sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			_ = repos.Cart.AddProductToCart(product.Id, client.Id)
+		}()
+	}
+	wait.Wait()
+
+	cart, _ := repos.Cart.GetCartByClient(client.Id)
+	items, _ := repos.Cart.GetCartItems(cart.Id)
+	assert.Len(t, *items, 1)
+	assert.Equal(t, 50, (*items)[0].Quantity)
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func Test_GivenAValidOrderId_ThenReturnOrderWithItems(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	client := models.Client{}
+	dbClientMock.On("Find", &client, getMockedQuery("id = ?", aValidClientId)).Return(getMockedDbObjectWithRows(getMockedClient(), 1))
+
+	order := models.Order{}
+	firstOrderQuery := []interface{}{"id = ? AND client_id = ?", aValidOrderId, aValidClientId}
+	dbClientMock.On("First", &order, firstOrderQuery).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Order)
+		arg.Id = aValidOrderId
+		arg.ClientId = aValidClientId
+	})
+
+	var orderItems []models.OrderItem
+	dbClientMock.On("Find", &orderItems, getMockedQuery("order_id = ?", aValidOrderId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*[]models.OrderItem)
+		*arg = []models.OrderItem{{OrderId: aValidOrderId, ProductId: aValidProductId}}
+	})
+
+	product := models.Product{}
+	dbClientMock.On("Find", &product, getMockedQuery("id = ?", aValidProductId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Product)
+		*arg = getMockedProduct()
+	})
+
+	repo := repository.PgOrderRepository{DbClient: dbClientMock}
+
+	resp, err := repo.GetOrderWithItems(aValidClientId, aValidOrderId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, aValidOrderId, resp.Id)
+	assert.Equal(t, 1, len(resp.Items))
+	assert.Equal(t, getMockedProduct(), resp.Items[0].Product)
+	dbClientMock.AssertNumberOfCalls(t, "First", 1)
+}
+
+func Test_GivenAnOrderIdFromAnotherClient_ThenOrderNotFound(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	client := models.Client{}
+	dbClientMock.On("Find", &client, getMockedQuery("id = ?", aValidClientId)).Return(getMockedDbObjectWithRows(getMockedClient(), 1))
+
+	order := models.Order{}
+	firstOrderQuery := []interface{}{"id = ? AND client_id = ?", aValidOrderId, aValidClientId}
+	dbClientMock.On("First", &order, firstOrderQuery).Return(getMockedDbObject(nil, errors.New("record not found")))
+
+	repo := repository.PgOrderRepository{DbClient: dbClientMock}
+
+	resp, err := repo.GetOrderWithItems(aValidClientId, aValidOrderId)
+
+	assert.Nil(t, resp)
+	assert.EqualError(t, err, fmt.Sprintf("order: %v for clientId: %v not found", aValidOrderId, aValidClientId))
+	dbClientMock.AssertNumberOfCalls(t, "Find", 1)
+}
+
+func Test_GivenANotValidClientId_ThenUnableToListOrders(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	client := models.Client{}
+	dbClientMock.On("Find", &client, getMockedQuery("id = ?", aNotValidClientId)).Return(getMockedDbObject(nil, errors.New("client not found in db")))
+
+	repo := repository.PgOrderRepository{DbClient: dbClientMock}
+
+	resp, total, err := repo.ListOrdersForClient(aNotValidClientId, 1, 10)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, int64(0), total)
+	assert.EqualError(t, err, fmt.Sprintf("client with id: %v not found", aNotValidClientId))
+	dbClientMock.AssertNotCalled(t, "Model", mock.Anything)
+}
+
+func Test_GivenAValidClientId_AndNoCart_ThenUnableToCreateOrder(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	client := models.Client{}
+	dbClientMock.On("Find", &client, getMockedQuery("id = ?", aValidClientId)).Return(getMockedDbObjectWithRows(getMockedClient(), 1))
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("First", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, errors.New("record not found")))
+
+	repo := repository.PgOrderRepository{DbClient: dbClientMock}
+
+	order, err := repo.CreateOrder(aValidClientId, models.OrderShipping{})
+
+	assert.Nil(t, order)
+	assert.EqualError(t, err, fmt.Sprintf("cart for client id: %v not found", aValidClientId))
+	dbClientMock.AssertNotCalled(t, "Transaction", mock.Anything, mock.Anything)
+}
+
+func Test_GivenAValidCart_ThenConversionErrorIsReturned(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	client := models.Client{}
+	dbClientMock.On("Find", &client, getMockedQuery("id = ?", aValidClientId)).Return(getMockedDbObjectWithRows(getMockedClient(), 1))
+
+	clientCart := models.Cart{ClientId: aValidClientId}
+	dbClientMock.On("First", &clientCart, getMockedQuery("client_id = ?", aValidClientId)).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Cart)
+		*arg = getMockedClientCart()
+	})
+	dbClientMock.On("Transaction", mock.Anything, mock.Anything).Return(errors.New("the cart has no products"))
+
+	repo := repository.PgOrderRepository{DbClient: dbClientMock}
+
+	order, err := repo.CreateOrder(aValidClientId, models.OrderShipping{})
+
+	assert.Nil(t, order)
+	assert.EqualError(t, err, "the cart has no products")
+	dbClientMock.AssertNumberOfCalls(t, "Transaction", 1)
+}
+
+func Test_GivenAnUnknownOrderId_ThenOrderNotFound(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	order := models.Order{}
+	dbClientMock.On("First", &order, getMockedQuery("id = ?", aValidOrderId)).Return(getMockedDbObject(nil, errors.New("record not found")))
+
+	repo := repository.PgOrderRepository{DbClient: dbClientMock}
+
+	_, err := repo.FindOrderById(aValidOrderId)
+
+	assert.EqualError(t, err, fmt.Sprintf("order: %v not found", aValidOrderId))
+}
+
+func Test_GivenAConcurrentTransition_ThenUnableToTransitionOrder(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("Transaction", mock.Anything, mock.Anything).
+		Return(models.NewInvalidOrderTransitionError(models.OrderStatusCancelled, models.OrderStatusPaid))
+
+	repo := repository.PgOrderRepository{DbClient: dbClientMock}
+
+	transition, err := repo.TransitionOrder(aValidOrderId, models.OrderStatusPending, models.OrderStatusPaid, "payments")
+
+	assert.Nil(t, transition)
+	assert.True(t, errors.Is(err, models.ErrInvalidOrderTransition))
+}
+
+func Test_GivenAnOrderAlreadyPaid_ThenItIsNotReleasedAsAbandoned(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	var orders []models.Order
+	dbClientMock.On("Find", &orders, mock.Anything).Return(getMockedDbObject(nil, nil)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*[]models.Order)
+		*arg = []models.Order{{Id: aValidOrderId, Status: models.OrderStatusPending}}
+	})
+	dbClientMock.On("Transaction", mock.Anything, mock.Anything).
+		Return(models.NewInvalidOrderTransitionError(models.OrderStatusPaid, models.OrderStatusCancelled))
+
+	repo := repository.PgOrderRepository{DbClient: dbClientMock}
+
+	released, err := repo.ReleaseAbandonedOrders(time.Now())
+
+	assert.Nil(t, err)
+	assert.Empty(t, released)
+}
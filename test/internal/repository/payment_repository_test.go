@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func Test_GivenAChargedOrder_ThenReturnPayment(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("Find", mock.Anything, []interface{}{"order_id = ?", aValidOrderId}).
+		Return(getMockedDbObjectWithRows(nil, 1)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Payment)
+		arg.OrderId = aValidOrderId
+		arg.Status = models.PaymentStatusCaptured
+	})
+
+	repo := repository.PgPaymentRepository{DbClient: dbClientMock}
+
+	payment, err := repo.FindPaymentByOrderId(aValidOrderId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.PaymentStatusCaptured, payment.Status)
+}
+
+func Test_GivenANeverChargedOrder_ThenReturnNoPayment(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("Find", mock.Anything, []interface{}{"order_id = ?", aValidOrderId}).Return(getMockedDbObjectWithRows(nil, 0))
+
+	repo := repository.PgPaymentRepository{DbClient: dbClientMock}
+
+	payment, err := repo.FindPaymentByOrderId(aValidOrderId)
+
+	assert.Nil(t, err)
+	assert.Nil(t, payment)
+}
+
+func Test_GivenAPayment_ThenUnableToSave(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("Save", mock.Anything).Return(getMockedDbObject(nil, errors.New("connection refused")))
+
+	repo := repository.PgPaymentRepository{DbClient: dbClientMock}
+
+	err := repo.SavePayment(&models.Payment{OrderId: aValidOrderId})
+
+	assert.EqualError(t, err, "unable to save the payment of the order: 5")
+}
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"errors"
-	"fmt"
 	"github.com/emiliocc5/online-store-api/internal/models"
 	"github.com/emiliocc5/online-store-api/internal/repository"
 	"github.com/stretchr/testify/assert"
@@ -10,20 +9,13 @@ import (
 	"testing"
 )
 
-func Test_GivenAValidCartId_ThenReturnAListOfProducts(t *testing.T) {
+func Test_GivenAValidProductId_ThenReturnAValidProduct(t *testing.T) {
 	dbClientMock := &DbClientMock{}
 
-	findProductsByCartIdQuery := getMockedQuery("cart_id = ?", aValidCartId)
 	findProductByIdQuery := getMockedQuery("id = ?", aValidProductId)
 
-	var productsCarts []models.ProductCart
-	dbClientMock.On("Find", &productsCarts, findProductsByCartIdQuery).Return(getMockedDbObject(getMockedProductCartsList(), nil)).Run(func(args mock.Arguments) {
-		arg := args.Get(0).(*[]models.ProductCart)
-		*arg = getMockedProductCartsList()
-	})
-
 	product := models.Product{}
-	dbClientMock.On("Find", &product, findProductByIdQuery).Return(getMockedDbObject(getMockedProductList(), nil)).Run(func(args mock.Arguments) {
+	dbClientMock.On("Find", &product, findProductByIdQuery).Return(getMockedDbObjectWithRows(getMockedProductList(), 1)).Run(func(args mock.Arguments) {
 		arg := args.Get(0).(*models.Product)
 		arg.Weight = getMockedProduct().Weight
 		arg.DownloadUrl = getMockedProduct().DownloadUrl
@@ -31,96 +23,111 @@ func Test_GivenAValidCartId_ThenReturnAListOfProducts(t *testing.T) {
 		arg.Label = getMockedProduct().Label
 		arg.CategoryId = getMockedProduct().CategoryId
 		arg.Type = getMockedProduct().Type
+		arg.Price = getMockedProduct().Price
+	})
+
+	category := models.Category{}
+	dbClientMock.On("Find", &category, getMockedQuery("id = ?", aValidCategoryId)).Return(getMockedDbObjectWithRows(nil, 1)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Category)
+		*arg = getMockedCategory()
 	})
 
 	repo := repository.PgProductRepository{DbClient: dbClientMock}
 
-	resp, err := repo.FindProductsFromCart(aValidCartId)
+	prod, err := repo.FindProductById(aValidProductId)
 
+	expectedProduct := getMockedProduct()
+	expectedProduct.Category = getMockedCategory()
 	assert.Nil(t, err)
-	assert.Equal(t, getMockedProductList(), *resp)
+	assert.Equal(t, expectedProduct, *prod)
 	dbClientMock.AssertNumberOfCalls(t, "Find", 2)
 }
 
-func Test_GivenAValidCartId_ThenNotFoundListOfProducts(t *testing.T) {
+func Test_GivenAnUnknownProductId_ThenProductNotFound(t *testing.T) {
 	dbClientMock := &DbClientMock{}
 
-	findProductsByCartIdQuery := getMockedQuery("cart_id = ?", aValidCartId)
-
-	var productsCarts []models.ProductCart
-	dbClientMock.On("Find", &productsCarts, findProductsByCartIdQuery).Return(getMockedDbObject(nil, errors.New("unable to find list of products")))
+	product := models.Product{}
+	dbClientMock.On("Find", &product, getMockedQuery("id = ?", aNotValidProductId)).Return(getMockedDbObjectWithRows(nil, 0))
 
 	repo := repository.PgProductRepository{DbClient: dbClientMock}
 
-	resp, err := repo.FindProductsFromCart(aValidCartId)
+	prod, err := repo.FindProductById(aNotValidProductId)
 
-	assert.EqualError(t, err, fmt.Sprintf("unable to retrieve the list of products"))
-	assert.Nil(t, resp)
+	assert.EqualError(t, err, "unable to find the product in our db")
+	assert.Nil(t, prod)
 	dbClientMock.AssertNumberOfCalls(t, "Find", 1)
-	dbClientMock.AssertNotCalled(t, "Find", mock.AnythingOfType("models.Product"))
 }
 
-func Test_GivenAValidCartId_AndProductsFound_NotFoundProductsById_ThenReturnEmptyList(t *testing.T) {
+func Test_GivenANotValidProductId_ThenUnableToFindProductById(t *testing.T) {
 	dbClientMock := &DbClientMock{}
 
-	findProductsByCartIdQuery := getMockedQuery("cart_id = ?", aValidCartId)
-	findProductByIdQuery := getMockedQuery("id = ?", aValidProductId)
-
-	var productsCarts []models.ProductCart
-	dbClientMock.On("Find", &productsCarts, findProductsByCartIdQuery).Return(getMockedDbObject(getMockedProductCartsList(), nil)).Run(func(args mock.Arguments) {
-		arg := args.Get(0).(*[]models.ProductCart)
-		*arg = getMockedProductCartsList()
-	})
+	findProductByIdQuery := getMockedQuery("id = ?", aNotValidProductId)
 
 	product := models.Product{}
 	dbClientMock.On("Find", &product, findProductByIdQuery).Return(getMockedDbObject(nil, errors.New("unable to find by id")))
+
 	repo := repository.PgProductRepository{DbClient: dbClientMock}
 
-	resp, err := repo.FindProductsFromCart(aValidCartId)
+	prod, err := repo.FindProductById(aNotValidProductId)
+
+	assert.EqualError(t, err, "unable to find the product in our db")
+	assert.Nil(t, prod)
+	dbClientMock.AssertNumberOfCalls(t, "Find", 1)
+}
+
+func Test_GivenAValidProduct_ThenCreateProduct(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	product := getMockedProduct()
+	product.Id = 0
+	dbClientMock.On("Transaction", mock.Anything, mock.Anything).Return(nil)
+
+	repo := repository.PgProductRepository{DbClient: dbClientMock}
+
+	err := repo.CreateProduct(&product)
 
 	assert.Nil(t, err)
-	assert.Equal(t, 0, len(*resp))
-	dbClientMock.AssertNumberOfCalls(t, "Find", 2)
+	dbClientMock.AssertNumberOfCalls(t, "Transaction", 1)
 }
 
-func Test_GivenAValidProductId_ThenReturnAValidProduct(t *testing.T) {
+func Test_GivenAValidProduct_ThenUnableToCreateProduct(t *testing.T) {
 	dbClientMock := &DbClientMock{}
 
-	findProductByIdQuery := getMockedQuery("id = ?", aValidProductId)
+	product := getMockedProduct()
+	dbClientMock.On("Transaction", mock.Anything, mock.Anything).Return(errors.New("db error"))
 
-	product := models.Product{}
-	dbClientMock.On("Find", &product, findProductByIdQuery).Return(getMockedDbObject(getMockedProductList(), nil)).Run(func(args mock.Arguments) {
-		arg := args.Get(0).(*models.Product)
-		arg.Weight = getMockedProduct().Weight
-		arg.DownloadUrl = getMockedProduct().DownloadUrl
-		arg.Id = getMockedProduct().Id
-		arg.Label = getMockedProduct().Label
-		arg.CategoryId = getMockedProduct().CategoryId
-		arg.Type = getMockedProduct().Type
-	})
+	repo := repository.PgProductRepository{DbClient: dbClientMock}
+
+	err := repo.CreateProduct(&product)
+
+	assert.EqualError(t, err, "unable to create the product")
+}
+
+func Test_GivenAValidProduct_ThenUpdateProductWithoutCategory(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	product := getMockedProduct()
+	product.Category = getMockedCategory()
+	expected := getMockedProduct()
+	dbClientMock.On("Save", &expected).Return(getMockedDbObject(nil, nil))
 
 	repo := repository.PgProductRepository{DbClient: dbClientMock}
 
-	prod, err := repo.FindProductById(aValidProductId)
+	err := repo.UpdateProduct(&product)
 
 	assert.Nil(t, err)
-	assert.Equal(t, getMockedProduct(), *prod)
-	dbClientMock.AssertNumberOfCalls(t, "Find", 1)
+	dbClientMock.AssertCalled(t, "Save", &expected)
 }
 
-func Test_GivenANotValidProductId_ThenUnableToFindProductById(t *testing.T) {
+func Test_GivenAValidProduct_ThenUnableToUpdateProduct(t *testing.T) {
 	dbClientMock := &DbClientMock{}
 
-	findProductByIdQuery := getMockedQuery("id = ?", aNotValidProductId)
-
-	product := models.Product{}
-	dbClientMock.On("Find", &product, findProductByIdQuery).Return(getMockedDbObject(nil, errors.New("unable to find by id")))
+	product := getMockedProduct()
+	dbClientMock.On("Save", mock.Anything).Return(getMockedDbObject(nil, errors.New("db error")))
 
 	repo := repository.PgProductRepository{DbClient: dbClientMock}
 
-	prod, err := repo.FindProductById(aNotValidProductId)
+	err := repo.UpdateProduct(&product)
 
-	assert.EqualError(t, err, "unable to find the product in our db")
-	assert.Nil(t, prod)
-	dbClientMock.AssertNumberOfCalls(t, "Find", 1)
+	assert.EqualError(t, err, "unable to update the product")
 }
@@ -0,0 +1,448 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/config"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// contractBackends are the stores every contract case runs against, so they
+// stay interchangeable.
+var contractBackends = map[string]func(t *testing.T) repository.Repositories{
+	config.DriverMemory: func(t *testing.T) repository.Repositories {
+		return repository.NewMemoryRepositories(repository.NewMemoryStore())
+	},
+	config.DriverSqlite: func(t *testing.T) repository.Repositories {
+		cfg := config.Default().Database
+		cfg.Driver = config.DriverSqlite
+		cfg.Path = filepath.Join(t.TempDir(), "store.db")
+
+		db, err := repository.Connect(cfg, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			sqlDB, _ := db.DB()
+			_ = sqlDB.Close()
+		})
+		return repository.NewGormRepositories(db)
+	},
+}
+
+func runContract(t *testing.T, contract func(t *testing.T, repos repository.Repositories)) {
+	for name, newRepositories := range contractBackends {
+		newRepositories := newRepositories
+		t.Run(name, func(t *testing.T) {
+			contract(t, newRepositories(t))
+		})
+	}
+}
+
+func givenACategory(t *testing.T, repos repository.Repositories) models.Category {
+	category := models.Category{Label: "Peripherals"}
+	assert.Nil(t, repos.Category.CreateCategory(&category))
+	return category
+}
+
+// givenAStockedProduct creates a physical product with onHand units.
+func givenAStockedProduct(t *testing.T, repos repository.Repositories, label string, onHand int) models.Product {
+	product := models.Product{
+		CategoryId: givenACategory(t, repos).Id,
+		Label:      label,
+		Type:       models.ProductTypePhysical,
+		Weight:     1,
+		Price:      models.Money{Amount: 1000, Currency: "USD"},
+	}
+	assert.Nil(t, repos.Product.CreateProduct(&product))
+	_, err := repos.Stock.SetOnHand(product.Id, onHand)
+	assert.Nil(t, err)
+	return product
+}
+
+func givenAClient(t *testing.T, repos repository.Repositories, email string) models.Client {
+	client := models.Client{Name: "Ada Lovelace", Email: email}
+	assert.Nil(t, repos.Client.CreateClient(&client))
+	return client
+}
+
+// getMockedShipping quotes the standard option for a parcel of weight kg.
+func getMockedShipping(weight float64) models.OrderShipping {
+	return models.OrderShipping{Option: "standard", Zone: "domestic", Weight: weight, Cost: models.Money{Amount: 500, Currency: "USD"}}
+}
+
+func Test_Contract_GivenACartWithStock_ThenCreateOrderReservesItAndEmptiesTheCart(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		product := givenAStockedProduct(t, repos, "Keyboard", 5)
+		assert.Nil(t, repos.Cart.SetProductQuantity(product.Id, client.Id, 2))
+
+		order, err := repos.Order.CreateOrder(client.Id, getMockedShipping(2))
+
+		assert.Nil(t, err)
+		assert.Equal(t, models.OrderStatusPending, order.Status)
+		assert.Equal(t, models.Money{Amount: 2500, Currency: "USD"}, order.Total)
+		stock, _ := repos.Stock.GetStock(product.Id)
+		assert.Equal(t, 2, stock.Reserved)
+		cart, _ := repos.Cart.GetCartByClient(client.Id)
+		items, _ := repos.Cart.GetCartItems(cart.Id)
+		assert.Empty(t, *items)
+	})
+}
+
+func Test_Contract_GivenNotEnoughStock_ThenCreateOrderLeavesEverythingUntouched(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		plenty := givenAStockedProduct(t, repos, "Keyboard", 5)
+		scarce := givenAStockedProduct(t, repos, "Mouse", 1)
+		assert.Nil(t, repos.Cart.SetProductQuantity(plenty.Id, client.Id, 2))
+		assert.Nil(t, repos.Cart.SetProductQuantity(scarce.Id, client.Id, 3))
+
+		_, err := repos.Order.CreateOrder(client.Id, getMockedShipping(5))
+
+		assert.True(t, errors.Is(err, models.ErrInsufficientStock))
+		stock, _ := repos.Stock.GetStock(plenty.Id)
+		assert.Equal(t, 0, stock.Reserved)
+		cart, _ := repos.Cart.GetCartByClient(client.Id)
+		items, _ := repos.Cart.GetCartItems(cart.Id)
+		assert.Len(t, *items, 2)
+	})
+}
+
+func Test_Contract_GivenAPlacedOrder_ThenCancelReleasesAndShipCommitsStock(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		product := givenAStockedProduct(t, repos, "Keyboard", 5)
+
+		assert.Nil(t, repos.Cart.SetProductQuantity(product.Id, client.Id, 2))
+		cancelled, _ := repos.Order.CreateOrder(client.Id, getMockedShipping(2))
+		assert.Nil(t, repos.Cart.SetProductQuantity(product.Id, client.Id, 1))
+		shipped, _ := repos.Order.CreateOrder(client.Id, getMockedShipping(1))
+
+		order, err := repos.Order.CancelOrder(cancelled.Id, models.OrderStatusPending, "client:1", "changed my mind")
+		assert.Nil(t, err)
+		assert.Equal(t, "changed my mind", order.CancellationReason)
+		_, err = repos.Order.TransitionOrder(shipped.Id, models.OrderStatusPending, models.OrderStatusPaid, "system")
+		assert.Nil(t, err)
+		_, err = repos.Order.TransitionOrder(shipped.Id, models.OrderStatusPaid, models.OrderStatusShipped, "warehouse")
+		assert.Nil(t, err)
+
+		stock, _ := repos.Stock.GetStock(product.Id)
+		assert.Equal(t, 4, stock.OnHand)
+		assert.Equal(t, 0, stock.Reserved)
+		transitions, _ := repos.Order.ListOrderTransitions(shipped.Id)
+		assert.Len(t, *transitions, 2)
+	})
+}
+
+func Test_Contract_GivenAProductWithoutLedger_ThenItSellsWithoutReservingStock(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		product := models.Product{
+			CategoryId: givenACategory(t, repos).Id,
+			Label:      "Manual",
+			Type:       models.ProductTypeDigital,
+			Price:      models.Money{Amount: 1000, Currency: "USD"},
+		}
+		assert.Nil(t, repos.Product.CreateProduct(&product))
+		product.Type = models.ProductTypePhysical
+		product.Weight = 1
+		assert.Nil(t, repos.Product.UpdateProduct(&product))
+		assert.Nil(t, repos.Cart.SetProductQuantity(product.Id, client.Id, 3))
+
+		order, err := repos.Order.CreateOrder(client.Id, getMockedShipping(3))
+		assert.Nil(t, err)
+		_, err = repos.Stock.SetOnHand(product.Id, 10)
+		assert.Nil(t, err)
+		_, err = repos.Order.CancelOrder(order.Id, models.OrderStatusPending, "client:1", "changed my mind")
+		assert.Nil(t, err)
+
+		stock, _ := repos.Stock.GetStock(product.Id)
+		assert.Equal(t, models.Stock{ProductId: product.Id, OnHand: 10}, *stock)
+	})
+}
+
+func Test_Contract_GivenACartChangedAfterTheQuote_ThenUnableToCreateOrder(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		product := givenAStockedProduct(t, repos, "Keyboard", 5)
+		assert.Nil(t, repos.Cart.SetProductQuantity(product.Id, client.Id, 3))
+
+		_, err := repos.Order.CreateOrder(client.Id, getMockedShipping(1))
+
+		assert.True(t, errors.Is(err, models.ErrStaleShippingQuote))
+		stock, _ := repos.Stock.GetStock(product.Id)
+		assert.Equal(t, 0, stock.Reserved)
+	})
+}
+
+func Test_Contract_GivenACartLineAtTheLimit_ThenUnableToAddMore(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		product := givenAStockedProduct(t, repos, "Keyboard", 500)
+		assert.Nil(t, repos.Cart.SetProductQuantity(product.Id, client.Id, models.MaxProductQuantity-1))
+		assert.Nil(t, repos.Cart.AddProductToCart(product.Id, client.Id))
+
+		err := repos.Cart.AddProductToCart(product.Id, client.Id)
+
+		assert.True(t, errors.Is(err, models.ErrCartQuantityLimit))
+		cart, _ := repos.Cart.GetCartByClient(client.Id)
+		items, _ := repos.Cart.GetCartItems(cart.Id)
+		assert.Len(t, *items, 1)
+		assert.Equal(t, models.MaxProductQuantity, (*items)[0].Quantity)
+	})
+}
+
+func Test_Contract_GivenAPasswordResetToken_ThenItCanOnlyBeUsedOnce(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		token := models.PasswordResetToken{ClientId: client.Id, TokenHash: "a-hash", ExpiresAt: time.Now().Add(time.Hour)}
+		assert.Nil(t, repos.Token.CreatePasswordResetToken(&token))
+
+		found, err := repos.Token.FindPasswordResetToken("a-hash")
+		assert.Nil(t, err)
+		assert.Equal(t, client.Id, found.ClientId)
+
+		assert.Nil(t, repos.Token.UsePasswordResetToken(token.Id))
+		assert.True(t, errors.Is(repos.Token.UsePasswordResetToken(token.Id), auth.ErrInvalidToken))
+		used, _ := repos.Token.FindPasswordResetToken("a-hash")
+		assert.False(t, used.IsActive(time.Now()))
+	})
+}
+
+func Test_Contract_GivenAClientSessions_ThenRevokeThemAll(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		other := givenAClient(t, repos, "grace@example.com")
+		for _, e := range []models.RefreshToken{
+			{ClientId: client.Id, TokenHash: "first", ExpiresAt: time.Now().Add(time.Hour)},
+			{ClientId: client.Id, TokenHash: "second", ExpiresAt: time.Now().Add(time.Hour)},
+			{ClientId: other.Id, TokenHash: "other", ExpiresAt: time.Now().Add(time.Hour)},
+		} {
+			e := e
+			assert.Nil(t, repos.Token.CreateRefreshToken(&e))
+		}
+
+		assert.Nil(t, repos.Token.RevokeClientRefreshTokens(client.Id))
+
+		for hash, active := range map[string]bool{"first": false, "second": false, "other": true} {
+			token, _ := repos.Token.FindRefreshToken(hash)
+			assert.Equal(t, active, token.IsActive(time.Now()), hash)
+		}
+	})
+}
+
+func Test_Contract_GivenANewPhysicalProduct_ThenItHasAnEmptyLedger(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		product := models.Product{
+			CategoryId: givenACategory(t, repos).Id,
+			Label:      "Keyboard",
+			Type:       models.ProductTypePhysical,
+			Weight:     1,
+			Price:      models.Money{Amount: 1000, Currency: "USD"},
+		}
+		assert.Nil(t, repos.Product.CreateProduct(&product))
+
+		stock, err := repos.Stock.GetStock(product.Id)
+
+		assert.Nil(t, err)
+		assert.Equal(t, models.Stock{ProductId: product.Id}, *stock)
+	})
+}
+
+func Test_Contract_GivenAStaleStatus_ThenUnableToTransition(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		product := givenAStockedProduct(t, repos, "Keyboard", 5)
+		assert.Nil(t, repos.Cart.AddProductToCart(product.Id, client.Id))
+		order, _ := repos.Order.CreateOrder(client.Id, getMockedShipping(1))
+		_, _ = repos.Order.TransitionOrder(order.Id, models.OrderStatusPending, models.OrderStatusPaid, "system")
+
+		_, err := repos.Order.TransitionOrder(order.Id, models.OrderStatusPending, models.OrderStatusCancelled, "system")
+
+		assert.True(t, errors.Is(err, models.ErrInvalidOrderTransition))
+	})
+}
+
+func Test_Contract_GivenOldPendingOrders_ThenReleaseThem(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		product := givenAStockedProduct(t, repos, "Keyboard", 5)
+		assert.Nil(t, repos.Cart.AddProductToCart(product.Id, client.Id))
+		order, _ := repos.Order.CreateOrder(client.Id, getMockedShipping(1))
+
+		released, err := repos.Order.ReleaseAbandonedOrders(time.Now().Add(time.Minute))
+
+		assert.Nil(t, err)
+		assert.Equal(t, []int{order.Id}, released)
+		found, _ := repos.Order.FindOrderById(order.Id)
+		assert.Equal(t, models.OrderStatusCancelled, found.Status)
+		assert.Equal(t, repository.AbandonedOrderReason, found.CancellationReason)
+	})
+}
+
+func Test_Contract_GivenADeletedClient_ThenItIsGoneAndItsEmailIsFree(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		address := models.Address{ClientId: client.Id, PostalAddress: models.PostalAddress{Country: "US"}}
+		assert.Nil(t, repos.Address.SaveAddress(&address))
+
+		assert.Nil(t, repos.Client.DeleteClient(client.Id))
+
+		assert.False(t, repos.Client.IsClientInDataBase(client.Id))
+		found, err := repos.Client.FindClientByEmail("ada@example.com")
+		assert.Nil(t, err)
+		assert.Nil(t, found)
+		addresses, _ := repos.Address.ListAddresses(client.Id)
+		assert.Empty(t, *addresses)
+		assert.Nil(t, repos.Client.CreateClient(&models.Client{Email: "ada@example.com"}))
+		assert.EqualError(t, repos.Client.DeleteClient(client.Id), fmt.Sprintf("client with id: %v not found", client.Id))
+	})
+}
+
+func Test_Contract_GivenTheDefaultAddressIsDeleted_ThenTheOldestRemainingTakesItOver(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		other := givenAClient(t, repos, "grace@example.com")
+		var addresses []models.Address
+		for _, city := range []string{"Springfield", "Shelbyville", "Ogdenville"} {
+			address := models.Address{ClientId: client.Id, PostalAddress: models.PostalAddress{City: city, Country: "US"}}
+			assert.Nil(t, repos.Address.SaveAddress(&address))
+			addresses = append(addresses, address)
+		}
+		otherAddress := models.Address{ClientId: other.Id, PostalAddress: models.PostalAddress{Country: "US"}}
+		assert.Nil(t, repos.Address.SaveAddress(&otherAddress))
+		addresses[2].DefaultShipping = true
+		addresses[2].DefaultBilling = true
+		assert.Nil(t, repos.Address.SaveAddress(&addresses[2]))
+
+		assert.Nil(t, repos.Address.DeleteAddress(&addresses[2]))
+
+		found, err := repos.Address.FindDefaultShippingAddress(client.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, addresses[0].Id, found.Id)
+		assert.True(t, found.DefaultBilling)
+		untouched, _ := repos.Address.FindAddress(other.Id, otherAddress.Id)
+		assert.False(t, untouched.DefaultShipping)
+	})
+}
+
+func Test_Contract_GivenTheLastAddressIsDeleted_ThenThereIsNoDefault(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		address := models.Address{ClientId: client.Id, PostalAddress: models.PostalAddress{Country: "US"}, DefaultShipping: true}
+		assert.Nil(t, repos.Address.SaveAddress(&address))
+
+		assert.Nil(t, repos.Address.DeleteAddress(&address))
+
+		found, err := repos.Address.FindDefaultShippingAddress(client.Id)
+		assert.Nil(t, err)
+		assert.Nil(t, found)
+	})
+}
+
+func Test_Contract_GivenATakenEmail_ThenUnableToCreateClient(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		givenAClient(t, repos, "ada@example.com")
+
+		err := repos.Client.CreateClient(&models.Client{Email: "ada@example.com"})
+
+		assert.True(t, errors.Is(err, models.ErrEmailTaken))
+	})
+}
+
+func Test_Contract_GivenATakenEmail_ThenUnableToUpdateClient(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		givenAClient(t, repos, "ada@example.com")
+		client := givenAClient(t, repos, "grace@example.com")
+
+		client.Email = "ada@example.com"
+		err := repos.Client.UpdateClient(&client)
+
+		assert.True(t, errors.Is(err, models.ErrEmailTaken))
+	})
+}
+
+func Test_Contract_GivenAFilter_ThenListMatchingProductsSortedAndPaged(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		category := givenACategory(t, repos)
+		var products []models.Product
+		for i, label := range []string{"Blue mug", "Red mug", "Green mug", "Poster"} {
+			product := models.Product{
+				CategoryId: category.Id,
+				Label:      label,
+				Type:       models.ProductTypePhysical,
+				Price:      models.Money{Amount: int64(100 * (4 - i)), Currency: "USD"},
+			}
+			assert.Nil(t, repos.Product.CreateProduct(&product))
+			products = append(products, product)
+		}
+		archivedAt := time.Now()
+		products[0].ArchivedAt = &archivedAt
+		assert.Nil(t, repos.Product.UpdateProduct(&products[0]))
+
+		found, total, err := repos.Product.ListProducts(models.ProductFilter{Label: "MUG", Sort: models.ProductSortByPrice, Page: 1, PageSize: 1})
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Len(t, *found, 1)
+		assert.Equal(t, "Green mug", (*found)[0].Label)
+	})
+}
+
+func Test_Contract_GivenTheDownloadLimit_ThenUnableToRecordMore(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		client := givenAClient(t, repos, "ada@example.com")
+		ebook := models.Product{
+			CategoryId:  givenACategory(t, repos).Id,
+			Label:       "Ebook",
+			Type:        models.ProductTypeDigital,
+			DownloadUrl: "https://files.example.com/ebook.pdf",
+			Price:       models.Money{Amount: 500, Currency: "USD"},
+		}
+		assert.Nil(t, repos.Product.CreateProduct(&ebook))
+		assert.Nil(t, repos.Cart.AddProductToCart(ebook.Id, client.Id))
+		order, _ := repos.Order.CreateOrder(client.Id, models.OrderShipping{})
+
+		assert.Nil(t, repos.Download.RecordDownload(&models.Download{OrderId: order.Id, ProductId: ebook.Id}, 1))
+		err := repos.Download.RecordDownload(&models.Download{OrderId: order.Id, ProductId: ebook.Id}, 1)
+
+		assert.True(t, errors.Is(err, models.ErrDownloadLimitReached))
+		item, _ := repos.Download.FindOrderItem(order.Id, ebook.Id)
+		assert.Equal(t, 1, item.Downloads)
+	})
+}
+
+func Test_Contract_GivenConcurrentCheckouts_ThenStockIsNeverOversold(t *testing.T) {
+	runContract(t, func(t *testing.T, repos repository.Repositories) {
+		product := givenAStockedProduct(t, repos, "Keyboard", 3)
+
+		var wait sync.WaitGroup
+		placed := make(chan int, 10)
+		for i := 0; i < 10; i++ {
+			client := givenAClient(t, repos, fmt.Sprintf("client%v@example.com", i))
+			assert.Nil(t, repos.Cart.AddProductToCart(product.Id, client.Id))
+
+			wait.Add(1)
+			go func(clientId int) {
+				defer wait.Done()
+				if _, err := repos.Order.CreateOrder(clientId, getMockedShipping(1)); err == nil {
+					placed <- clientId
+				}
+			}(client.Id)
+		}
+		wait.Wait()
+		close(placed)
+
+		assert.Len(t, placed, 3)
+		stock, _ := repos.Stock.GetStock(product.Id)
+		assert.Equal(t, 3, stock.Reserved)
+	})
+}
@@ -1,22 +1,28 @@
 package repository
 
 import (
+	"database/sql"
 	"github.com/emiliocc5/online-store-api/internal/models"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 )
 
 const (
-	aValidClientId     = 123
-	aValidProductId    = 123
-	aValidCartId       = 1
-	aValidCategoryId   = 1
-	aValidLabel        = "aValidLabel"
-	aValidType         = 1
-	aValidDownloadUrl  = ""
-	aValidWeight       = 7.5
-	aNotValidClientId  = 000
-	aNotValidProductId = 0
+	aValidClientId      = 123
+	aValidProductId     = 123
+	aValidCartId        = 1
+	aValidOrderId       = 5
+	aValidProductCartId = 9
+	aValidAddressId     = 3
+	aValidCategoryId    = 1
+	aValidLabel         = "aValidLabel"
+	aValidType          = models.ProductTypePhysical
+	aValidDownloadUrl   = ""
+	aValidWeight        = 7.5
+	aValidPriceAmount   = 1999
+	aValidCurrency      = "USD"
+	aNotValidClientId   = 000
+	aNotValidProductId  = 0
 )
 
 type DbClientMock struct{ mock.Mock }
@@ -41,6 +47,36 @@ func (mock *DbClientMock) FirstOrCreate(dest interface{}, conds ...interface{})
 	return args.Get(0).(*gorm.DB)
 }
 
+func (mock *DbClientMock) Model(value interface{}) (tx *gorm.DB) {
+	args := mock.Called(value)
+	return args.Get(0).(*gorm.DB)
+}
+
+func (mock *DbClientMock) Where(query interface{}, args ...interface{}) (tx *gorm.DB) {
+	arguments := mock.Called(query, args)
+	return arguments.Get(0).(*gorm.DB)
+}
+
+func (mock *DbClientMock) Transaction(fc func(tx *gorm.DB) error, opts ...*sql.TxOptions) (err error) {
+	args := mock.Called(fc, opts)
+	return args.Error(0)
+}
+
+func (mock *DbClientMock) Save(value interface{}) (tx *gorm.DB) {
+	args := mock.Called(value)
+	return args.Get(0).(*gorm.DB)
+}
+
+func (mock *DbClientMock) Exec(sql string, values ...interface{}) (tx *gorm.DB) {
+	args := mock.Called(sql, values)
+	return args.Get(0).(*gorm.DB)
+}
+
+func (mock *DbClientMock) Delete(value interface{}, conds ...interface{}) (tx *gorm.DB) {
+	args := mock.Called(value, conds)
+	return args.Get(0).(*gorm.DB)
+}
+
 func getMockedDbObject(dest interface{}, err error) *gorm.DB {
 	dbOb := gorm.DB{
 		Statement: &gorm.Statement{Dest: dest},
@@ -49,6 +85,12 @@ func getMockedDbObject(dest interface{}, err error) *gorm.DB {
 	return &dbOb
 }
 
+func getMockedDbObjectWithRows(dest interface{}, rowsAffected int64) *gorm.DB {
+	dbOb := getMockedDbObject(dest, nil)
+	dbOb.RowsAffected = rowsAffected
+	return dbOb
+}
+
 func getMockedClientCart() models.Cart {
 	return models.Cart{
 		Id:       aValidCartId,
@@ -64,7 +106,7 @@ func getMockedClient() models.Client {
 	}
 }
 func getMockedProductCart() models.ProductCart {
-	return models.ProductCart{ProductId: aValidProductId, Product: getMockedProduct(), Cart: getMockedClientCart(), CartId: aValidCartId}
+	return models.ProductCart{ProductId: aValidProductId, Product: getMockedProduct(), Cart: getMockedClientCart(), CartId: aValidCartId, Quantity: 1}
 }
 
 func getMockedProductCartsList() []models.ProductCart {
@@ -74,7 +116,12 @@ func getMockedProductCartsList() []models.ProductCart {
 }
 
 func getMockedProduct() models.Product {
-	return models.Product{Id: aValidProductId, CategoryId: aValidCategoryId, Label: aValidLabel, Type: aValidType, DownloadUrl: aValidDownloadUrl, Weight: aValidWeight}
+	return models.Product{Id: aValidProductId, CategoryId: aValidCategoryId, Label: aValidLabel, Type: aValidType, DownloadUrl: aValidDownloadUrl, Weight: aValidWeight,
+		Price: models.Money{Amount: aValidPriceAmount, Currency: aValidCurrency}}
+}
+
+func getMockedCategory() models.Category {
+	return models.Category{Id: aValidCategoryId, Label: "Peripherals"}
 }
 
 func getMockedProductList() []models.Product {
@@ -82,6 +129,10 @@ func getMockedProductList() []models.Product {
 	return append(products, getMockedProduct())
 }
 
+func getMockedProductCartQuery() []interface{} {
+	return []interface{}{"cart_id = ? AND product_id = ?", aValidCartId, aValidProductId}
+}
+
 func getMockedQuery(query string, parameter int) []interface{} {
 	var queryArray []interface{}
 	queryArray = append(queryArray, query, parameter)
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func Test_GivenAStockedProduct_ThenReturnStock(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("Find", mock.Anything, []interface{}{"product_id = ?", aValidProductId}).
+		Return(getMockedDbObjectWithRows(nil, 1)).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Stock)
+		arg.OnHand = 10
+		arg.Reserved = 4
+	})
+
+	repo := repository.PgStockRepository{DbClient: dbClientMock}
+
+	stock, err := repo.GetStock(aValidProductId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, aValidProductId, stock.ProductId)
+	assert.Equal(t, 6, stock.Available())
+}
+
+func Test_GivenAProductWithoutLedger_ThenReturnNoStock(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("Find", mock.Anything, []interface{}{"product_id = ?", aValidProductId}).Return(getMockedDbObjectWithRows(nil, 0))
+
+	repo := repository.PgStockRepository{DbClient: dbClientMock}
+
+	stock, err := repo.GetStock(aValidProductId)
+
+	assert.Nil(t, err)
+	assert.Nil(t, stock)
+}
+
+func Test_GivenAProductId_ThenUnableToGetStock(t *testing.T) {
+	dbClientMock := &DbClientMock{}
+
+	dbClientMock.On("Find", mock.Anything, []interface{}{"product_id = ?", aValidProductId}).Return(getMockedDbObject(nil, errors.New("db error")))
+
+	repo := repository.PgStockRepository{DbClient: dbClientMock}
+
+	_, err := repo.GetStock(aValidProductId)
+
+	assert.EqualError(t, err, "unable to get the stock of the product: 123")
+}
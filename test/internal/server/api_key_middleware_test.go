@@ -0,0 +1,88 @@
+package server
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/server"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type ApiKeyServiceMock struct{ mock.Mock }
+
+func (mock *ApiKeyServiceMock) CreateApiKey(req request.ApiKeyRequest) (response.CreatedApiKeyResponse, error) {
+	args := mock.Called(req)
+	return args.Get(0).(response.CreatedApiKeyResponse), args.Error(1)
+}
+
+func (mock *ApiKeyServiceMock) ListApiKeys() ([]response.ApiKeyResponse, error) {
+	args := mock.Called()
+	return args.Get(0).([]response.ApiKeyResponse), args.Error(1)
+}
+
+func (mock *ApiKeyServiceMock) RevokeApiKey(apiKeyId int) error {
+	args := mock.Called(apiKeyId)
+	return args.Error(0)
+}
+
+func (mock *ApiKeyServiceMock) Authenticate(key string) (*models.ApiKey, error) {
+	args := mock.Called(key)
+	return args.Get(0).(*models.ApiKey), args.Error(1)
+}
+
+func serveWithApiKey(apiKeys *ApiKeyServiceMock, key string) *httptest.ResponseRecorder {
+	engine := gin.New()
+	engine.PUT("/api/admin/products/1/stock", server.RequireScope(apiKeys, models.ScopeInventoryWrite), func(context *gin.Context) {
+		context.Status(http.StatusOK)
+	})
+
+	request := httptest.NewRequest(http.MethodPut, "/api/admin/products/1/stock", nil)
+	if key != "" {
+		request.Header.Set(server.ApiKeyHeader, key)
+	}
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func Test_RequireScope_WithGrantedScope_ThenOk(t *testing.T) {
+	apiKeys := &ApiKeyServiceMock{}
+	apiKeys.On("Authenticate", "osk_a1b2c3d4_secret").Return(&models.ApiKey{Prefix: "a1b2c3d4", Scopes: "inventory:read inventory:write"}, nil)
+
+	recorder := serveWithApiKey(apiKeys, "osk_a1b2c3d4_secret")
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func Test_RequireScope_WithoutTheScope_ThenForbidden(t *testing.T) {
+	apiKeys := &ApiKeyServiceMock{}
+	apiKeys.On("Authenticate", "osk_a1b2c3d4_secret").Return(&models.ApiKey{Prefix: "a1b2c3d4", Scopes: "inventory:read"}, nil)
+
+	recorder := serveWithApiKey(apiKeys, "osk_a1b2c3d4_secret")
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func Test_RequireScope_WithRevokedKey_ThenUnauthorized(t *testing.T) {
+	apiKeys := &ApiKeyServiceMock{}
+	apiKeys.On("Authenticate", "osk_a1b2c3d4_secret").Return((*models.ApiKey)(nil), auth.ErrInvalidApiKey)
+
+	recorder := serveWithApiKey(apiKeys, "osk_a1b2c3d4_secret")
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func Test_RequireScope_WithoutKey_ThenUnauthorized(t *testing.T) {
+	apiKeys := &ApiKeyServiceMock{}
+
+	recorder := serveWithApiKey(apiKeys, "")
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	apiKeys.AssertNotCalled(t, "Authenticate", mock.Anything)
+}
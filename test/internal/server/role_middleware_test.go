@@ -0,0 +1,66 @@
+package server
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/server"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func getMockedTokenIssuer() *auth.TokenIssuer {
+	tokens, _ := auth.NewTokenIssuer([]byte("0123456789abcdef0123456789abcdef"), time.Minute)
+	return tokens
+}
+
+func serveAs(tokens *auth.TokenIssuer, apiKeys *ApiKeyServiceMock, role models.Role, scope models.Scope) *httptest.ResponseRecorder {
+	engine := gin.New()
+	engine.GET("/api/admin/clients/1/cart", server.RequirePermission(apiKeys, tokens, scope), func(context *gin.Context) {
+		context.Status(http.StatusOK)
+	})
+
+	token, _, _ := tokens.IssueAccessToken(1, role, time.Now())
+	request := httptest.NewRequest(http.MethodGet, "/api/admin/clients/1/cart", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func Test_RequirePermission_WithSupportRole_ThenReadsAnyCart(t *testing.T) {
+	recorder := serveAs(getMockedTokenIssuer(), &ApiKeyServiceMock{}, models.RoleSupport, models.ScopeCartsRead)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func Test_RequirePermission_WithCustomerRole_ThenForbidden(t *testing.T) {
+	recorder := serveAs(getMockedTokenIssuer(), &ApiKeyServiceMock{}, models.RoleCustomer, models.ScopeCartsRead)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func Test_RequirePermission_WithSupportRole_ThenUnableToWriteCatalog(t *testing.T) {
+	recorder := serveAs(getMockedTokenIssuer(), &ApiKeyServiceMock{}, models.RoleSupport, models.ScopeCatalogWrite)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func Test_RequirePermission_WithApiKey_ThenTheKeyScopesApply(t *testing.T) {
+	apiKeys := &ApiKeyServiceMock{}
+	apiKeys.On("Authenticate", "osk_a1b2c3d4_secret").Return(&models.ApiKey{Prefix: "a1b2c3d4", Scopes: "carts:read"}, nil)
+
+	engine := gin.New()
+	engine.GET("/api/admin/clients/1/cart", server.RequirePermission(apiKeys, getMockedTokenIssuer(), models.ScopeCartsRead), func(context *gin.Context) {
+		context.Status(http.StatusOK)
+	})
+	request := httptest.NewRequest(http.MethodGet, "/api/admin/clients/1/cart", nil)
+	request.Header.Set(server.ApiKeyHeader, "osk_a1b2c3d4_secret")
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
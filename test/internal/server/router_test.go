@@ -0,0 +1,109 @@
+package server
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/app"
+	"github.com/emiliocc5/online-store-api/internal/config"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/emiliocc5/online-store-api/internal/server"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func getConfiguredEngine() *gin.Engine {
+	engine, _ := getConfiguredEngineAndApp()
+	return engine
+}
+
+func getConfiguredEngineAndApp() (*gin.Engine, *app.App) {
+	a, _ := app.New(config.Default(), repository.Repositories{})
+	engine := gin.New()
+	server.ConfigureRouter(engine, a)
+	return engine, a
+}
+
+// getBackOfficeRoutes lists every registered admin route, plus the order
+// transitions, with their path parameters filled in.
+func getBackOfficeRoutes(engine *gin.Engine) []gin.RouteInfo {
+	var routes []gin.RouteInfo
+	for _, e := range engine.Routes() {
+		if !strings.HasPrefix(e.Path, "/api/admin/") && !strings.HasSuffix(e.Path, "/transitions") {
+			continue
+		}
+		e.Path = pathParameter.ReplaceAllString(e.Path, "1")
+		routes = append(routes, e)
+	}
+	return routes
+}
+
+var pathParameter = regexp.MustCompile(`:[A-Za-z]+`)
+
+func Test_GivenAnAnonymousRequest_ThenProtectedRoutesAreUnauthorized(t *testing.T) {
+	engine := getConfiguredEngine()
+
+	routes := []struct{ method, path string }{
+		{http.MethodGet, "/api/cart"},
+		{http.MethodPost, "/api/orders"},
+		{http.MethodGet, "/api/orders/1/downloads/2"},
+		{http.MethodPost, "/api/orders/1/transitions"},
+		{http.MethodGet, "/api/clients/me"},
+		{http.MethodGet, "/api/clients/me/addresses"},
+		{http.MethodPost, "/api/admin/products"},
+		{http.MethodPut, "/api/admin/products/1/stock"},
+		{http.MethodDelete, "/api/admin/categories/1"},
+		{http.MethodGet, "/api/admin/clients/1/cart"},
+		{http.MethodGet, "/api/admin/clients/1/orders/2"},
+		{http.MethodPut, "/api/admin/clients/1/role"},
+		{http.MethodGet, "/api/admin/api-keys"},
+	}
+	for _, e := range routes {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(e.method, e.path, nil))
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, e.method+" "+e.path)
+	}
+}
+
+func Test_GivenAClientIdHeader_ThenTheCartIsStillUnauthorized(t *testing.T) {
+	engine := getConfiguredEngine()
+
+	request := httptest.NewRequest(http.MethodGet, "/api/cart", nil)
+	request.Header.Set("clientId", "1")
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func Test_GivenAnyBackOfficeRoute_ThenAnonymousRequestsAreUnauthorized(t *testing.T) {
+	engine := getConfiguredEngine()
+
+	routes := getBackOfficeRoutes(engine)
+	assert.NotEmpty(t, routes)
+	for _, e := range routes {
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, httptest.NewRequest(e.Method, e.Path, nil))
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, e.Method+" "+e.Path)
+	}
+}
+
+func Test_GivenAnyBackOfficeRoute_ThenCustomersAreForbidden(t *testing.T) {
+	engine, a := getConfiguredEngineAndApp()
+	token, _, _ := a.Tokens.IssueAccessToken(1, models.RoleCustomer, time.Now())
+
+	for _, e := range getBackOfficeRoutes(engine) {
+		request := httptest.NewRequest(e.Method, e.Path, nil)
+		request.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		engine.ServeHTTP(recorder, request)
+
+		assert.Equal(t, http.StatusForbidden, recorder.Code, e.Method+" "+e.Path)
+	}
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/app"
+	"github.com/emiliocc5/online-store-api/internal/config"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/payment"
+	"github.com/emiliocc5/online-store-api/internal/repository"
+	"github.com/emiliocc5/online-store-api/internal/server"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const anAdminApiKey = "an-admin-api-key-of-at-least-32-chars"
+
+// storeClient drives a server backed by the memory repositories over HTTP.
+type storeClient struct {
+	t      *testing.T
+	server server.Server
+}
+
+func getStoreClient(t *testing.T) storeClient {
+	return getStoreClientWithPayments(t, payment.FakeBehaviorApprove)
+}
+
+// getStoreClientWithPayments answers every payment with the given fake
+// gateway behavior.
+func getStoreClientWithPayments(t *testing.T, behavior string) storeClient {
+	cfg := config.Default()
+	cfg.Database.Driver = config.DriverMemory
+	cfg.Auth.AdminApiKey = anAdminApiKey
+	cfg.Payment.FakeBehavior = behavior
+
+	a, err := app.New(cfg, repository.NewMemoryRepositories(repository.NewMemoryStore()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := server.New(cfg, a)
+	s.ConfigureRoutes()
+	return storeClient{t: t, server: s}
+}
+
+// do sends body as JSON authenticated with the given headers and decodes the
+// response into out when it is not nil.
+func (sc storeClient) do(method, path string, headers map[string]string, body, out interface{}) int {
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			sc.t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	sc.server.ServeHTTP(recorder, req)
+	if out != nil {
+		assert.Nil(sc.t, json.Unmarshal(recorder.Body.Bytes(), out), recorder.Body.String())
+	}
+	return recorder.Code
+}
+
+func Test_GivenAMemoryStore_ThenAClientCanBuyWhatAnAdminStocked(t *testing.T) {
+	store := getStoreClient(t)
+	admin := map[string]string{server.ApiKeyHeader: anAdminApiKey}
+
+	var category response.CategoryResponse
+	code := store.do(http.MethodPost, "/api/admin/categories", admin, request.CategoryRequest{Label: "Peripherals"}, &category)
+	assert.Equal(t, http.StatusCreated, code)
+
+	var product response.ProductResponse
+	code = store.do(http.MethodPost, "/api/admin/products", admin, request.ProductRequest{
+		CategoryId: category.Id,
+		Label:      "Keyboard",
+		Type:       models.ProductTypePhysical,
+		Weight:     0.8,
+		Price:      request.MoneyRequest{Amount: 4999, Currency: "USD"},
+	}, &product)
+	assert.Equal(t, http.StatusCreated, code)
+
+	onHand := 5
+	stockPath := fmt.Sprintf("/api/admin/products/%v/stock", product.Id)
+	code = store.do(http.MethodPut, stockPath, admin, request.SetStockRequest{OnHand: &onHand}, nil)
+	assert.Equal(t, http.StatusOK, code)
+
+	signUp := request.SignUpRequest{
+		ClientRequest: request.ClientRequest{Name: "Ada Lovelace", Email: "ada@example.com"},
+		Password:      "correct horse battery staple",
+	}
+	code = store.do(http.MethodPost, "/api/clients", nil, signUp, nil)
+	assert.Equal(t, http.StatusCreated, code)
+
+	var tokens response.TokenResponse
+	code = store.do(http.MethodPost, "/api/auth/login", nil, request.LoginRequest{Email: signUp.Email, Password: signUp.Password}, &tokens)
+	assert.Equal(t, http.StatusOK, code)
+	client := map[string]string{"Authorization": "Bearer " + tokens.AccessToken}
+
+	code = store.do(http.MethodPost, fmt.Sprintf("/api/cart/products/%v", product.Id), client, nil, nil)
+	assert.Equal(t, http.StatusOK, code)
+
+	var address response.AddressResponse
+	code = store.do(http.MethodPost, "/api/clients/me/addresses", client, request.AddressRequest{
+		Recipient: "Ada Lovelace", Line1: "1 Main St", City: "Springfield", Region: "IL", PostalCode: "62701", Country: "US",
+	}, &address)
+	assert.Equal(t, http.StatusCreated, code)
+
+	var order response.CreateOrderResponse
+	code = store.do(http.MethodPost, "/api/orders", client, request.CreateOrderRequest{ShippingAddressId: address.Id, ShippingOption: "standard"}, &order)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.Equal(t, string(models.OrderStatusPaid), order.Status)
+
+	var orders response.ListOrdersResponse
+	code = store.do(http.MethodGet, "/api/orders", client, nil, &orders)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, orders.Orders, 1)
+	assert.Equal(t, order.OrderId, orders.Orders[0].Id)
+	assert.Equal(t, 1, orders.Orders[0].ItemCount)
+
+	var stock response.StockResponse
+	code = store.do(http.MethodGet, stockPath, admin, nil, &stock)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1, stock.Reserved)
+	assert.Equal(t, 4, stock.Available)
+
+	transitionsPath := fmt.Sprintf("/api/orders/%v/transitions", order.OrderId)
+	code = store.do(http.MethodPost, transitionsPath, admin, request.OrderTransitionRequest{Status: models.OrderStatusFulfilled}, nil)
+	assert.Equal(t, http.StatusCreated, code)
+
+	var transitions []response.OrderTransitionResponse
+	code = store.do(http.MethodGet, transitionsPath, admin, nil, &transitions)
+	assert.Equal(t, http.StatusOK, code)
+	last := transitions[len(transitions)-1]
+	assert.Equal(t, string(models.OrderStatusFulfilled), last.To)
+	assert.Equal(t, "api-key:bootstrap", last.Actor)
+}
+
+func Test_GivenAMemoryStore_ThenAClientCannotBuyMoreThanIsInStock(t *testing.T) {
+	store := getStoreClient(t)
+	admin := map[string]string{server.ApiKeyHeader: anAdminApiKey}
+
+	var category response.CategoryResponse
+	store.do(http.MethodPost, "/api/admin/categories", admin, request.CategoryRequest{Label: "Peripherals"}, &category)
+	var product response.ProductResponse
+	code := store.do(http.MethodPost, "/api/admin/products", admin, request.ProductRequest{
+		CategoryId: category.Id,
+		Label:      "Mouse",
+		Type:       models.ProductTypePhysical,
+		Weight:     0.1,
+		Price:      request.MoneyRequest{Amount: 1999, Currency: "USD"},
+	}, &product)
+	assert.Equal(t, http.StatusCreated, code)
+
+	signUp := request.SignUpRequest{
+		ClientRequest: request.ClientRequest{Name: "Ada Lovelace", Email: "ada@example.com"},
+		Password:      "correct horse battery staple",
+	}
+	store.do(http.MethodPost, "/api/clients", nil, signUp, nil)
+	var tokens response.TokenResponse
+	store.do(http.MethodPost, "/api/auth/login", nil, request.LoginRequest{Email: signUp.Email, Password: signUp.Password}, &tokens)
+	client := map[string]string{"Authorization": "Bearer " + tokens.AccessToken}
+
+	var failure response.ErrorResponse
+	code = store.do(http.MethodPost, fmt.Sprintf("/api/cart/products/%v", product.Id), client, nil, &failure)
+
+	assert.Equal(t, http.StatusConflict, code)
+	assert.NotEmpty(t, failure.Error)
+}
+
+func Test_GivenADeclinedCard_ThenTheOrderIsPlacedAwaitingPayment(t *testing.T) {
+	store := getStoreClientWithPayments(t, payment.FakeBehaviorDecline)
+	admin := map[string]string{server.ApiKeyHeader: anAdminApiKey}
+
+	var category response.CategoryResponse
+	store.do(http.MethodPost, "/api/admin/categories", admin, request.CategoryRequest{Label: "Books"}, &category)
+	var product response.ProductResponse
+	code := store.do(http.MethodPost, "/api/admin/products", admin, request.ProductRequest{
+		CategoryId:  category.Id,
+		Label:       "Manual",
+		Type:        models.ProductTypeDigital,
+		DownloadUrl: "https://files.example.com/manual.pdf",
+		Price:       request.MoneyRequest{Amount: 999, Currency: "USD"},
+	}, &product)
+	assert.Equal(t, http.StatusCreated, code)
+
+	signUp := request.SignUpRequest{
+		ClientRequest: request.ClientRequest{Name: "Ada Lovelace", Email: "ada@example.com"},
+		Password:      "correct horse battery staple",
+	}
+	store.do(http.MethodPost, "/api/clients", nil, signUp, nil)
+	var tokens response.TokenResponse
+	store.do(http.MethodPost, "/api/auth/login", nil, request.LoginRequest{Email: signUp.Email, Password: signUp.Password}, &tokens)
+	client := map[string]string{"Authorization": "Bearer " + tokens.AccessToken}
+	store.do(http.MethodPost, fmt.Sprintf("/api/cart/products/%v", product.Id), client, nil, nil)
+
+	var order response.CreateOrderResponse
+	code = store.do(http.MethodPost, "/api/orders", client, request.CreateOrderRequest{}, &order)
+
+	assert.Equal(t, http.StatusCreated, code)
+	assert.NotZero(t, order.OrderId)
+	assert.Equal(t, string(models.OrderStatusPending), order.Status)
+	assert.Equal(t, string(models.PaymentStatusDeclined), order.Payment.Status)
+	assert.NotEmpty(t, order.PaymentError)
+
+	var failure response.ErrorResponse
+	code = store.do(http.MethodPost, fmt.Sprintf("/api/orders/%v/payment", order.OrderId), client, nil, &failure)
+	assert.Equal(t, http.StatusPaymentRequired, code)
+}
+
+func Test_GivenAnIssuedPasswordReset_ThenTheClientLogsInWithTheNewPassword(t *testing.T) {
+	store := getStoreClient(t)
+	admin := map[string]string{server.ApiKeyHeader: anAdminApiKey}
+
+	signUp := request.SignUpRequest{
+		ClientRequest: request.ClientRequest{Name: "Ada Lovelace", Email: "ada@example.com"},
+		Password:      "correct horse battery staple",
+	}
+	var created response.ClientResponse
+	code := store.do(http.MethodPost, "/api/clients", nil, signUp, &created)
+	assert.Equal(t, http.StatusCreated, code)
+
+	var reset response.PasswordResetResponse
+	code = store.do(http.MethodPost, fmt.Sprintf("/api/admin/clients/%v/password-reset", created.Id), admin, nil, &reset)
+	assert.Equal(t, http.StatusCreated, code)
+	assert.NotEmpty(t, reset.ResetToken)
+
+	newPassword := request.ResetPasswordRequest{ResetToken: reset.ResetToken, Password: "tr0ub4dor and three"}
+	code = store.do(http.MethodPost, "/api/auth/password-reset", nil, newPassword, nil)
+	assert.Equal(t, http.StatusOK, code)
+
+	code = store.do(http.MethodPost, "/api/auth/password-reset", nil, newPassword, nil)
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code = store.do(http.MethodPost, "/api/auth/login", nil, request.LoginRequest{Email: signUp.Email, Password: signUp.Password}, nil)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code = store.do(http.MethodPost, "/api/auth/login", nil, request.LoginRequest{Email: signUp.Email, Password: newPassword.Password}, nil)
+	assert.Equal(t, http.StatusOK, code)
+}
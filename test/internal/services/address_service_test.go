@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func getValidAddressRequest() request.AddressRequest {
+	return request.AddressRequest{
+		Recipient:  "Ada Lovelace",
+		Line1:      "12 Analytical St",
+		City:       "Springfield",
+		Region:     "IL",
+		PostalCode: "62701",
+		Country:    "us",
+	}
+}
+
+func getMockedAddressService(addresses []models.Address) (*services.AddressServiceImpl, *AddressRepositoryMock) {
+	addressMockRepository := &AddressRepositoryMock{}
+	clientMockRepository := &ClientRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+	addressMockRepository.On("ListAddresses", aValidClientId).Return(&addresses, nil)
+	addressMockRepository.On("SaveAddress", mock.Anything).Return(nil)
+
+	return &services.AddressServiceImpl{AddressRepository: addressMockRepository, ClientRepository: clientMockRepository}, addressMockRepository
+}
+
+func Test_GivenAClientWithoutAddresses_ThenTheFirstAddressBecomesTheDefault(t *testing.T) {
+	as, _ := getMockedAddressService([]models.Address{})
+
+	resp, err := as.CreateAddress(aValidClientId, getValidAddressRequest())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "US", resp.Country)
+	assert.True(t, resp.DefaultShipping)
+	assert.True(t, resp.DefaultBilling)
+}
+
+func Test_GivenAClientWithAddresses_ThenTheNewAddressIsNotTheDefault(t *testing.T) {
+	as, _ := getMockedAddressService([]models.Address{getValidDbAddress()})
+
+	resp, err := as.CreateAddress(aValidClientId, getValidAddressRequest())
+
+	assert.Nil(t, err)
+	assert.False(t, resp.DefaultShipping)
+	assert.False(t, resp.DefaultBilling)
+}
+
+func Test_GivenTheDefaultAddressUpdatedWithoutFlags_ThenItStaysTheDefault(t *testing.T) {
+	as, addressMockRepository := getMockedAddressService([]models.Address{})
+	stored := getValidDbAddress()
+	stored.DefaultBilling = true
+	addressMockRepository.On("FindAddress", aValidClientId, aValidAddressId).Return(&stored, nil)
+	req := getValidAddressRequest()
+	req.Line1 = "14 Analytical St"
+
+	resp, err := as.UpdateAddress(aValidClientId, aValidAddressId, req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "14 Analytical St", resp.Line1)
+	assert.True(t, resp.DefaultShipping)
+	assert.True(t, resp.DefaultBilling)
+}
+
+func Test_GivenTheDefaultAddressUnflagged_ThenUnableToUpdate(t *testing.T) {
+	as, addressMockRepository := getMockedAddressService([]models.Address{})
+	stored := getValidDbAddress()
+	addressMockRepository.On("FindAddress", aValidClientId, aValidAddressId).Return(&stored, nil)
+	unflagged := false
+	req := getValidAddressRequest()
+	req.DefaultShipping = &unflagged
+
+	_, err := as.UpdateAddress(aValidClientId, aValidAddressId, req)
+
+	assert.EqualError(t, err, "flag another address as the default shipping address instead")
+	addressMockRepository.AssertNotCalled(t, "SaveAddress", mock.Anything)
+}
+
+func Test_GivenAnAddressFlaggedAsDefault_ThenUpdateIt(t *testing.T) {
+	as, addressMockRepository := getMockedAddressService([]models.Address{})
+	stored := getValidDbAddress()
+	stored.DefaultShipping = false
+	addressMockRepository.On("FindAddress", aValidClientId, aValidAddressId).Return(&stored, nil)
+	flagged := true
+	req := getValidAddressRequest()
+	req.DefaultShipping = &flagged
+
+	resp, err := as.UpdateAddress(aValidClientId, aValidAddressId, req)
+
+	assert.Nil(t, err)
+	assert.True(t, resp.DefaultShipping)
+}
+
+func Test_GivenAUSAddressWithoutRegionAndPostalCode_ThenUnableToCreate(t *testing.T) {
+	as, addressMockRepository := getMockedAddressService([]models.Address{})
+	req := getValidAddressRequest()
+	req.Region = ""
+	req.PostalCode = " "
+
+	_, err := as.CreateAddress(aValidClientId, req)
+
+	assert.EqualError(t, err, "addresses in US require: region, postalCode")
+	addressMockRepository.AssertNotCalled(t, "SaveAddress", mock.Anything)
+}
+
+func Test_GivenAnInvalidPostalCode_ThenUnableToCreate(t *testing.T) {
+	as, _ := getMockedAddressService([]models.Address{})
+	req := getValidAddressRequest()
+	req.Country = "CA"
+	req.PostalCode = "62701"
+
+	_, err := as.CreateAddress(aValidClientId, req)
+
+	assert.EqualError(t, err, "invalid postal code for CA: 62701")
+}
+
+func Test_GivenAnInvalidCountry_ThenUnableToCreate(t *testing.T) {
+	as, _ := getMockedAddressService([]models.Address{})
+	req := getValidAddressRequest()
+	req.Country = "USA"
+
+	_, err := as.CreateAddress(aValidClientId, req)
+
+	assert.EqualError(t, err, "country must be an ISO 3166-1 alpha-2 code")
+}
+
+func Test_GivenACountryWithoutRules_ThenOnlyTheBaseFieldsAreRequired(t *testing.T) {
+	as, _ := getMockedAddressService([]models.Address{})
+	req := request.AddressRequest{Recipient: "Ada Lovelace", Line1: "Main St 1", City: "Montevideo", Country: "UY"}
+
+	resp, err := as.CreateAddress(aValidClientId, req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "UY", resp.Country)
+}
+
+func Test_GivenAnAddressOfAnotherClient_ThenUnableToDelete(t *testing.T) {
+	addressMockRepository := &AddressRepositoryMock{}
+	addressMockRepository.On("FindAddress", aValidClientId, aValidAddressId).
+		Return((*models.Address)(nil), errors.New("address with id: 5 not found"))
+
+	as := services.AddressServiceImpl{AddressRepository: addressMockRepository}
+
+	err := as.DeleteAddress(aValidClientId, aValidAddressId)
+
+	assert.EqualError(t, err, "address with id: 5 not found")
+	addressMockRepository.AssertNotCalled(t, "DeleteAddress", mock.Anything)
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+const (
+	aValidApiKeyId = 8
+	aBootstrapKey  = "a-bootstrap-key-that-is-long-enough"
+)
+
+func Test_GivenValidScopes_ThenCreateApiKey(t *testing.T) {
+	apiKeyMockRepository := &ApiKeyRepositoryMock{}
+	apiKeyMockRepository.On("CreateApiKey", mock.Anything).Return(nil)
+
+	as := services.ApiKeyServiceImpl{ApiKeyRepository: apiKeyMockRepository}
+
+	resp, err := as.CreateApiKey(request.ApiKeyRequest{Name: "warehouse", Scopes: []string{"inventory:write", "orders:read", "inventory:write"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"inventory:write", "orders:read"}, resp.Scopes)
+	stored := apiKeyMockRepository.Calls[0].Arguments.Get(0).(*models.ApiKey)
+	assert.Equal(t, auth.HashApiKey(resp.Key), stored.KeyHash)
+	assert.NotEqual(t, resp.Key, stored.KeyHash)
+}
+
+func Test_GivenAnUnknownScope_ThenUnableToCreateApiKey(t *testing.T) {
+	as := services.ApiKeyServiceImpl{ApiKeyRepository: &ApiKeyRepositoryMock{}}
+
+	_, err := as.CreateApiKey(request.ApiKeyRequest{Name: "warehouse", Scopes: []string{"catalog:delete"}})
+
+	assert.EqualError(t, err, "unsupported scope: catalog:delete")
+}
+
+func Test_GivenAnActiveApiKey_ThenAuthenticate(t *testing.T) {
+	apiKeyMockRepository := &ApiKeyRepositoryMock{}
+
+	key, prefix, hash := auth.NewApiKey()
+	apiKey := models.ApiKey{Id: aValidApiKeyId, Prefix: prefix, KeyHash: hash, Scopes: "orders:read"}
+	apiKeyMockRepository.On("FindApiKeyByPrefix", prefix).Return(&apiKey, nil)
+	apiKeyMockRepository.On("TouchApiKey", aValidApiKeyId, mock.Anything).Return(nil)
+
+	as := services.ApiKeyServiceImpl{ApiKeyRepository: apiKeyMockRepository}
+
+	resp, err := as.Authenticate(key)
+
+	assert.Nil(t, err)
+	assert.True(t, resp.HasScope(models.ScopeOrdersRead))
+	apiKeyMockRepository.AssertExpectations(t)
+}
+
+func Test_GivenARevokedApiKey_ThenUnableToAuthenticate(t *testing.T) {
+	apiKeyMockRepository := &ApiKeyRepositoryMock{}
+
+	key, prefix, hash := auth.NewApiKey()
+	revokedAt := time.Now()
+	apiKey := models.ApiKey{Id: aValidApiKeyId, Prefix: prefix, KeyHash: hash, Scopes: "orders:read", RevokedAt: &revokedAt}
+	apiKeyMockRepository.On("FindApiKeyByPrefix", prefix).Return(&apiKey, nil)
+
+	as := services.ApiKeyServiceImpl{ApiKeyRepository: apiKeyMockRepository}
+
+	_, err := as.Authenticate(key)
+
+	assert.Equal(t, auth.ErrInvalidApiKey, err)
+}
+
+func Test_GivenAKeyWithAKnownPrefixButWrongSecret_ThenUnableToAuthenticate(t *testing.T) {
+	apiKeyMockRepository := &ApiKeyRepositoryMock{}
+
+	_, prefix, hash := auth.NewApiKey()
+	apiKey := models.ApiKey{Id: aValidApiKeyId, Prefix: prefix, KeyHash: hash, Scopes: "orders:read"}
+	apiKeyMockRepository.On("FindApiKeyByPrefix", prefix).Return(&apiKey, nil)
+
+	as := services.ApiKeyServiceImpl{ApiKeyRepository: apiKeyMockRepository}
+
+	_, err := as.Authenticate("osk_" + prefix + "_guessed")
+
+	assert.Equal(t, auth.ErrInvalidApiKey, err)
+}
+
+func Test_GivenTheBootstrapKey_ThenAuthenticateWithEveryScope(t *testing.T) {
+	as := services.ApiKeyServiceImpl{ApiKeyRepository: &ApiKeyRepositoryMock{}, BootstrapKey: aBootstrapKey}
+
+	resp, err := as.Authenticate(aBootstrapKey)
+
+	assert.Nil(t, err)
+	assert.True(t, resp.HasScope(models.ScopeKeysManage))
+	assert.True(t, resp.HasScope(models.ScopeCatalogWrite))
+	assert.Equal(t, "bootstrap", resp.Prefix)
+}
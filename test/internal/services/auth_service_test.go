@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+const (
+	aValidRefreshTokenId = 6
+	aValidPassword       = "correct horse"
+)
+
+func getMockedAuthService(clientMockRepository *ClientRepositoryMock, tokenMockRepository *TokenRepositoryMock) *services.AuthServiceImpl {
+	tokens, _ := auth.NewTokenIssuer([]byte("0123456789abcdef0123456789abcdef"), services.AccessTokenLifetime)
+	return &services.AuthServiceImpl{ClientRepository: clientMockRepository, TokenRepository: tokenMockRepository, Tokens: tokens}
+}
+
+func Test_GivenValidCredentials_ThenLogin(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	tokenMockRepository := &TokenRepositoryMock{}
+
+	client := getValidDbClient()
+	client.PasswordHash, _ = auth.HashPassword(aValidPassword)
+	clientMockRepository.On("FindClientByEmail", "ada@example.com").Return(&client, nil)
+	tokenMockRepository.On("CreateRefreshToken", mock.Anything).Return(nil)
+
+	as := getMockedAuthService(clientMockRepository, tokenMockRepository)
+
+	resp, err := as.Login(request.LoginRequest{Email: " ADA@example.com", Password: aValidPassword})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, int(services.AccessTokenLifetime.Seconds()), resp.ExpiresIn)
+	clientId, _, _ := as.Tokens.ParseAccessToken(resp.AccessToken)
+	assert.Equal(t, aValidClientId, clientId)
+	stored := tokenMockRepository.Calls[0].Arguments.Get(0).(*models.RefreshToken)
+	assert.Equal(t, auth.HashRefreshToken(resp.RefreshToken), stored.TokenHash)
+}
+
+func Test_GivenAWrongPassword_ThenUnableToLogin(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	tokenMockRepository := &TokenRepositoryMock{}
+
+	client := getValidDbClient()
+	client.PasswordHash, _ = auth.HashPassword(aValidPassword)
+	clientMockRepository.On("FindClientByEmail", "ada@example.com").Return(&client, nil)
+
+	as := getMockedAuthService(clientMockRepository, tokenMockRepository)
+
+	_, err := as.Login(request.LoginRequest{Email: "ada@example.com", Password: "wrong horse"})
+
+	assert.Equal(t, auth.ErrInvalidCredentials, err)
+	tokenMockRepository.AssertNotCalled(t, "CreateRefreshToken", mock.Anything)
+}
+
+func Test_GivenAnUnknownEmail_ThenUnableToLogin(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+
+	clientMockRepository.On("FindClientByEmail", "nobody@example.com").Return((*models.Client)(nil), nil)
+
+	as := getMockedAuthService(clientMockRepository, &TokenRepositoryMock{})
+
+	_, err := as.Login(request.LoginRequest{Email: "nobody@example.com", Password: aValidPassword})
+
+	assert.Equal(t, auth.ErrInvalidCredentials, err)
+}
+
+func Test_GivenAnActiveRefreshToken_ThenRotateIt(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	tokenMockRepository := &TokenRepositoryMock{}
+
+	token := models.RefreshToken{Id: aValidRefreshTokenId, ClientId: aValidClientId, ExpiresAt: time.Now().Add(time.Hour)}
+	tokenMockRepository.On("FindRefreshToken", auth.HashRefreshToken("a-refresh-token")).Return(&token, nil)
+	tokenMockRepository.On("RevokeRefreshToken", aValidRefreshTokenId).Return(nil)
+	tokenMockRepository.On("CreateRefreshToken", mock.Anything).Return(nil)
+	client := getValidDbClient()
+	client.Role = models.RoleSupport
+	clientMockRepository.On("FindClientById", aValidClientId).Return(&client, nil)
+
+	as := getMockedAuthService(clientMockRepository, tokenMockRepository)
+
+	resp, err := as.Refresh(request.RefreshTokenRequest{RefreshToken: "a-refresh-token"})
+
+	assert.Nil(t, err)
+	assert.NotEqual(t, "a-refresh-token", resp.RefreshToken)
+	_, role, _ := as.Tokens.ParseAccessToken(resp.AccessToken)
+	assert.Equal(t, models.RoleSupport, role)
+	tokenMockRepository.AssertCalled(t, "RevokeRefreshToken", aValidRefreshTokenId)
+}
+
+func Test_GivenARevokedRefreshToken_ThenUnableToRefresh(t *testing.T) {
+	tokenMockRepository := &TokenRepositoryMock{}
+
+	revokedAt := time.Now().Add(-time.Minute)
+	token := models.RefreshToken{Id: aValidRefreshTokenId, ClientId: aValidClientId, ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}
+	tokenMockRepository.On("FindRefreshToken", auth.HashRefreshToken("a-refresh-token")).Return(&token, nil)
+
+	as := getMockedAuthService(&ClientRepositoryMock{}, tokenMockRepository)
+
+	_, err := as.Refresh(request.RefreshTokenRequest{RefreshToken: "a-refresh-token"})
+
+	assert.Equal(t, auth.ErrInvalidToken, err)
+	tokenMockRepository.AssertNotCalled(t, "CreateRefreshToken", mock.Anything)
+}
+
+func Test_GivenARefreshTokenOfADeletedClient_ThenUnableToRefresh(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	tokenMockRepository := &TokenRepositoryMock{}
+
+	token := models.RefreshToken{Id: aValidRefreshTokenId, ClientId: aValidClientId, ExpiresAt: time.Now().Add(time.Hour)}
+	tokenMockRepository.On("FindRefreshToken", auth.HashRefreshToken("a-refresh-token")).Return(&token, nil)
+	clientMockRepository.On("FindClientById", aValidClientId).Return((*models.Client)(nil), errors.New("client with id: 2 not found"))
+
+	as := getMockedAuthService(clientMockRepository, tokenMockRepository)
+
+	_, err := as.Refresh(request.RefreshTokenRequest{RefreshToken: "a-refresh-token"})
+
+	assert.Equal(t, auth.ErrInvalidToken, err)
+}
+
+func Test_GivenAClient_ThenIssueAPasswordReset(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	tokenMockRepository := &TokenRepositoryMock{}
+
+	client := getValidDbClient()
+	clientMockRepository.On("FindClientById", aValidClientId).Return(&client, nil)
+	tokenMockRepository.On("CreatePasswordResetToken", mock.Anything).Return(nil)
+
+	as := getMockedAuthService(clientMockRepository, tokenMockRepository)
+
+	resp, err := as.IssuePasswordReset(aValidClientId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, aValidClientId, resp.ClientId)
+	stored := tokenMockRepository.Calls[0].Arguments.Get(0).(*models.PasswordResetToken)
+	assert.Equal(t, auth.HashPasswordResetToken(resp.ResetToken), stored.TokenHash)
+	assert.Equal(t, resp.ExpiresAt, stored.ExpiresAt)
+}
+
+func Test_GivenAnActiveResetToken_ThenSetThePasswordAndSignOut(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	tokenMockRepository := &TokenRepositoryMock{}
+
+	client := getValidDbClient()
+	token := models.PasswordResetToken{Id: 3, ClientId: aValidClientId, ExpiresAt: time.Now().Add(time.Hour)}
+	tokenMockRepository.On("FindPasswordResetToken", auth.HashPasswordResetToken("a-reset-token")).Return(&token, nil)
+	clientMockRepository.On("FindClientById", aValidClientId).Return(&client, nil)
+	tokenMockRepository.On("UsePasswordResetToken", 3).Return(nil)
+	clientMockRepository.On("UpdateClient", mock.Anything).Return(nil)
+	tokenMockRepository.On("RevokeClientRefreshTokens", aValidClientId).Return(nil)
+
+	as := getMockedAuthService(clientMockRepository, tokenMockRepository)
+
+	err := as.ResetPassword(request.ResetPasswordRequest{ResetToken: "a-reset-token", Password: aValidPassword})
+
+	assert.Nil(t, err)
+	updated := clientMockRepository.Calls[1].Arguments.Get(0).(*models.Client)
+	assert.True(t, auth.CheckPassword(updated.PasswordHash, aValidPassword))
+	tokenMockRepository.AssertCalled(t, "RevokeClientRefreshTokens", aValidClientId)
+}
+
+func Test_GivenAnExpiredResetToken_ThenUnableToResetPassword(t *testing.T) {
+	tokenMockRepository := &TokenRepositoryMock{}
+
+	token := models.PasswordResetToken{Id: 3, ClientId: aValidClientId, ExpiresAt: time.Now().Add(-time.Minute)}
+	tokenMockRepository.On("FindPasswordResetToken", auth.HashPasswordResetToken("a-reset-token")).Return(&token, nil)
+
+	as := getMockedAuthService(&ClientRepositoryMock{}, tokenMockRepository)
+
+	err := as.ResetPassword(request.ResetPasswordRequest{ResetToken: "a-reset-token", Password: aValidPassword})
+
+	assert.Equal(t, auth.ErrInvalidToken, err)
+	tokenMockRepository.AssertNotCalled(t, "UsePasswordResetToken", mock.Anything)
+}
+
+func Test_GivenAShortPassword_ThenUnableToResetPassword(t *testing.T) {
+	tokenMockRepository := &TokenRepositoryMock{}
+
+	as := getMockedAuthService(&ClientRepositoryMock{}, tokenMockRepository)
+
+	err := as.ResetPassword(request.ResetPasswordRequest{ResetToken: "a-reset-token", Password: "short"})
+
+	assert.NotNil(t, err)
+	tokenMockRepository.AssertNotCalled(t, "FindPasswordResetToken", mock.Anything)
+}
@@ -14,6 +14,7 @@ func Test_GivenAValidClientId_AValidProductIt_ThenAddProductToCart(t *testing.T)
 	clientMockRepository := &ClientRepositoryMock{}
 	productMockRepository := &ProductRepositoryMock{}
 	cartMockRepository := &CartRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
 
 	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
 
@@ -21,11 +22,16 @@ func Test_GivenAValidClientId_AValidProductIt_ThenAddProductToCart(t *testing.T)
 	productMockRepository.On("FindProductById", aValidProductId).Return(&productMocked, nil)
 
 	cartMockRepository.On("AddProductToCart", mock.Anything, mock.Anything).Return(nil)
+	stockMockRepository.On("GetStock", aValidProductId).Return(&models.Stock{ProductId: aValidProductId, OnHand: 10}, nil)
+	cart := getMockedValidCart()
+	cartMockRepository.On("GetCartByClient", aValidClientId).Return(&cart, nil)
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(getValidListOfCartItems(), nil)
 
 	cs := services.CartServiceImpl{
 		CartRepository:    cartMockRepository,
 		ClientRepository:  clientMockRepository,
 		ProductRepository: productMockRepository,
+		StockRepository:   stockMockRepository,
 	}
 
 	err := cs.AddProductToCart(aValidProductId, aValidClientId)
@@ -90,6 +96,7 @@ func Test_GivenAValidClientId_AValidProductId_ThenUnableToAddProductToTheCart(t
 	clientMockRepository := &ClientRepositoryMock{}
 	productMockRepository := &ProductRepositoryMock{}
 	cartMockRepository := &CartRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
 
 	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
 
@@ -97,11 +104,16 @@ func Test_GivenAValidClientId_AValidProductId_ThenUnableToAddProductToTheCart(t
 	productMockRepository.On("FindProductById", aValidProductId).Return(&productMocked, nil)
 
 	cartMockRepository.On("AddProductToCart", aValidProductId, aValidClientId).Return(errors.New("could not add product"))
+	stockMockRepository.On("GetStock", aValidProductId).Return(&models.Stock{ProductId: aValidProductId, OnHand: 10}, nil)
+	cart := getMockedValidCart()
+	cartMockRepository.On("GetCartByClient", aValidClientId).Return(&cart, nil)
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(getValidListOfCartItems(), nil)
 
 	cs := services.CartServiceImpl{
 		CartRepository:    cartMockRepository,
 		ClientRepository:  clientMockRepository,
 		ProductRepository: productMockRepository,
+		StockRepository:   stockMockRepository,
 	}
 
 	err := cs.AddProductToCart(aValidProductId, aValidClientId)
@@ -125,7 +137,7 @@ func Test_GivenAValidClientId_ThenReturnCartWithElements(t *testing.T) {
 	validCart := getMockedValidCart()
 	cartMockRepository.On("GetCartByClient", aValidClientId).Return(&validCart, nil)
 
-	productMockRepository.On("FindProductsFromCart", aValidCartId).Return(getValidListOfProducts(), nil)
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(getValidListOfCartItems(), nil)
 
 	cs := services.CartServiceImpl{
 		CartRepository:    cartMockRepository,
@@ -137,10 +149,13 @@ func Test_GivenAValidClientId_ThenReturnCartWithElements(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, resp)
 	assert.Equal(t, 1, len(resp.Products))
-	assert.Equal(t, getValidResponseProduct(), resp.Products[0])
+	assert.Equal(t, getValidResponseProduct(), resp.Products[0].ProductResponse)
+	assert.Equal(t, 1, resp.Products[0].Quantity)
+	assert.Equal(t, getValidResponseProduct().Price, resp.Products[0].Subtotal)
+	assert.Equal(t, getValidResponseProduct().Price, resp.Subtotal)
 	clientMockRepository.AssertNumberOfCalls(t, "IsClientInDataBase", 1)
 	cartMockRepository.AssertNumberOfCalls(t, "GetCartByClient", 1)
-	productMockRepository.AssertNumberOfCalls(t, "FindProductsFromCart", 1)
+	cartMockRepository.AssertNumberOfCalls(t, "GetCartItems", 1)
 }
 
 func Test_GivenANotValidClientId_ThenUnableToGetCart(t *testing.T) {
@@ -153,7 +168,7 @@ func Test_GivenANotValidClientId_ThenUnableToGetCart(t *testing.T) {
 	validCart := getMockedValidCart()
 	cartMockRepository.On("GetCartByClient", anInvalidClientId).Return(&validCart, nil)
 
-	productMockRepository.On("FindProductsFromCart", aValidCartId).Return(getValidListOfProducts(), nil)
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(getValidListOfCartItems(), nil)
 
 	cs := services.CartServiceImpl{
 		CartRepository:    cartMockRepository,
@@ -167,7 +182,7 @@ func Test_GivenANotValidClientId_ThenUnableToGetCart(t *testing.T) {
 	assert.Equal(t, 0, len(resp.Products))
 	clientMockRepository.AssertNumberOfCalls(t, "IsClientInDataBase", 1)
 	cartMockRepository.AssertNumberOfCalls(t, "GetCartByClient", 0)
-	productMockRepository.AssertNumberOfCalls(t, "FindProductsFromCart", 0)
+	cartMockRepository.AssertNumberOfCalls(t, "GetCartItems", 0)
 }
 
 func Test_GivenAValidClientId_ThenCartNotFound(t *testing.T) {
@@ -180,7 +195,7 @@ func Test_GivenAValidClientId_ThenCartNotFound(t *testing.T) {
 	cart := models.Cart{}
 	cartMockRepository.On("GetCartByClient", aValidClientId).Return(&cart, errors.New("cart not found"))
 
-	productMockRepository.On("FindProductsFromCart", aValidCartId).Return(getValidListOfProducts(), nil)
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(getValidListOfCartItems(), nil)
 
 	cs := services.CartServiceImpl{
 		CartRepository:    cartMockRepository,
@@ -194,7 +209,7 @@ func Test_GivenAValidClientId_ThenCartNotFound(t *testing.T) {
 	assert.Equal(t, 0, len(resp.Products))
 	clientMockRepository.AssertNumberOfCalls(t, "IsClientInDataBase", 1)
 	cartMockRepository.AssertNumberOfCalls(t, "GetCartByClient", 1)
-	productMockRepository.AssertNumberOfCalls(t, "FindProductsFromCart", 0)
+	cartMockRepository.AssertNumberOfCalls(t, "GetCartItems", 0)
 }
 
 func Test_GivenAValidClientId_CartFound_ThenListOfProductsNotFound(t *testing.T) {
@@ -207,8 +222,8 @@ func Test_GivenAValidClientId_CartFound_ThenListOfProductsNotFound(t *testing.T)
 	cart := getMockedValidCart()
 	cartMockRepository.On("GetCartByClient", aValidClientId).Return(&cart, nil)
 
-	var emptyProducts []models.Product
-	productMockRepository.On("FindProductsFromCart", aValidCartId).Return(&emptyProducts, errors.New("products not found"))
+	var emptyItems []models.ProductCart
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(&emptyItems, errors.New("products not found"))
 
 	cs := services.CartServiceImpl{
 		CartRepository:    cartMockRepository,
@@ -222,5 +237,356 @@ func Test_GivenAValidClientId_CartFound_ThenListOfProductsNotFound(t *testing.T)
 	assert.Equal(t, 0, len(resp.Products))
 	clientMockRepository.AssertNumberOfCalls(t, "IsClientInDataBase", 1)
 	cartMockRepository.AssertNumberOfCalls(t, "GetCartByClient", 1)
-	productMockRepository.AssertNumberOfCalls(t, "FindProductsFromCart", 1)
+	cartMockRepository.AssertNumberOfCalls(t, "GetCartItems", 1)
+}
+
+func Test_GivenAValidClientId_CartWithMixedCurrencies_ThenUnableToComputeSubtotal(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	productMockRepository := &ProductRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+
+	cart := getMockedValidCart()
+	cartMockRepository.On("GetCartByClient", aValidClientId).Return(&cart, nil)
+
+	euroProduct := getValidDbProduct()
+	euroProduct.Id = aValidProductId + 1
+	euroProduct.Price = models.Money{Amount: 100, Currency: "EUR"}
+	items := append(*getValidListOfCartItems(), models.ProductCart{ProductId: euroProduct.Id, Product: euroProduct, Quantity: 1})
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(&items, nil)
+
+	cs := services.CartServiceImpl{
+		CartRepository:    cartMockRepository,
+		ClientRepository:  clientMockRepository,
+		ProductRepository: productMockRepository,
+	}
+	_, err := cs.GetCart(aValidClientId)
+
+	assert.EqualError(t, err, "the cart contains products priced in different currencies")
+}
+
+func Test_GivenRepeatedCartRows_ThenReturnAggregatedQuantities(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	productMockRepository := &ProductRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+
+	cart := getMockedValidCart()
+	cartMockRepository.On("GetCartByClient", aValidClientId).Return(&cart, nil)
+
+	items := []models.ProductCart{
+		{ProductId: aValidProductId, Product: getValidDbProduct(), Quantity: 2},
+		{ProductId: aValidProductId, Product: getValidDbProduct(), Quantity: 1},
+	}
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(&items, nil)
+
+	cs := services.CartServiceImpl{
+		CartRepository:    cartMockRepository,
+		ClientRepository:  clientMockRepository,
+		ProductRepository: productMockRepository,
+	}
+	resp, err := cs.GetCart(aValidClientId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resp.Products))
+	assert.Equal(t, 3, resp.Products[0].Quantity)
+	assert.Equal(t, int64(13650), resp.Products[0].Subtotal.Amount)
+	assert.Equal(t, int64(13650), resp.Subtotal.Amount)
+}
+
+func Test_GivenAValidClientId_AValidProductId_ThenSetProductQuantity(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	productMockRepository := &ProductRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+
+	productMocked := getValidDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&productMocked, nil)
+
+	cartMockRepository.On("SetProductQuantity", aValidProductId, aValidClientId, 5).Return(nil)
+	stockMockRepository.On("GetStock", aValidProductId).Return(&models.Stock{ProductId: aValidProductId, OnHand: 10}, nil)
+
+	cs := services.CartServiceImpl{
+		CartRepository:    cartMockRepository,
+		ClientRepository:  clientMockRepository,
+		ProductRepository: productMockRepository,
+		StockRepository:   stockMockRepository,
+	}
+
+	err := cs.SetProductQuantity(aValidProductId, aValidClientId, 5)
+
+	assert.Nil(t, err)
+	cartMockRepository.AssertCalled(t, "SetProductQuantity", aValidProductId, aValidClientId, 5)
+}
+
+func Test_GivenAnOutOfRangeQuantity_ThenUnableToSetProductQuantity(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	productMockRepository := &ProductRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+
+	cs := services.CartServiceImpl{
+		CartRepository:    cartMockRepository,
+		ClientRepository:  clientMockRepository,
+		ProductRepository: productMockRepository,
+	}
+
+	err := cs.SetProductQuantity(aValidProductId, aValidClientId, 0)
+
+	assert.EqualError(t, err, fmt.Sprintf("quantity must be between 1 and %v", services.MaxProductQuantity))
+	clientMockRepository.AssertNotCalled(t, "IsClientInDataBase")
+	cartMockRepository.AssertNotCalled(t, "SetProductQuantity")
+}
+
+func Test_GivenAValidClientId_AValidProductId_ThenUnableToSetProductQuantity(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	productMockRepository := &ProductRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+
+	productMocked := getValidDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&productMocked, nil)
+
+	cartMockRepository.On("SetProductQuantity", aValidProductId, aValidClientId, 2).Return(errors.New("could not save"))
+	stockMockRepository.On("GetStock", aValidProductId).Return(&models.Stock{ProductId: aValidProductId, OnHand: 10}, nil)
+
+	cs := services.CartServiceImpl{
+		CartRepository:    cartMockRepository,
+		ClientRepository:  clientMockRepository,
+		ProductRepository: productMockRepository,
+		StockRepository:   stockMockRepository,
+	}
+
+	err := cs.SetProductQuantity(aValidProductId, aValidClientId, 2)
+
+	assert.EqualError(t, err, "unable to update the product quantity in the cart")
+}
+
+func Test_GivenAValidClientId_AValidProductId_ThenRemoveProductFromCart(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+	cartMockRepository.On("RemoveProductFromCart", aValidProductId, aValidClientId).Return(nil)
+
+	cs := services.CartServiceImpl{
+		CartRepository:   cartMockRepository,
+		ClientRepository: clientMockRepository,
+	}
+
+	err := cs.RemoveProductFromCart(aValidProductId, aValidClientId)
+
+	assert.Nil(t, err)
+	cartMockRepository.AssertNumberOfCalls(t, "RemoveProductFromCart", 1)
+}
+
+func Test_GivenAnInvalidClientId_ThenUnableToRemoveProductFromCart(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", anInvalidClientId).Return(false)
+
+	cs := services.CartServiceImpl{
+		CartRepository:   cartMockRepository,
+		ClientRepository: clientMockRepository,
+	}
+
+	err := cs.RemoveProductFromCart(aValidProductId, anInvalidClientId)
+
+	assert.EqualError(t, err, fmt.Sprintf("client with id: %v not found", anInvalidClientId))
+	cartMockRepository.AssertNotCalled(t, "RemoveProductFromCart")
+}
+
+func Test_GivenAValidClientId_ProductNotInCart_ThenUnableToRemoveProduct(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+	cartMockRepository.On("RemoveProductFromCart", aValidProductId, aValidClientId).Return(errors.New("product with id: 1 is not in the cart"))
+
+	cs := services.CartServiceImpl{
+		CartRepository:   cartMockRepository,
+		ClientRepository: clientMockRepository,
+	}
+
+	err := cs.RemoveProductFromCart(aValidProductId, aValidClientId)
+
+	assert.EqualError(t, err, "product with id: 1 is not in the cart")
+}
+
+func Test_GivenAValidClientId_ThenClearCart(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+	cartMockRepository.On("ClearCart", aValidClientId).Return(nil)
+
+	cs := services.CartServiceImpl{
+		CartRepository:   cartMockRepository,
+		ClientRepository: clientMockRepository,
+	}
+
+	err := cs.ClearCart(aValidClientId)
+
+	assert.Nil(t, err)
+	cartMockRepository.AssertNumberOfCalls(t, "ClearCart", 1)
+}
+
+func Test_GivenAnInvalidClientId_ThenUnableToClearCart(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", anInvalidClientId).Return(false)
+
+	cs := services.CartServiceImpl{
+		CartRepository:   cartMockRepository,
+		ClientRepository: clientMockRepository,
+	}
+
+	err := cs.ClearCart(anInvalidClientId)
+
+	assert.EqualError(t, err, fmt.Sprintf("client with id: %v not found", anInvalidClientId))
+	cartMockRepository.AssertNotCalled(t, "ClearCart")
+}
+
+func Test_GivenAnArchivedProduct_ThenUnableToAddProductToCart(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	productMockRepository := &ProductRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+
+	productMocked := getArchivedDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&productMocked, nil)
+
+	cs := services.CartServiceImpl{
+		CartRepository:    cartMockRepository,
+		ClientRepository:  clientMockRepository,
+		ProductRepository: productMockRepository,
+	}
+
+	err := cs.AddProductToCart(aValidProductId, aValidClientId)
+
+	assert.EqualError(t, err, fmt.Sprintf("the product: %v is no longer available", aValidProductId))
+	cartMockRepository.AssertNotCalled(t, "AddProductToCart")
+}
+
+func Test_GivenAProductWithoutEnoughStock_ThenUnableToAddProductToCart(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	productMockRepository := &ProductRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+
+	productMocked := getValidDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&productMocked, nil)
+
+	cart := getMockedValidCart()
+	cartMockRepository.On("GetCartByClient", aValidClientId).Return(&cart, nil)
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(getValidListOfCartItems(), nil)
+	stockMockRepository.On("GetStock", aValidProductId).Return(&models.Stock{ProductId: aValidProductId, OnHand: 3, Reserved: 2}, nil)
+
+	cs := services.CartServiceImpl{
+		CartRepository:    cartMockRepository,
+		ClientRepository:  clientMockRepository,
+		ProductRepository: productMockRepository,
+		StockRepository:   stockMockRepository,
+	}
+
+	err := cs.AddProductToCart(aValidProductId, aValidClientId)
+
+	assert.True(t, errors.Is(err, models.ErrInsufficientStock))
+	assert.EqualError(t, err, fmt.Sprintf("insufficient stock: the product: %v has 1 units available", aValidProductId))
+	cartMockRepository.AssertNotCalled(t, "AddProductToCart")
+}
+
+func Test_GivenAQuantityAboveTheStock_ThenUnableToSetProductQuantity(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	productMockRepository := &ProductRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+
+	productMocked := getValidDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&productMocked, nil)
+
+	stockMockRepository.On("GetStock", aValidProductId).Return(&models.Stock{ProductId: aValidProductId, OnHand: 4}, nil)
+
+	cs := services.CartServiceImpl{
+		CartRepository:    cartMockRepository,
+		ClientRepository:  clientMockRepository,
+		ProductRepository: productMockRepository,
+		StockRepository:   stockMockRepository,
+	}
+
+	err := cs.SetProductQuantity(aValidProductId, aValidClientId, 5)
+
+	assert.True(t, errors.Is(err, models.ErrInsufficientStock))
+	cartMockRepository.AssertNotCalled(t, "SetProductQuantity")
+}
+
+func Test_GivenADigitalProduct_ThenAddProductToCartWithoutCheckingStock(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	productMockRepository := &ProductRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+
+	productMocked := getValidDbProduct()
+	productMocked.Type = models.ProductTypeDigital
+	productMockRepository.On("FindProductById", aValidProductId).Return(&productMocked, nil)
+
+	cart := getMockedValidCart()
+	cartMockRepository.On("GetCartByClient", aValidClientId).Return(&cart, nil)
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(getValidListOfCartItems(), nil)
+	cartMockRepository.On("AddProductToCart", aValidProductId, aValidClientId).Return(nil)
+
+	cs := services.CartServiceImpl{
+		CartRepository:    cartMockRepository,
+		ClientRepository:  clientMockRepository,
+		ProductRepository: productMockRepository,
+		StockRepository:   stockMockRepository,
+	}
+
+	err := cs.AddProductToCart(aValidProductId, aValidClientId)
+
+	assert.Nil(t, err)
+	stockMockRepository.AssertNotCalled(t, "GetStock")
+}
+
+func Test_GivenACartLineAtTheLimit_ThenUnableToAddProductToCart(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+	productMockRepository := &ProductRepositoryMock{}
+	cartMockRepository := &CartRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
+
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+
+	productMocked := getValidDbProduct()
+	productMocked.Type = models.ProductTypeDigital
+	productMockRepository.On("FindProductById", aValidProductId).Return(&productMocked, nil)
+
+	cart := getMockedValidCart()
+	cartMockRepository.On("GetCartByClient", aValidClientId).Return(&cart, nil)
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(getValidListOfCartItems(), nil)
+	cartMockRepository.On("AddProductToCart", aValidProductId, aValidClientId).Return(models.NewCartQuantityLimitError(aValidProductId))
+
+	cs := services.CartServiceImpl{
+		CartRepository:    cartMockRepository,
+		ClientRepository:  clientMockRepository,
+		ProductRepository: productMockRepository,
+		StockRepository:   stockMockRepository,
+	}
+
+	err := cs.AddProductToCart(aValidProductId, aValidClientId)
+
+	assert.True(t, errors.Is(err, models.ErrCartQuantityLimit))
 }
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_GivenAFilter_ThenListProductsWithPagination(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+
+	productType := models.ProductTypePhysical
+	expectedFilter := models.ProductFilter{CategoryIds: []int{1}, Type: &productType, Label: "key", Sort: models.ProductSortByPriceDesc, Page: 1, PageSize: services.DefaultPageSize}
+	products := []models.Product{getValidDbProduct()}
+	productMockRepository.On("ListProducts", expectedFilter).Return(&products, int64(1), nil)
+
+	cs := services.CatalogServiceImpl{ProductRepository: productMockRepository}
+
+	resp, err := cs.ListProducts(models.ProductFilter{CategoryIds: []int{1}, Type: &productType, Label: "key", Sort: models.ProductSortByPriceDesc})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resp.Products))
+	assert.Equal(t, getValidResponseProduct(), resp.Products[0])
+	assert.Equal(t, int64(1), resp.Pagination.TotalItems)
+	assert.Equal(t, 1, resp.Pagination.TotalPages)
+}
+
+func Test_GivenNoSort_ThenListProductsSortedById(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+
+	var products []models.Product
+	expectedFilter := models.ProductFilter{Sort: models.ProductSortById, Page: 1, PageSize: services.DefaultPageSize}
+	productMockRepository.On("ListProducts", expectedFilter).Return(&products, int64(0), nil)
+
+	cs := services.CatalogServiceImpl{ProductRepository: productMockRepository}
+
+	resp, err := cs.ListProducts(models.ProductFilter{})
+
+	assert.Nil(t, err)
+	assert.NotNil(t, resp.Products)
+	productMockRepository.AssertCalled(t, "ListProducts", expectedFilter)
+}
+
+func Test_GivenAnUnsupportedSort_ThenUnableToListProducts(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+
+	cs := services.CatalogServiceImpl{ProductRepository: productMockRepository}
+
+	_, err := cs.ListProducts(models.ProductFilter{Sort: "weight; drop table products"})
+
+	assert.EqualError(t, err, "unsupported sort: weight; drop table products")
+	productMockRepository.AssertNotCalled(t, "ListProducts")
+}
+
+func Test_GivenAValidProductId_ThenGetProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+
+	product := getValidDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&product, nil)
+
+	cs := services.CatalogServiceImpl{ProductRepository: productMockRepository}
+
+	resp, err := cs.GetProduct(aValidProductId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, getValidResponseProduct(), resp)
+}
+
+func Test_GivenADigitalProduct_ThenGetProductWithoutDownloadUrl(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+
+	product := getDigitalDbProduct()
+	productMockRepository.On("FindProductById", product.Id).Return(&product, nil)
+
+	cs := services.CatalogServiceImpl{ProductRepository: productMockRepository}
+
+	resp, err := cs.GetProduct(product.Id)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "digital", resp.Type)
+	assert.Nil(t, resp.Download)
+}
+
+func Test_GivenAnInvalidProductId_ThenProductNotFound(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+
+	productMockRepository.On("FindProductById", anInvalidProductId).Return(&models.Product{}, errors.New("unable to find the product in our db"))
+
+	cs := services.CatalogServiceImpl{ProductRepository: productMockRepository}
+
+	_, err := cs.GetProduct(anInvalidProductId)
+
+	assert.EqualError(t, err, fmt.Sprintf("product with id: %v not found", anInvalidProductId))
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func Test_ListCategoryTree_ThenReturnNestedCategories(t *testing.T) {
+	categoryMockRepository := &CategoryRepositoryMock{}
+	categoryMockRepository.On("ListCategories").Return(getMockedCategoryTree(), nil)
+
+	cs := services.CategoryServiceImpl{CategoryRepository: categoryMockRepository}
+
+	resp, err := cs.ListCategoryTree()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(resp))
+	assert.Equal(t, "Electronics", resp[0].Label)
+	assert.Equal(t, 2, len(resp[0].Children))
+	assert.Equal(t, "Laptops", resp[0].Children[0].Children[0].Label)
+	assert.Equal(t, "Books", resp[1].Label)
+}
+
+func Test_GivenACategoryWithSubcategories_ThenListProductsOfDescendants(t *testing.T) {
+	categoryMockRepository := &CategoryRepositoryMock{}
+	catalogServiceMock := &CatalogServiceMock{}
+
+	categoryMockRepository.On("ListCategories").Return(getMockedCategoryTree(), nil)
+	catalogServiceMock.On("ListProducts", mock.Anything).Return(response.ListProductsResponse{}, nil)
+
+	cs := services.CategoryServiceImpl{CategoryRepository: categoryMockRepository, CatalogService: catalogServiceMock}
+
+	_, err := cs.ListCategoryProducts(1, models.ProductFilter{Label: "pro"})
+
+	assert.Nil(t, err)
+	catalogServiceMock.AssertCalled(t, "ListProducts", models.ProductFilter{CategoryIds: []int{1, 2, 3, 4}, Label: "pro"})
+}
+
+func Test_GivenAnUnknownCategory_ThenUnableToListProducts(t *testing.T) {
+	categoryMockRepository := &CategoryRepositoryMock{}
+	catalogServiceMock := &CatalogServiceMock{}
+
+	categoryMockRepository.On("ListCategories").Return(getMockedCategoryTree(), nil)
+
+	cs := services.CategoryServiceImpl{CategoryRepository: categoryMockRepository, CatalogService: catalogServiceMock}
+
+	_, err := cs.ListCategoryProducts(99, models.ProductFilter{})
+
+	assert.EqualError(t, err, "category with id: 99 not found")
+	catalogServiceMock.AssertNotCalled(t, "ListProducts")
+}
+
+func Test_GivenAValidParent_ThenCreateSubcategory(t *testing.T) {
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	parentId := 1
+	parent := models.Category{Id: parentId, Label: "Electronics"}
+	categoryMockRepository.On("FindCategoryById", parentId).Return(&parent, nil)
+	categoryMockRepository.On("CreateCategory", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Category).Id = 6
+	})
+
+	cs := services.CategoryServiceImpl{CategoryRepository: categoryMockRepository}
+
+	resp, err := cs.CreateCategory(request.CategoryRequest{Label: " Tablets ", ParentId: &parentId})
+
+	assert.Nil(t, err)
+	assert.Equal(t, response.CategoryResponse{Id: 6, Label: "Tablets", ParentId: &parentId}, resp)
+}
+
+func Test_GivenAnEmptyLabel_ThenUnableToCreateCategory(t *testing.T) {
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	cs := services.CategoryServiceImpl{CategoryRepository: categoryMockRepository}
+
+	_, err := cs.CreateCategory(request.CategoryRequest{Label: "  "})
+
+	assert.EqualError(t, err, "category label is required")
+	categoryMockRepository.AssertNotCalled(t, "CreateCategory", mock.Anything)
+}
+
+func Test_GivenAnUnknownParent_ThenUnableToCreateCategory(t *testing.T) {
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	parentId := 42
+	categoryMockRepository.On("FindCategoryById", parentId).Return(&models.Category{}, errors.New("not found"))
+
+	cs := services.CategoryServiceImpl{CategoryRepository: categoryMockRepository}
+
+	_, err := cs.CreateCategory(request.CategoryRequest{Label: "Tablets", ParentId: &parentId})
+
+	assert.EqualError(t, err, "parent category with id: 42 not found")
+}
+
+func Test_GivenADescendantAsNewParent_ThenUnableToUpdateCategory(t *testing.T) {
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	category := models.Category{Id: 1, Label: "Electronics"}
+	categoryMockRepository.On("FindCategoryById", 1).Return(&category, nil)
+	categoryMockRepository.On("ListCategories").Return(getMockedCategoryTree(), nil)
+
+	cs := services.CategoryServiceImpl{CategoryRepository: categoryMockRepository}
+
+	laptops := 4
+	_, err := cs.UpdateCategory(1, request.CategoryRequest{Label: "Electronics", ParentId: &laptops})
+
+	assert.EqualError(t, err, "a category cannot be moved under itself or one of its subcategories")
+	categoryMockRepository.AssertNotCalled(t, "UpdateCategory", mock.Anything)
+}
+
+func Test_GivenAValidParent_ThenMoveCategory(t *testing.T) {
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	category := models.Category{Id: 3, Label: "Phones"}
+	categoryMockRepository.On("FindCategoryById", 3).Return(&category, nil)
+	categoryMockRepository.On("ListCategories").Return(getMockedCategoryTree(), nil)
+	categoryMockRepository.On("UpdateCategory", mock.Anything).Return(nil)
+
+	cs := services.CategoryServiceImpl{CategoryRepository: categoryMockRepository}
+
+	computers := 2
+	resp, err := cs.UpdateCategory(3, request.CategoryRequest{Label: "Smartphones", ParentId: &computers})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Smartphones", resp.Label)
+	assert.Equal(t, &computers, resp.ParentId)
+}
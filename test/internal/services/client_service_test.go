@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func getValidDbClient() models.Client {
+	return models.Client{Id: aValidClientId, Name: "Ada Lovelace", Email: "ada@example.com", Phone: "+1 555 0100", Role: models.RoleCustomer}
+}
+
+func Test_GivenAValidRequest_ThenSignUp(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+
+	clientMockRepository.On("FindClientByEmail", "ada@example.com").Return((*models.Client)(nil), nil)
+	clientMockRepository.On("CreateClient", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Client).Id = aValidClientId
+	})
+
+	cs := services.ClientServiceImpl{ClientRepository: clientMockRepository}
+
+	resp, err := cs.SignUp(request.SignUpRequest{ClientRequest: request.ClientRequest{Name: " Ada Lovelace ", Email: "Ada@Example.com", Phone: "+1 555 0100"}, Password: "correct horse"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, aValidClientId, resp.Id)
+	assert.Equal(t, "Ada Lovelace", resp.Name)
+	assert.Equal(t, "ada@example.com", resp.Email)
+	created := clientMockRepository.Calls[1].Arguments.Get(0).(*models.Client)
+	assert.True(t, auth.CheckPassword(created.PasswordHash, "correct horse"))
+}
+
+func Test_GivenAShortPassword_ThenUnableToSignUp(t *testing.T) {
+	cs := services.ClientServiceImpl{ClientRepository: &ClientRepositoryMock{}}
+
+	_, err := cs.SignUp(request.SignUpRequest{ClientRequest: request.ClientRequest{Name: "Ada", Email: "ada@example.com"}, Password: "short"})
+
+	assert.EqualError(t, err, "the password must have between 8 and 72 characters")
+}
+
+func Test_GivenARegisteredEmail_ThenUnableToSignUp(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+
+	client := getValidDbClient()
+	clientMockRepository.On("FindClientByEmail", "ada@example.com").Return(&client, nil)
+
+	cs := services.ClientServiceImpl{ClientRepository: clientMockRepository}
+
+	_, err := cs.SignUp(request.SignUpRequest{ClientRequest: request.ClientRequest{Name: "Ada", Email: "ada@example.com"}, Password: "correct horse"})
+
+	assert.True(t, errors.Is(err, models.ErrEmailTaken))
+	clientMockRepository.AssertNotCalled(t, "CreateClient", mock.Anything)
+}
+
+func Test_GivenAnInvalidEmail_ThenUnableToSignUp(t *testing.T) {
+	cs := services.ClientServiceImpl{ClientRepository: &ClientRepositoryMock{}}
+
+	_, err := cs.SignUp(request.SignUpRequest{ClientRequest: request.ClientRequest{Name: "Ada", Email: "Ada <ada@example.com>"}, Password: "correct horse"})
+
+	assert.EqualError(t, err, "a valid email is required")
+}
+
+func Test_GivenAnInvalidPhone_ThenUnableToSignUp(t *testing.T) {
+	cs := services.ClientServiceImpl{ClientRepository: &ClientRepositoryMock{}}
+
+	_, err := cs.SignUp(request.SignUpRequest{ClientRequest: request.ClientRequest{Name: "Ada", Email: "ada@example.com", Phone: "call me"}, Password: "correct horse"})
+
+	assert.EqualError(t, err, "invalid phone number")
+}
+
+func Test_GivenTheSameEmail_ThenUpdateProfile(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+
+	client := getValidDbClient()
+	owner := getValidDbClient()
+	clientMockRepository.On("FindClientById", aValidClientId).Return(&client, nil)
+	clientMockRepository.On("FindClientByEmail", "ada@example.com").Return(&owner, nil)
+	clientMockRepository.On("UpdateClient", &client).Return(nil)
+
+	cs := services.ClientServiceImpl{ClientRepository: clientMockRepository}
+
+	resp, err := cs.UpdateProfile(aValidClientId, request.ClientRequest{Name: "Ada King", Email: "ada@example.com"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Ada King", resp.Name)
+	assert.Empty(t, resp.Phone)
+}
+
+func Test_GivenAnEmailOfAnotherClient_ThenUnableToUpdateProfile(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+
+	client := getValidDbClient()
+	other := models.Client{Id: aValidClientId + 1, Email: "charles@example.com"}
+	clientMockRepository.On("FindClientById", aValidClientId).Return(&client, nil)
+	clientMockRepository.On("FindClientByEmail", "charles@example.com").Return(&other, nil)
+
+	cs := services.ClientServiceImpl{ClientRepository: clientMockRepository}
+
+	_, err := cs.UpdateProfile(aValidClientId, request.ClientRequest{Name: "Ada", Email: "charles@example.com"})
+
+	assert.True(t, errors.Is(err, models.ErrEmailTaken))
+	clientMockRepository.AssertNotCalled(t, "UpdateClient", mock.Anything)
+}
+
+func Test_GivenAValidRole_ThenSetIt(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+
+	client := getValidDbClient()
+	clientMockRepository.On("FindClientById", aValidClientId).Return(&client, nil)
+	clientMockRepository.On("UpdateClient", mock.Anything).Return(nil)
+
+	cs := services.ClientServiceImpl{ClientRepository: clientMockRepository}
+
+	resp, err := cs.SetRole(aValidClientId, request.SetRoleRequest{Role: models.RoleSupport})
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(models.RoleSupport), resp.Role)
+}
+
+func Test_GivenAnUnknownRole_ThenUnableToSetIt(t *testing.T) {
+	clientMockRepository := &ClientRepositoryMock{}
+
+	cs := services.ClientServiceImpl{ClientRepository: clientMockRepository}
+
+	_, err := cs.SetRole(aValidClientId, request.SetRoleRequest{Role: "owner"})
+
+	assert.EqualError(t, err, "unsupported role: owner")
+	clientMockRepository.AssertNotCalled(t, "FindClientById", aValidClientId)
+}
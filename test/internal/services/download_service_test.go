@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/downloads"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	aDigitalProductId = 7
+)
+
+func getMockedDownloadService(orderStatus models.OrderStatus, downloadCount int) (*services.DownloadServiceImpl, *DownloadRepositoryMock) {
+	orderMockRepository := &OrderRepositoryMock{}
+	productMockRepository := &ProductRepositoryMock{}
+	downloadMockRepository := &DownloadRepositoryMock{}
+
+	order := models.Order{Id: aValidOrderId, ClientId: aValidClientId, Status: orderStatus}
+	product := getDigitalDbProduct()
+	orderMockRepository.On("FindOrderForClient", aValidClientId, aValidOrderId).Return(&order, nil)
+	orderMockRepository.On("FindOrderById", aValidOrderId).Return(&order, nil)
+	productMockRepository.On("FindProductById", aDigitalProductId).Return(&product, nil)
+	downloadMockRepository.On("FindOrderItem", aValidOrderId, aDigitalProductId).
+		Return(&models.OrderItem{OrderId: aValidOrderId, ProductId: aDigitalProductId, Downloads: downloadCount}, nil)
+
+	signer, _ := downloads.NewSigner([]byte("a-secret-long-enough-to-sign"), time.Minute)
+	return &services.DownloadServiceImpl{
+		OrderRepository:    orderMockRepository,
+		ProductRepository:  productMockRepository,
+		DownloadRepository: downloadMockRepository,
+		Signer:             signer,
+	}, downloadMockRepository
+}
+
+// getRedeemRequest turns an issued link back into what the download handler
+// reads from it.
+func getRedeemRequest(link string) request.RedeemDownloadRequest {
+	parsed, _ := url.Parse(link)
+	parts := strings.Split(parsed.Path, "/")
+	orderId, _ := strconv.Atoi(parts[3])
+	productId, _ := strconv.Atoi(parts[4])
+	expires, _ := strconv.ParseInt(parsed.Query().Get("expires"), 10, 64)
+	return request.RedeemDownloadRequest{OrderId: orderId, ProductId: productId, Expires: expires,
+		Signature: parsed.Query().Get("signature"), IpAddress: "10.0.0.1", UserAgent: "curl"}
+}
+
+func Test_GivenAPaidOrder_ThenIssueAndRedeemDownloadLink(t *testing.T) {
+	ds, downloadMockRepository := getMockedDownloadService(models.OrderStatusPaid, 1)
+	downloadMockRepository.On("RecordDownload", mock.Anything, services.MaxDownloadsPerItem).Return(nil)
+
+	resp, err := ds.IssueDownloadLink(aValidClientId, aValidOrderId, aDigitalProductId)
+
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(resp.Url, fmt.Sprintf("/api/downloads/%v/%v?expires=", aValidOrderId, aDigitalProductId)))
+	assert.Equal(t, services.MaxDownloadsPerItem-1, resp.RemainingDownloads)
+
+	downloadUrl, err := ds.RedeemDownloadLink(getRedeemRequest(resp.Url))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/ebook.pdf", downloadUrl)
+	download := downloadMockRepository.Calls[1].Arguments.Get(0).(*models.Download)
+	assert.Equal(t, models.Download{OrderId: aValidOrderId, ProductId: aDigitalProductId, IpAddress: "10.0.0.1", UserAgent: "curl"}, *download)
+}
+
+func Test_GivenAPendingOrder_ThenUnableToIssueDownloadLink(t *testing.T) {
+	ds, downloadMockRepository := getMockedDownloadService(models.OrderStatusPending, 0)
+
+	_, err := ds.IssueDownloadLink(aValidClientId, aValidOrderId, aDigitalProductId)
+
+	assert.True(t, errors.Is(err, downloads.ErrDownloadNotAllowed))
+	downloadMockRepository.AssertNotCalled(t, "FindOrderItem")
+}
+
+func Test_GivenAnExhaustedDownloadLimit_ThenUnableToIssueDownloadLink(t *testing.T) {
+	ds, _ := getMockedDownloadService(models.OrderStatusDelivered, services.MaxDownloadsPerItem)
+
+	_, err := ds.IssueDownloadLink(aValidClientId, aValidOrderId, aDigitalProductId)
+
+	assert.True(t, errors.Is(err, models.ErrDownloadLimitReached))
+}
+
+func Test_GivenATamperedLink_ThenUnableToRedeem(t *testing.T) {
+	ds, downloadMockRepository := getMockedDownloadService(models.OrderStatusPaid, 0)
+
+	resp, _ := ds.IssueDownloadLink(aValidClientId, aValidOrderId, aDigitalProductId)
+	req := getRedeemRequest(resp.Url)
+	req.Expires += 3600
+
+	_, err := ds.RedeemDownloadLink(req)
+
+	assert.True(t, errors.Is(err, downloads.ErrInvalidLink))
+	downloadMockRepository.AssertNotCalled(t, "RecordDownload")
+}
+
+func Test_GivenARefundedOrder_ThenUnableToRedeem(t *testing.T) {
+	ds, downloadMockRepository := getMockedDownloadService(models.OrderStatusPaid, 0)
+
+	resp, _ := ds.IssueDownloadLink(aValidClientId, aValidOrderId, aDigitalProductId)
+
+	refunded := models.Order{Id: aValidOrderId, ClientId: aValidClientId, Status: models.OrderStatusRefunded}
+	orderMockRepository := &OrderRepositoryMock{}
+	orderMockRepository.On("FindOrderById", aValidOrderId).Return(&refunded, nil)
+	ds.OrderRepository = orderMockRepository
+
+	_, err := ds.RedeemDownloadLink(getRedeemRequest(resp.Url))
+
+	assert.True(t, errors.Is(err, downloads.ErrDownloadNotAllowed))
+	downloadMockRepository.AssertNotCalled(t, "RecordDownload")
+}
+
+func Test_GivenAPhysicalProduct_ThenUnableToIssueDownloadLink(t *testing.T) {
+	ds, downloadMockRepository := getMockedDownloadService(models.OrderStatusPaid, 0)
+
+	product := getValidDbProduct()
+	productMockRepository := &ProductRepositoryMock{}
+	productMockRepository.On("FindProductById", aValidProductId).Return(&product, nil)
+	ds.ProductRepository = productMockRepository
+	downloadMockRepository.On("FindOrderItem", aValidOrderId, aValidProductId).Return(&models.OrderItem{}, nil)
+
+	_, err := ds.IssueDownloadLink(aValidClientId, aValidOrderId, aValidProductId)
+
+	assert.EqualError(t, err, fmt.Sprintf("the product: %v has nothing to download", aValidProductId))
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_GivenAPhysicalProduct_ThenSetStock(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
+
+	product := getValidDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&product, nil)
+	stockMockRepository.On("SetOnHand", aValidProductId, 8).Return(&models.Stock{ProductId: aValidProductId, OnHand: 8, Reserved: 3}, nil)
+
+	is := services.InventoryServiceImpl{ProductRepository: productMockRepository, StockRepository: stockMockRepository}
+
+	onHand := 8
+	resp, err := is.SetStock(aValidProductId, request.SetStockRequest{OnHand: &onHand})
+
+	assert.Nil(t, err)
+	assert.Equal(t, response.StockResponse{ProductId: aValidProductId, Tracked: true, OnHand: 8, Reserved: 3, Available: 5}, resp)
+}
+
+func Test_GivenANegativeOnHand_ThenUnableToSetStock(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
+
+	is := services.InventoryServiceImpl{ProductRepository: productMockRepository, StockRepository: stockMockRepository}
+
+	onHand := -1
+	_, err := is.SetStock(aValidProductId, request.SetStockRequest{OnHand: &onHand})
+
+	assert.EqualError(t, err, "onHand cannot be negative")
+	stockMockRepository.AssertNotCalled(t, "SetOnHand")
+}
+
+func Test_GivenADigitalProduct_ThenUnableToGetStock(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
+
+	product := getValidDbProduct()
+	product.Type = models.ProductTypeDigital
+	productMockRepository.On("FindProductById", aValidProductId).Return(&product, nil)
+
+	is := services.InventoryServiceImpl{ProductRepository: productMockRepository, StockRepository: stockMockRepository}
+
+	_, err := is.GetStock(aValidProductId)
+
+	assert.EqualError(t, err, "digital products do not track stock")
+	stockMockRepository.AssertNotCalled(t, "GetStock")
+}
+
+func Test_GivenAProductWithoutLedger_ThenStockIsNotTracked(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	stockMockRepository := &StockRepositoryMock{}
+
+	product := getValidDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&product, nil)
+	stockMockRepository.On("GetStock", aValidProductId).Return((*models.Stock)(nil), nil)
+
+	is := services.InventoryServiceImpl{ProductRepository: productMockRepository, StockRepository: stockMockRepository}
+
+	resp, err := is.GetStock(aValidProductId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, response.StockResponse{ProductId: aValidProductId}, resp)
+}
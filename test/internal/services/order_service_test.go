@@ -0,0 +1,430 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/payment"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func Test_GivenAValidClientId_ThenCreateOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentServiceMock := &PaymentServiceMock{}
+	shippingServiceMock := &ShippingServiceMock{}
+
+	orderShipping := models.OrderShipping{Option: "standard", Zone: "domestic", Cost: models.Money{Amount: 499, Currency: "USD"},
+		Address: getValidDbAddress().PostalAddress}
+	order := models.Order{Id: aValidOrderId, ClientId: aValidClientId, Status: models.OrderStatusPending,
+		Shipping: orderShipping, Total: models.Money{Amount: 5049, Currency: "USD"}}
+	shippingServiceMock.On("SelectShippingOption", aValidClientId, aValidAddressId, "standard").Return(orderShipping, nil)
+	orderMockRepository.On("CreateOrder", aValidClientId, orderShipping).Return(&order, nil)
+	paymentServiceMock.On("PayOrder", aValidClientId, aValidOrderId).
+		Return(response.PaymentResponse{OrderId: aValidOrderId, OrderStatus: "paid", Status: "captured"}, nil)
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository, PaymentService: paymentServiceMock, ShippingService: shippingServiceMock}
+
+	resp, err := os.CreateOrder(aValidClientId, request.CreateOrderRequest{ShippingAddressId: aValidAddressId, ShippingOption: "standard"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, aValidOrderId, resp.OrderId)
+	assert.Equal(t, "paid", resp.Status)
+	assert.Equal(t, "captured", resp.Payment.Status)
+	assert.Equal(t, "50.49 USD", resp.Total.Formatted)
+	assert.Equal(t, "4.99 USD", resp.Shipping.Cost.Formatted)
+	assert.Equal(t, "Ada Lovelace", resp.Shipping.Address.Recipient)
+	orderMockRepository.AssertNumberOfCalls(t, "CreateOrder", 1)
+}
+
+func Test_GivenADeclinedPayment_ThenOrderIsAwaitingPayment(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentServiceMock := &PaymentServiceMock{}
+	shippingServiceMock := &ShippingServiceMock{}
+
+	order := models.Order{Id: aValidOrderId, ClientId: aValidClientId, Status: models.OrderStatusPending}
+	shippingServiceMock.On("SelectShippingOption", aValidClientId, 0, "").Return(models.OrderShipping{}, nil)
+	orderMockRepository.On("CreateOrder", aValidClientId, models.OrderShipping{}).Return(&order, nil)
+	paymentServiceMock.On("PayOrder", aValidClientId, aValidOrderId).
+		Return(response.PaymentResponse{}, fmt.Errorf("%w: the card was declined", payment.ErrPaymentDeclined))
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository, PaymentService: paymentServiceMock, ShippingService: shippingServiceMock}
+
+	resp, err := os.CreateOrder(aValidClientId, request.CreateOrderRequest{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, aValidOrderId, resp.OrderId)
+	assert.Equal(t, "pending", resp.Status)
+	assert.Equal(t, "declined", resp.Payment.Status)
+	assert.Equal(t, "pending", resp.Payment.OrderStatus)
+	assert.Equal(t, "payment declined: the card was declined", resp.PaymentError)
+	assert.Nil(t, resp.Shipping)
+}
+
+func Test_GivenAValidClientId_ThenUnableToCreateOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	shippingServiceMock := &ShippingServiceMock{}
+
+	shippingServiceMock.On("SelectShippingOption", aValidClientId, 0, "").Return(models.OrderShipping{}, nil)
+	orderMockRepository.On("CreateOrder", aValidClientId, models.OrderShipping{}).Return(&models.Order{}, errors.New("the cart has no products"))
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository, ShippingService: shippingServiceMock}
+
+	resp, err := os.CreateOrder(aValidClientId, request.CreateOrderRequest{})
+
+	assert.EqualError(t, err, "the cart has no products")
+	assert.Equal(t, 0, resp.OrderId)
+}
+
+func Test_GivenAPhysicalCartWithoutShipping_ThenUnableToCreateOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	shippingServiceMock := &ShippingServiceMock{}
+
+	shippingServiceMock.On("SelectShippingOption", aValidClientId, 0, "").
+		Return(models.OrderShipping{}, errors.New("a shipping address and option are required for carts with physical products"))
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository, ShippingService: shippingServiceMock}
+
+	_, err := os.CreateOrder(aValidClientId, request.CreateOrderRequest{})
+
+	assert.EqualError(t, err, "a shipping address and option are required for carts with physical products")
+	orderMockRepository.AssertNotCalled(t, "CreateOrder")
+}
+
+func Test_GivenAValidOrderId_ThenReturnProductsFromOrderWithCapturedPrices(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	product := getValidDbProduct()
+	order := models.Order{
+		Id:       aValidOrderId,
+		ClientId: aValidClientId,
+		Items: []models.OrderItem{
+			{OrderId: aValidOrderId, ProductId: aValidProductId, Product: product, UnitPrice: models.Money{Amount: 4000, Currency: "USD"}, Quantity: 2},
+		},
+		Total: models.Money{Amount: 8000, Currency: "USD"},
+	}
+	orderMockRepository.On("GetOrderWithItems", aValidClientId, aValidOrderId).Return(&order, nil)
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	resp, err := os.GetProductsFromOrder(aValidClientId, aValidOrderId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resp.Products))
+	assert.Equal(t, aValidProductId, resp.Products[0].Id)
+	assert.Equal(t, int64(4000), resp.Products[0].Price.Amount)
+	assert.Equal(t, 2, resp.Products[0].Quantity)
+	assert.Equal(t, int64(8000), resp.Products[0].Subtotal.Amount)
+	assert.Equal(t, "80.00 USD", resp.Total.Formatted)
+}
+
+func Test_GivenAnInvalidOrderId_ThenUnableToGetProductsFromOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	orderMockRepository.On("GetOrderWithItems", aValidClientId, aValidOrderId).Return(&models.Order{}, errors.New("order not found"))
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	resp, err := os.GetProductsFromOrder(aValidClientId, aValidOrderId)
+
+	assert.EqualError(t, err, fmt.Sprintf("unable to get the list of products from the order: %v", aValidOrderId))
+	assert.Equal(t, 0, len(resp.Products))
+}
+
+func Test_GivenAValidClientId_ThenListOrdersWithPagination(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	summaries := []models.OrderSummary{
+		{Order: models.Order{Id: aValidOrderId, ClientId: aValidClientId}, ItemCount: 2},
+	}
+	orderMockRepository.On("ListOrdersForClient", aValidClientId, 2, 10).Return(&summaries, int64(11), nil)
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	resp, err := os.ListOrdersForClient(aValidClientId, 2, 10)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(resp.Orders))
+	assert.Equal(t, aValidOrderId, resp.Orders[0].Id)
+	assert.Equal(t, 2, resp.Orders[0].ItemCount)
+	assert.Equal(t, 2, resp.Pagination.Page)
+	assert.Equal(t, 10, resp.Pagination.PageSize)
+	assert.Equal(t, int64(11), resp.Pagination.TotalItems)
+	assert.Equal(t, 2, resp.Pagination.TotalPages)
+}
+
+func Test_GivenOutOfRangePagination_ThenListOrdersWithDefaults(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	var summaries []models.OrderSummary
+	orderMockRepository.On("ListOrdersForClient", aValidClientId, 1, services.MaxPageSize).Return(&summaries, int64(0), nil)
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	resp, err := os.ListOrdersForClient(aValidClientId, 0, 1000)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, resp.Orders)
+	assert.Equal(t, 0, len(resp.Orders))
+	assert.Equal(t, 0, resp.Pagination.TotalPages)
+	orderMockRepository.AssertCalled(t, "ListOrdersForClient", aValidClientId, 1, services.MaxPageSize)
+}
+
+func Test_GivenAnInvalidClientId_ThenUnableToListOrders(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	var summaries []models.OrderSummary
+	orderMockRepository.On("ListOrdersForClient", anInvalidClientId, 1, services.DefaultPageSize).Return(&summaries, int64(0), errors.New("client not found"))
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	_, err := os.ListOrdersForClient(anInvalidClientId, 0, 0)
+
+	assert.EqualError(t, err, fmt.Sprintf("unable to list the orders for the client: %v", anInvalidClientId))
+}
+
+func Test_ReleaseAbandonedOrders_OlderThanTheTimeout(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentServiceMock := &PaymentServiceMock{}
+
+	orderMockRepository.On("ReleaseAbandonedOrders", mock.Anything).Return([]int{aValidOrderId, aValidOrderId + 1}, nil)
+	paymentServiceMock.On("ReversePayment", mock.Anything).Return(nil)
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository, PaymentService: paymentServiceMock}
+
+	before := time.Now()
+	released, err := os.ReleaseAbandonedOrders()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, released)
+	cutoff := orderMockRepository.Calls[0].Arguments.Get(0).(time.Time)
+	assert.WithinDuration(t, before.Add(-services.AbandonedOrderTimeout), cutoff, time.Second)
+	paymentServiceMock.AssertCalled(t, "ReversePayment", aValidOrderId)
+	paymentServiceMock.AssertCalled(t, "ReversePayment", aValidOrderId+1)
+}
+
+func Test_GivenAnAbandonedOrderAwaitingConfirmation_ThenVoidItsAuthorization(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentMockRepository := &PaymentRepositoryMock{}
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorRequireConfirmation)
+
+	amount := models.Money{Amount: 4550, Currency: "USD"}
+	authorization, _ := provider.Authorize(payment.AuthorizationRequest{OrderId: aValidOrderId, Amount: amount})
+	pending := models.Payment{OrderId: aValidOrderId, AuthorizationId: authorization.Id, Status: models.PaymentStatusRequiresConfirmation, Amount: amount}
+
+	orderMockRepository.On("ReleaseAbandonedOrders", mock.Anything).Return([]int{aValidOrderId}, nil)
+	paymentMockRepository.On("FindPaymentByOrderId", aValidOrderId).Return(&pending, nil)
+	paymentMockRepository.On("SavePayment", mock.Anything).Return(nil)
+
+	ps := &services.PaymentServiceImpl{OrderRepository: orderMockRepository, PaymentRepository: paymentMockRepository, PaymentProvider: provider}
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository, PaymentService: ps}
+
+	released, err := os.ReleaseAbandonedOrders()
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, released)
+	assert.Equal(t, models.PaymentStatusVoided, pending.Status)
+	_, err = provider.Confirm(authorization.Id)
+	assert.True(t, errors.Is(err, payment.ErrInvalidAuthorizationOp))
+}
+
+func Test_GivenAnAllowedTransition_ThenTransitionOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	orderMockRepository.On("FindOrderById", aValidOrderId).Return(&models.Order{Id: aValidOrderId, Status: models.OrderStatusPaid}, nil)
+	transition := models.OrderTransition{OrderId: aValidOrderId, FromStatus: models.OrderStatusPaid, ToStatus: models.OrderStatusFulfilled, Actor: "warehouse"}
+	orderMockRepository.On("TransitionOrder", aValidOrderId, models.OrderStatusPaid, models.OrderStatusFulfilled, "warehouse").Return(&transition, nil)
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	resp, err := os.TransitionOrder(aValidOrderId, "warehouse", request.OrderTransitionRequest{Status: models.OrderStatusFulfilled})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "paid", resp.From)
+	assert.Equal(t, "fulfilled", resp.To)
+	assert.Equal(t, "warehouse", resp.Actor)
+}
+
+func Test_GivenAForbiddenTransition_ThenUnableToTransitionOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	orderMockRepository.On("FindOrderById", aValidOrderId).Return(&models.Order{Id: aValidOrderId, Status: models.OrderStatusShipped}, nil)
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	_, err := os.TransitionOrder(aValidOrderId, "support", request.OrderTransitionRequest{Status: models.OrderStatusCancelled})
+
+	assert.True(t, errors.Is(err, models.ErrInvalidOrderTransition))
+	assert.EqualError(t, err, "invalid order transition: an order cannot move from shipped to cancelled")
+	orderMockRepository.AssertNotCalled(t, "TransitionOrder")
+}
+
+func Test_GivenADeliveredOrder_ThenRefundItThroughTheProvider(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentMockRepository := &PaymentRepositoryMock{}
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorApprove)
+
+	amount := models.Money{Amount: 4550, Currency: "USD"}
+	authorization, _ := provider.Authorize(payment.AuthorizationRequest{OrderId: aValidOrderId, Amount: amount})
+	_ = provider.Capture(authorization.Id)
+	captured := models.Payment{OrderId: aValidOrderId, AuthorizationId: authorization.Id, Status: models.PaymentStatusCaptured, Amount: amount}
+
+	orderMockRepository.On("FindOrderById", aValidOrderId).Return(&models.Order{Id: aValidOrderId, Status: models.OrderStatusDelivered}, nil)
+	paymentMockRepository.On("FindPaymentByOrderId", aValidOrderId).Return(&captured, nil)
+	paymentMockRepository.On("SavePayment", mock.Anything).Return(nil)
+	transition := models.OrderTransition{OrderId: aValidOrderId, FromStatus: models.OrderStatusDelivered, ToStatus: models.OrderStatusRefunded, Actor: "support"}
+	orderMockRepository.On("TransitionOrder", aValidOrderId, models.OrderStatusDelivered, models.OrderStatusRefunded, "support").Return(&transition, nil)
+
+	ps := &services.PaymentServiceImpl{OrderRepository: orderMockRepository, PaymentRepository: paymentMockRepository, PaymentProvider: provider}
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository, PaymentService: ps}
+
+	resp, err := os.TransitionOrder(aValidOrderId, "support", request.OrderTransitionRequest{Status: models.OrderStatusRefunded})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "refunded", resp.To)
+	assert.Equal(t, amount, provider.Refunded(authorization.Id))
+	assert.Equal(t, models.PaymentStatusRefunded, captured.Status)
+}
+
+func Test_GivenAFailingRefund_ThenOrderIsNotMarkedRefunded(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentServiceMock := &PaymentServiceMock{}
+
+	orderMockRepository.On("FindOrderById", aValidOrderId).Return(&models.Order{Id: aValidOrderId, Status: models.OrderStatusCancelled, RefundPending: true}, nil)
+	paymentServiceMock.On("RefundPayment", aValidOrderId).Return(fmt.Errorf("%w: no answer", payment.ErrPaymentTimeout))
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository, PaymentService: paymentServiceMock}
+
+	_, err := os.TransitionOrder(aValidOrderId, "support", request.OrderTransitionRequest{Status: models.OrderStatusRefunded})
+
+	assert.True(t, errors.Is(err, payment.ErrPaymentTimeout))
+	orderMockRepository.AssertNotCalled(t, "TransitionOrder")
+}
+
+func Test_GivenAPaidOrderCancelledByStaff_ThenReverseItsPayment(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentServiceMock := &PaymentServiceMock{}
+
+	orderMockRepository.On("FindOrderById", aValidOrderId).Return(&models.Order{Id: aValidOrderId, Status: models.OrderStatusPaid}, nil)
+	transition := models.OrderTransition{OrderId: aValidOrderId, FromStatus: models.OrderStatusPaid, ToStatus: models.OrderStatusCancelled, Actor: "support"}
+	orderMockRepository.On("TransitionOrder", aValidOrderId, models.OrderStatusPaid, models.OrderStatusCancelled, "support").Return(&transition, nil)
+	paymentServiceMock.On("ReversePayment", aValidOrderId).Return(nil)
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository, PaymentService: paymentServiceMock}
+
+	_, err := os.TransitionOrder(aValidOrderId, "support", request.OrderTransitionRequest{Status: models.OrderStatusCancelled})
+
+	assert.Nil(t, err)
+	paymentServiceMock.AssertCalled(t, "ReversePayment", aValidOrderId)
+}
+
+func Test_GivenATransitionWithoutActor_ThenUnableToTransitionOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	_, err := os.TransitionOrder(aValidOrderId, " ", request.OrderTransitionRequest{Status: models.OrderStatusPaid})
+
+	assert.EqualError(t, err, "actor is required")
+	orderMockRepository.AssertNotCalled(t, "FindOrderById")
+}
+
+func Test_GivenAnOrderWithHistory_ThenListTransitionsInOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	orderMockRepository.On("FindOrderById", aValidOrderId).Return(&models.Order{Id: aValidOrderId, Status: models.OrderStatusFulfilled}, nil)
+	transitions := []models.OrderTransition{
+		{Id: 2, OrderId: aValidOrderId, FromStatus: models.OrderStatusPaid, ToStatus: models.OrderStatusFulfilled, Actor: "warehouse"},
+		{Id: 1, OrderId: aValidOrderId, FromStatus: models.OrderStatusPending, ToStatus: models.OrderStatusPaid, Actor: "payments"},
+	}
+	orderMockRepository.On("ListOrderTransitions", aValidOrderId).Return(&transitions, nil)
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	resp, err := os.ListOrderTransitions(aValidOrderId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(resp))
+	assert.Equal(t, "payments", resp[0].Actor)
+	assert.Equal(t, "warehouse", resp[1].Actor)
+}
+
+func Test_GivenAPaidOrder_ThenCancelOrderAndRefund(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentServiceMock := &PaymentServiceMock{}
+
+	orderMockRepository.On("FindOrderForClient", aValidClientId, aValidOrderId).Return(&models.Order{Id: aValidOrderId, Status: models.OrderStatusPaid}, nil)
+	cancelled := models.Order{Id: aValidOrderId, Status: models.OrderStatusCancelled, CancellationReason: "ordered by mistake", RefundPending: true}
+	orderMockRepository.On("CancelOrder", aValidOrderId, models.OrderStatusPaid, fmt.Sprintf("client:%v", aValidClientId), "ordered by mistake").Return(&cancelled, nil)
+	paymentServiceMock.On("ReversePayment", aValidOrderId).Return(nil)
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository, PaymentService: paymentServiceMock}
+
+	resp, err := os.CancelOrder(aValidClientId, aValidOrderId, request.CancelOrderRequest{Reason: "  ordered by mistake "})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "refunded", resp.Status)
+	assert.Equal(t, "ordered by mistake", resp.Reason)
+	assert.False(t, resp.RefundPending)
+}
+
+func Test_GivenAFailingRefund_ThenCancelOrderAndKeepRefundPending(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentServiceMock := &PaymentServiceMock{}
+
+	orderMockRepository.On("FindOrderForClient", aValidClientId, aValidOrderId).Return(&models.Order{Id: aValidOrderId, Status: models.OrderStatusFulfilled}, nil)
+	cancelled := models.Order{Id: aValidOrderId, Status: models.OrderStatusCancelled, CancellationReason: "arrives too late", RefundPending: true}
+	orderMockRepository.On("CancelOrder", aValidOrderId, models.OrderStatusFulfilled, fmt.Sprintf("client:%v", aValidClientId), "arrives too late").Return(&cancelled, nil)
+	paymentServiceMock.On("ReversePayment", aValidOrderId).Return(fmt.Errorf("%w: no answer", payment.ErrPaymentTimeout))
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository, PaymentService: paymentServiceMock}
+
+	resp, err := os.CancelOrder(aValidClientId, aValidOrderId, request.CancelOrderRequest{Reason: "arrives too late"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "cancelled", resp.Status)
+	assert.True(t, resp.RefundPending)
+}
+
+func Test_GivenAShippedOrder_ThenUnableToCancelOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	orderMockRepository.On("FindOrderForClient", aValidClientId, aValidOrderId).Return(&models.Order{Id: aValidOrderId, Status: models.OrderStatusShipped}, nil)
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	_, err := os.CancelOrder(aValidClientId, aValidOrderId, request.CancelOrderRequest{Reason: "too late"})
+
+	assert.True(t, errors.Is(err, models.ErrInvalidOrderTransition))
+	orderMockRepository.AssertNotCalled(t, "CancelOrder")
+}
+
+func Test_GivenNoCancellationReason_ThenUnableToCancelOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	_, err := os.CancelOrder(aValidClientId, aValidOrderId, request.CancelOrderRequest{Reason: "   "})
+
+	assert.EqualError(t, err, "a cancellation reason is required")
+	orderMockRepository.AssertNotCalled(t, "FindOrderForClient")
+}
+
+func Test_GivenAnOrderFromAnotherClient_ThenUnableToCancelOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+
+	orderMockRepository.On("FindOrderForClient", aValidClientId, aValidOrderId).
+		Return(&models.Order{}, errors.New(fmt.Sprintf("order: %v for clientId: %v not found", aValidOrderId, aValidClientId)))
+
+	os := services.OrderServiceImpl{OrderRepository: orderMockRepository}
+
+	_, err := os.CancelOrder(aValidClientId, aValidOrderId, request.CancelOrderRequest{Reason: "changed my mind"})
+
+	assert.EqualError(t, err, fmt.Sprintf("order: %v for clientId: %v not found", aValidOrderId, aValidClientId))
+	orderMockRepository.AssertNotCalled(t, "CancelOrder")
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/payment"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func getMockedPendingOrder() *models.Order {
+	return &models.Order{Id: aValidOrderId, ClientId: aValidClientId, Status: models.OrderStatusPending, Total: models.Money{Amount: 4550, Currency: "USD"}}
+}
+
+func Test_GivenAPendingOrder_ThenPayOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentMockRepository := &PaymentRepositoryMock{}
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorApprove)
+
+	orderMockRepository.On("FindOrderForClient", aValidClientId, aValidOrderId).Return(getMockedPendingOrder(), nil)
+	paymentMockRepository.On("FindPaymentByOrderId", aValidOrderId).Return((*models.Payment)(nil), nil)
+	paymentMockRepository.On("SavePayment", mock.Anything).Return(nil)
+	orderMockRepository.On("TransitionOrder", aValidOrderId, models.OrderStatusPending, models.OrderStatusPaid, services.PaymentsActor).
+		Return(&models.OrderTransition{}, nil)
+
+	ps := services.PaymentServiceImpl{OrderRepository: orderMockRepository, PaymentRepository: paymentMockRepository, PaymentProvider: provider}
+
+	resp, err := ps.PayOrder(aValidClientId, aValidOrderId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "paid", resp.OrderStatus)
+	assert.Equal(t, "captured", resp.Status)
+	assert.Equal(t, "fake_auth_000001", resp.AuthorizationId)
+	assert.Equal(t, "45.50 USD", resp.Amount.Formatted)
+}
+
+func Test_GivenADecliningProvider_ThenUnableToPayOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentMockRepository := &PaymentRepositoryMock{}
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorDecline)
+
+	orderMockRepository.On("FindOrderForClient", aValidClientId, aValidOrderId).Return(getMockedPendingOrder(), nil)
+	paymentMockRepository.On("FindPaymentByOrderId", aValidOrderId).Return((*models.Payment)(nil), nil)
+	paymentMockRepository.On("SavePayment", mock.Anything).Return(nil)
+
+	ps := services.PaymentServiceImpl{OrderRepository: orderMockRepository, PaymentRepository: paymentMockRepository, PaymentProvider: provider}
+
+	_, err := ps.PayOrder(aValidClientId, aValidOrderId)
+
+	assert.True(t, errors.Is(err, payment.ErrPaymentDeclined))
+	saved := paymentMockRepository.Calls[1].Arguments.Get(0).(*models.Payment)
+	assert.Equal(t, models.PaymentStatusDeclined, saved.Status)
+	orderMockRepository.AssertNotCalled(t, "TransitionOrder")
+}
+
+func Test_GivenAPaymentRequiringConfirmation_ThenOrderStaysPendingUntilConfirmed(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentMockRepository := &PaymentRepositoryMock{}
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorRequireConfirmation)
+
+	orderMockRepository.On("FindOrderForClient", aValidClientId, aValidOrderId).Return(getMockedPendingOrder(), nil)
+	paymentMockRepository.On("FindPaymentByOrderId", aValidOrderId).Return((*models.Payment)(nil), nil).Once()
+	paymentMockRepository.On("SavePayment", mock.Anything).Return(nil)
+	orderMockRepository.On("TransitionOrder", aValidOrderId, models.OrderStatusPending, models.OrderStatusPaid, services.PaymentsActor).
+		Return(&models.OrderTransition{}, nil)
+
+	ps := services.PaymentServiceImpl{OrderRepository: orderMockRepository, PaymentRepository: paymentMockRepository, PaymentProvider: provider}
+
+	resp, err := ps.PayOrder(aValidClientId, aValidOrderId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "pending", resp.OrderStatus)
+	assert.Equal(t, "requires_confirmation", resp.Status)
+	orderMockRepository.AssertNotCalled(t, "TransitionOrder")
+
+	pending := models.Payment{OrderId: aValidOrderId, AuthorizationId: resp.AuthorizationId, Status: models.PaymentStatusRequiresConfirmation,
+		Amount: models.Money{Amount: 4550, Currency: "USD"}}
+	paymentMockRepository.On("FindPaymentByOrderId", aValidOrderId).Return(&pending, nil)
+
+	resp, err = ps.ConfirmPayment(aValidClientId, aValidOrderId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "paid", resp.OrderStatus)
+	assert.Equal(t, "captured", resp.Status)
+}
+
+func Test_GivenAPaidOrder_ThenUnableToPayAgain(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentMockRepository := &PaymentRepositoryMock{}
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorApprove)
+
+	order := getMockedPendingOrder()
+	order.Status = models.OrderStatusPaid
+	orderMockRepository.On("FindOrderForClient", aValidClientId, aValidOrderId).Return(order, nil)
+
+	ps := services.PaymentServiceImpl{OrderRepository: orderMockRepository, PaymentRepository: paymentMockRepository, PaymentProvider: provider}
+
+	_, err := ps.PayOrder(aValidClientId, aValidOrderId)
+
+	assert.True(t, errors.Is(err, models.ErrInvalidOrderTransition))
+	paymentMockRepository.AssertNotCalled(t, "FindPaymentByOrderId")
+}
+
+func Test_GivenACapturedPayment_ThenReversePaymentRefundsTheOrder(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentMockRepository := &PaymentRepositoryMock{}
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorApprove)
+
+	authorization, _ := provider.Authorize(payment.AuthorizationRequest{OrderId: aValidOrderId, Amount: models.Money{Amount: 4550, Currency: "USD"}})
+	_ = provider.Capture(authorization.Id)
+
+	captured := models.Payment{OrderId: aValidOrderId, AuthorizationId: authorization.Id, Status: models.PaymentStatusCaptured,
+		Amount: models.Money{Amount: 4550, Currency: "USD"}}
+	paymentMockRepository.On("FindPaymentByOrderId", aValidOrderId).Return(&captured, nil)
+	paymentMockRepository.On("SavePayment", mock.Anything).Return(nil)
+	orderMockRepository.On("TransitionOrder", aValidOrderId, models.OrderStatusCancelled, models.OrderStatusRefunded, services.PaymentsActor).
+		Return(&models.OrderTransition{}, nil)
+
+	ps := services.PaymentServiceImpl{OrderRepository: orderMockRepository, PaymentRepository: paymentMockRepository, PaymentProvider: provider}
+
+	err := ps.ReversePayment(aValidOrderId)
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.PaymentStatusRefunded, captured.Status)
+	orderMockRepository.AssertCalled(t, "TransitionOrder", aValidOrderId, models.OrderStatusCancelled, models.OrderStatusRefunded, services.PaymentsActor)
+}
+
+func Test_GivenAnUnpaidOrder_ThenReversePaymentDoesNothing(t *testing.T) {
+	orderMockRepository := &OrderRepositoryMock{}
+	paymentMockRepository := &PaymentRepositoryMock{}
+	provider, _ := payment.NewFakeProvider(payment.FakeBehaviorApprove)
+
+	paymentMockRepository.On("FindPaymentByOrderId", aValidOrderId).Return((*models.Payment)(nil), nil)
+
+	ps := services.PaymentServiceImpl{OrderRepository: orderMockRepository, PaymentRepository: paymentMockRepository, PaymentProvider: provider}
+
+	err := ps.ReversePayment(aValidOrderId)
+
+	assert.Nil(t, err)
+	paymentMockRepository.AssertNotCalled(t, "SavePayment")
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func getValidProductRequest() request.ProductRequest {
+	return request.ProductRequest{
+		CategoryId: 1,
+		Label:      "Keyboard",
+		Type:       models.ProductTypePhysical,
+		Weight:     3.5,
+		Price:      request.MoneyRequest{Amount: 4550, Currency: "usd"},
+	}
+}
+
+func Test_GivenAValidProductRequest_ThenCreateProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	categoryMockRepository.On("FindCategoryById", 1).Return(&models.Category{Id: 1, Label: "Peripherals"}, nil)
+	productMockRepository.On("CreateProduct", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*models.Product)
+		arg.Id = aValidProductId
+	})
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository, CategoryRepository: categoryMockRepository}
+
+	resp, err := ps.CreateProduct(getValidProductRequest())
+
+	expected := getValidResponseProduct()
+	expected.Category = "Peripherals"
+	assert.Nil(t, err)
+	assert.Equal(t, expected, resp)
+}
+
+func Test_GivenADigitalProductWithoutDownloadUrl_ThenUnableToCreateProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	req := getValidProductRequest()
+	req.Type = models.ProductTypeDigital
+	req.Weight = 0
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository, CategoryRepository: categoryMockRepository}
+
+	_, err := ps.CreateProduct(req)
+
+	assert.EqualError(t, err, "digital products require a valid download url")
+	productMockRepository.AssertNotCalled(t, "CreateProduct")
+}
+
+func Test_GivenAPhysicalProductWithoutWeight_ThenUnableToCreateProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	req := getValidProductRequest()
+	req.Weight = 0
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository, CategoryRepository: categoryMockRepository}
+
+	_, err := ps.CreateProduct(req)
+
+	assert.EqualError(t, err, "physical products require a positive weight")
+	productMockRepository.AssertNotCalled(t, "CreateProduct")
+}
+
+func Test_GivenAPriceOutsideTheCatalogCurrencies_ThenUnableToCreateProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	req := getValidProductRequest()
+	req.Price.Currency = "eur"
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository, CategoryRepository: categoryMockRepository, Currencies: []string{"USD"}}
+
+	_, err := ps.CreateProduct(req)
+
+	assert.EqualError(t, err, "product price must be in one of: USD")
+	productMockRepository.AssertNotCalled(t, "CreateProduct")
+}
+
+func Test_GivenAnUnknownCategory_ThenUnableToCreateProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	categoryMockRepository.On("FindCategoryById", 1).Return(&models.Category{}, errors.New("category not found"))
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository, CategoryRepository: categoryMockRepository}
+
+	_, err := ps.CreateProduct(getValidProductRequest())
+
+	assert.EqualError(t, err, "category with id: 1 not found")
+	productMockRepository.AssertNotCalled(t, "CreateProduct")
+}
+
+func Test_GivenAPatchSwitchingToDigital_WithoutClearingWeight_ThenUnableToPatchProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	product := getValidDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&product, nil)
+
+	digital := models.ProductTypeDigital
+	downloadUrl := "https://downloads.example.com/keyboard.zip"
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository, CategoryRepository: categoryMockRepository}
+
+	_, err := ps.PatchProduct(aValidProductId, request.PatchProductRequest{Type: &digital, DownloadUrl: &downloadUrl})
+
+	assert.EqualError(t, err, "digital products cannot have a weight")
+	productMockRepository.AssertNotCalled(t, "UpdateProduct")
+}
+
+func Test_GivenAPatchWithLabel_ThenPatchProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	product := getValidDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&product, nil)
+	productMockRepository.On("UpdateProduct", mock.Anything).Return(nil)
+	categoryMockRepository.On("FindCategoryById", 1).Return(&models.Category{Id: 1, Label: "Peripherals"}, nil)
+
+	label := "Mechanical keyboard"
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository, CategoryRepository: categoryMockRepository}
+
+	resp, err := ps.PatchProduct(aValidProductId, request.PatchProductRequest{Label: &label})
+
+	assert.Nil(t, err)
+	assert.Equal(t, label, resp.Label)
+	assert.Equal(t, 3.5, resp.Shipping.Weight)
+}
+
+func Test_GivenAnArchivedProduct_ThenUnableToUpdateProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	product := getArchivedDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&product, nil)
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository, CategoryRepository: categoryMockRepository}
+
+	_, err := ps.UpdateProduct(aValidProductId, getValidProductRequest())
+
+	assert.EqualError(t, err, fmt.Sprintf("product with id: %v is archived", aValidProductId))
+}
+
+func Test_GivenAValidProductId_ThenArchiveProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+
+	product := getValidDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&product, nil)
+	productMockRepository.On("UpdateProduct", mock.Anything).Return(nil)
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository}
+
+	err := ps.ArchiveProduct(aValidProductId)
+
+	assert.Nil(t, err)
+	assert.True(t, product.IsArchived())
+}
+
+func Test_GivenAnArchivedProduct_ThenArchiveIsANoOp(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+
+	product := getArchivedDbProduct()
+	productMockRepository.On("FindProductById", aValidProductId).Return(&product, nil)
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository}
+
+	err := ps.ArchiveProduct(aValidProductId)
+
+	assert.Nil(t, err)
+	productMockRepository.AssertNotCalled(t, "UpdateProduct")
+}
+
+func Test_GivenAServiceProductWithAWeight_ThenUnableToCreateProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	req := getValidProductRequest()
+	req.Type = models.ProductTypeService
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository, CategoryRepository: categoryMockRepository}
+
+	_, err := ps.CreateProduct(req)
+
+	assert.EqualError(t, err, "service products cannot have a weight or a download url")
+	productMockRepository.AssertNotCalled(t, "CreateProduct")
+}
+
+func Test_GivenAProductRequestWithoutType_ThenUnableToCreateProduct(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	req := getValidProductRequest()
+	req.Type = 0
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository, CategoryRepository: categoryMockRepository}
+
+	_, err := ps.CreateProduct(req)
+
+	assert.EqualError(t, err, "product type must be one of: physical, digital, service, bundle")
+	productMockRepository.AssertNotCalled(t, "CreateProduct")
+}
+
+func Test_GivenAValidDigitalProductRequest_ThenCreateProductWithDownloadDetails(t *testing.T) {
+	productMockRepository := &ProductRepositoryMock{}
+	categoryMockRepository := &CategoryRepositoryMock{}
+
+	categoryMockRepository.On("FindCategoryById", 1).Return(&models.Category{Id: 1, Label: "Software"}, nil)
+	productMockRepository.On("CreateProduct", mock.Anything).Return(nil)
+
+	req := getValidProductRequest()
+	req.Type = models.ProductTypeDigital
+	req.Weight = 0
+	req.DownloadUrl = "https://downloads.example.com/editor.zip"
+
+	ps := services.ProductAdminServiceImpl{ProductRepository: productMockRepository, CategoryRepository: categoryMockRepository}
+
+	resp, err := ps.CreateProduct(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "digital", resp.Type)
+	assert.Nil(t, resp.Shipping)
+	assert.Equal(t, req.DownloadUrl, resp.Download.Url)
+}
@@ -2,14 +2,18 @@ package services
 
 import (
 	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
 	"github.com/emiliocc5/online-store-api/internal/models/response"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 const (
 	aValidProductId    = 1
 	aValidClientId     = 2
 	aValidCartId       = 3
+	aValidOrderId      = 4
+	aValidAddressId    = 5
 	anInvalidProductId = 000
 	anInvalidClientId  = 111
 )
@@ -23,19 +27,64 @@ func (mock *CartRepositoryMock) GetCartByClient(clientId int) (*models.Cart, err
 	return args.Get(0).(*models.Cart), args.Error(1)
 }
 
+func (mock *CartRepositoryMock) GetCartItems(cartId int) (*[]models.ProductCart, error) {
+	args := mock.Called(cartId)
+	return args.Get(0).(*[]models.ProductCart), args.Error(1)
+}
+
 func (mock *CartRepositoryMock) AddProductToCart(productId, clientId int) error {
 	args := mock.Called(productId, clientId)
 	return args.Error(0)
 }
 
+func (mock *CartRepositoryMock) SetProductQuantity(productId, clientId, quantity int) error {
+	args := mock.Called(productId, clientId, quantity)
+	return args.Error(0)
+}
+
+func (mock *CartRepositoryMock) RemoveProductFromCart(productId, clientId int) error {
+	args := mock.Called(productId, clientId)
+	return args.Error(0)
+}
+
+func (mock *CartRepositoryMock) ClearCart(clientId int) error {
+	args := mock.Called(clientId)
+	return args.Error(0)
+}
+
 func (mock *ClientRepositoryMock) IsClientInDataBase(clientId int) bool {
 	args := mock.Called(clientId)
 	return args.Get(0).(bool)
 }
 
-func (mock *ProductRepositoryMock) FindProductsFromCart(cartId int) (*[]models.Product, error) {
-	args := mock.Called(cartId)
-	return args.Get(0).(*[]models.Product), args.Error(1)
+func (mock *ClientRepositoryMock) FindClientById(clientId int) (*models.Client, error) {
+	args := mock.Called(clientId)
+	return args.Get(0).(*models.Client), args.Error(1)
+}
+
+func (mock *ClientRepositoryMock) FindClientByEmail(email string) (*models.Client, error) {
+	args := mock.Called(email)
+	return args.Get(0).(*models.Client), args.Error(1)
+}
+
+func (mock *ClientRepositoryMock) CreateClient(client *models.Client) error {
+	args := mock.Called(client)
+	return args.Error(0)
+}
+
+func (mock *ClientRepositoryMock) UpdateClient(client *models.Client) error {
+	args := mock.Called(client)
+	return args.Error(0)
+}
+
+func (mock *ClientRepositoryMock) DeleteClient(clientId int) error {
+	args := mock.Called(clientId)
+	return args.Error(0)
+}
+
+func (mock *ProductRepositoryMock) ListProducts(filter models.ProductFilter) (*[]models.Product, int64, error) {
+	args := mock.Called(filter)
+	return args.Get(0).(*[]models.Product), args.Get(1).(int64), args.Error(2)
 }
 
 func (mock *ProductRepositoryMock) FindProductById(productId int) (*models.Product, error) {
@@ -43,13 +92,21 @@ func (mock *ProductRepositoryMock) FindProductById(productId int) (*models.Produ
 	return args.Get(0).(*models.Product), args.Error(1)
 }
 
-func getValidListOfProducts() *[]models.Product {
-	var products []models.Product
+func (mock *ProductRepositoryMock) CreateProduct(product *models.Product) error {
+	args := mock.Called(product)
+	return args.Error(0)
+}
 
-	product := getValidDbProduct()
+func (mock *ProductRepositoryMock) UpdateProduct(product *models.Product) error {
+	args := mock.Called(product)
+	return args.Error(0)
+}
 
-	products = append(products, product)
-	return &products
+func getValidListOfCartItems() *[]models.ProductCart {
+	items := []models.ProductCart{
+		{ProductId: aValidProductId, CartId: aValidCartId, Product: getValidDbProduct(), Quantity: 1},
+	}
+	return &items
 }
 
 func getValidDbProduct() models.Product {
@@ -57,19 +114,27 @@ func getValidDbProduct() models.Product {
 		Id:          aValidProductId,
 		CategoryId:  1,
 		Label:       "Keyboard",
-		Type:        1,
+		Type:        models.ProductTypePhysical,
 		DownloadUrl: "",
 		Weight:      3.5,
+		Price:       models.Money{Amount: 4550, Currency: "USD"},
 	}
 }
 
+func getArchivedDbProduct() models.Product {
+	product := getValidDbProduct()
+	archivedAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	product.ArchivedAt = &archivedAt
+	return product
+}
+
 func getValidResponseProduct() response.ProductResponse {
 	return response.ProductResponse{
-		Id:          aValidProductId,
-		Label:       "Keyboard",
-		Type:        1,
-		DownloadUrl: "",
-		Weight:      3.5,
+		Id:       aValidProductId,
+		Label:    "Keyboard",
+		Type:     "physical",
+		Price:    response.MoneyResponse{Amount: 4550, Currency: "USD", Formatted: "45.50 USD"},
+		Shipping: &response.ShippingDetailsResponse{Weight: 3.5},
 	}
 }
 
@@ -79,3 +144,280 @@ func getMockedValidCart() models.Cart {
 		ClientId: aValidClientId,
 	}
 }
+
+type OrderRepositoryMock struct{ mock.Mock }
+
+func (mock *OrderRepositoryMock) CreateOrder(clientId int, shipping models.OrderShipping) (*models.Order, error) {
+	args := mock.Called(clientId, shipping)
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (mock *OrderRepositoryMock) GetOrderWithItems(clientId, orderId int) (*models.Order, error) {
+	args := mock.Called(clientId, orderId)
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (mock *OrderRepositoryMock) ListOrdersForClient(clientId, page, pageSize int) (*[]models.OrderSummary, int64, error) {
+	args := mock.Called(clientId, page, pageSize)
+	return args.Get(0).(*[]models.OrderSummary), args.Get(1).(int64), args.Error(2)
+}
+
+type CategoryRepositoryMock struct{ mock.Mock }
+type CatalogServiceMock struct{ mock.Mock }
+
+func (mock *CategoryRepositoryMock) CreateCategory(category *models.Category) error {
+	args := mock.Called(category)
+	return args.Error(0)
+}
+
+func (mock *CategoryRepositoryMock) FindCategoryById(categoryId int) (*models.Category, error) {
+	args := mock.Called(categoryId)
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (mock *CategoryRepositoryMock) ListCategories() (*[]models.Category, error) {
+	args := mock.Called()
+	return args.Get(0).(*[]models.Category), args.Error(1)
+}
+
+func (mock *CategoryRepositoryMock) UpdateCategory(category *models.Category) error {
+	args := mock.Called(category)
+	return args.Error(0)
+}
+
+func (mock *CategoryRepositoryMock) DeleteCategory(categoryId int) error {
+	args := mock.Called(categoryId)
+	return args.Error(0)
+}
+
+func (mock *CatalogServiceMock) ListProducts(filter models.ProductFilter) (response.ListProductsResponse, error) {
+	args := mock.Called(filter)
+	return args.Get(0).(response.ListProductsResponse), args.Error(1)
+}
+
+func (mock *CatalogServiceMock) GetProduct(productId int) (response.ProductResponse, error) {
+	args := mock.Called(productId)
+	return args.Get(0).(response.ProductResponse), args.Error(1)
+}
+
+// getMockedCategoryTree returns Electronics(1) > Computers(2) > Laptops(4),
+// Electronics(1) > Phones(3) and Books(5).
+func getMockedCategoryTree() *[]models.Category {
+	electronics, computers := 1, 2
+	categories := []models.Category{
+		{Id: 1, Label: "Electronics"},
+		{Id: 2, Label: "Computers", ParentId: &electronics},
+		{Id: 3, Label: "Phones", ParentId: &electronics},
+		{Id: 4, Label: "Laptops", ParentId: &computers},
+		{Id: 5, Label: "Books"},
+	}
+	return &categories
+}
+
+func (mock *OrderRepositoryMock) FindOrderById(orderId int) (*models.Order, error) {
+	args := mock.Called(orderId)
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (mock *OrderRepositoryMock) FindOrderForClient(clientId, orderId int) (*models.Order, error) {
+	args := mock.Called(clientId, orderId)
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (mock *OrderRepositoryMock) CancelOrder(orderId int, from models.OrderStatus, actor, reason string) (*models.Order, error) {
+	args := mock.Called(orderId, from, actor, reason)
+	return args.Get(0).(*models.Order), args.Error(1)
+}
+
+func (mock *OrderRepositoryMock) TransitionOrder(orderId int, from, to models.OrderStatus, actor string) (*models.OrderTransition, error) {
+	args := mock.Called(orderId, from, to, actor)
+	return args.Get(0).(*models.OrderTransition), args.Error(1)
+}
+
+func (mock *OrderRepositoryMock) ListOrderTransitions(orderId int) (*[]models.OrderTransition, error) {
+	args := mock.Called(orderId)
+	return args.Get(0).(*[]models.OrderTransition), args.Error(1)
+}
+
+func (mock *OrderRepositoryMock) ReleaseAbandonedOrders(createdBefore time.Time) ([]int, error) {
+	args := mock.Called(createdBefore)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+type StockRepositoryMock struct{ mock.Mock }
+
+func (mock *StockRepositoryMock) GetStock(productId int) (*models.Stock, error) {
+	args := mock.Called(productId)
+	return args.Get(0).(*models.Stock), args.Error(1)
+}
+
+func (mock *StockRepositoryMock) SetOnHand(productId, onHand int) (*models.Stock, error) {
+	args := mock.Called(productId, onHand)
+	return args.Get(0).(*models.Stock), args.Error(1)
+}
+
+type PaymentServiceMock struct{ mock.Mock }
+
+func (mock *PaymentServiceMock) PayOrder(clientId, orderId int) (response.PaymentResponse, error) {
+	args := mock.Called(clientId, orderId)
+	return args.Get(0).(response.PaymentResponse), args.Error(1)
+}
+
+func (mock *PaymentServiceMock) ConfirmPayment(clientId, orderId int) (response.PaymentResponse, error) {
+	args := mock.Called(clientId, orderId)
+	return args.Get(0).(response.PaymentResponse), args.Error(1)
+}
+
+func (mock *PaymentServiceMock) ReversePayment(orderId int) error {
+	args := mock.Called(orderId)
+	return args.Error(0)
+}
+
+func (mock *PaymentServiceMock) RefundPayment(orderId int) error {
+	args := mock.Called(orderId)
+	return args.Error(0)
+}
+
+type PaymentRepositoryMock struct{ mock.Mock }
+
+func (mock *PaymentRepositoryMock) FindPaymentByOrderId(orderId int) (*models.Payment, error) {
+	args := mock.Called(orderId)
+	return args.Get(0).(*models.Payment), args.Error(1)
+}
+
+func (mock *PaymentRepositoryMock) SavePayment(payment *models.Payment) error {
+	args := mock.Called(payment)
+	return args.Error(0)
+}
+
+type ShippingServiceMock struct{ mock.Mock }
+
+func (mock *ShippingServiceMock) GetShippingQuotes(clientId int, req request.ShippingQuotesRequest) (response.ShippingQuotesResponse, error) {
+	args := mock.Called(clientId, req)
+	return args.Get(0).(response.ShippingQuotesResponse), args.Error(1)
+}
+
+func (mock *ShippingServiceMock) SelectShippingOption(clientId, addressId int, option string) (models.OrderShipping, error) {
+	args := mock.Called(clientId, addressId, option)
+	return args.Get(0).(models.OrderShipping), args.Error(1)
+}
+
+type DownloadRepositoryMock struct{ mock.Mock }
+
+func (mock *DownloadRepositoryMock) FindOrderItem(orderId, productId int) (*models.OrderItem, error) {
+	args := mock.Called(orderId, productId)
+	return args.Get(0).(*models.OrderItem), args.Error(1)
+}
+
+func (mock *DownloadRepositoryMock) RecordDownload(download *models.Download, limit int) error {
+	args := mock.Called(download, limit)
+	return args.Error(0)
+}
+
+type AddressRepositoryMock struct{ mock.Mock }
+
+func (mock *AddressRepositoryMock) ListAddresses(clientId int) (*[]models.Address, error) {
+	args := mock.Called(clientId)
+	return args.Get(0).(*[]models.Address), args.Error(1)
+}
+
+func (mock *AddressRepositoryMock) FindAddress(clientId, addressId int) (*models.Address, error) {
+	args := mock.Called(clientId, addressId)
+	return args.Get(0).(*models.Address), args.Error(1)
+}
+
+func (mock *AddressRepositoryMock) FindDefaultShippingAddress(clientId int) (*models.Address, error) {
+	args := mock.Called(clientId)
+	return args.Get(0).(*models.Address), args.Error(1)
+}
+
+func (mock *AddressRepositoryMock) SaveAddress(address *models.Address) error {
+	args := mock.Called(address)
+	return args.Error(0)
+}
+
+func (mock *AddressRepositoryMock) DeleteAddress(address *models.Address) error {
+	args := mock.Called(address)
+	return args.Error(0)
+}
+
+func getValidDbAddress() models.Address {
+	return models.Address{
+		Id:       aValidAddressId,
+		ClientId: aValidClientId,
+		PostalAddress: models.PostalAddress{
+			Recipient:  "Ada Lovelace",
+			Line1:      "12 Analytical St",
+			City:       "Springfield",
+			Region:     "IL",
+			PostalCode: "62701",
+			Country:    "US",
+		},
+		DefaultShipping: true,
+		DefaultBilling:  true,
+	}
+}
+
+type TokenRepositoryMock struct{ mock.Mock }
+
+func (mock *TokenRepositoryMock) CreateRefreshToken(token *models.RefreshToken) error {
+	args := mock.Called(token)
+	return args.Error(0)
+}
+
+func (mock *TokenRepositoryMock) FindRefreshToken(tokenHash string) (*models.RefreshToken, error) {
+	args := mock.Called(tokenHash)
+	return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+
+func (mock *TokenRepositoryMock) RevokeRefreshToken(tokenId int) error {
+	args := mock.Called(tokenId)
+	return args.Error(0)
+}
+
+func (mock *TokenRepositoryMock) RevokeClientRefreshTokens(clientId int) error {
+	args := mock.Called(clientId)
+	return args.Error(0)
+}
+
+func (mock *TokenRepositoryMock) CreatePasswordResetToken(token *models.PasswordResetToken) error {
+	args := mock.Called(token)
+	return args.Error(0)
+}
+
+func (mock *TokenRepositoryMock) FindPasswordResetToken(tokenHash string) (*models.PasswordResetToken, error) {
+	args := mock.Called(tokenHash)
+	return args.Get(0).(*models.PasswordResetToken), args.Error(1)
+}
+
+func (mock *TokenRepositoryMock) UsePasswordResetToken(tokenId int) error {
+	args := mock.Called(tokenId)
+	return args.Error(0)
+}
+
+type ApiKeyRepositoryMock struct{ mock.Mock }
+
+func (mock *ApiKeyRepositoryMock) CreateApiKey(apiKey *models.ApiKey) error {
+	args := mock.Called(apiKey)
+	return args.Error(0)
+}
+
+func (mock *ApiKeyRepositoryMock) FindApiKeyByPrefix(prefix string) (*models.ApiKey, error) {
+	args := mock.Called(prefix)
+	return args.Get(0).(*models.ApiKey), args.Error(1)
+}
+
+func (mock *ApiKeyRepositoryMock) ListApiKeys() (*[]models.ApiKey, error) {
+	args := mock.Called()
+	return args.Get(0).(*[]models.ApiKey), args.Error(1)
+}
+
+func (mock *ApiKeyRepositoryMock) RevokeApiKey(apiKeyId int) error {
+	args := mock.Called(apiKeyId)
+	return args.Error(0)
+}
+
+func (mock *ApiKeyRepositoryMock) TouchApiKey(apiKeyId int, usedAt time.Time) error {
+	args := mock.Called(apiKeyId, usedAt)
+	return args.Error(0)
+}
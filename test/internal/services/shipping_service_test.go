@@ -0,0 +1,116 @@
+package services
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/services"
+	"github.com/emiliocc5/online-store-api/internal/shipping"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func getMockedShippingService(items []models.ProductCart) *services.ShippingServiceImpl {
+	cartMockRepository := &CartRepositoryMock{}
+	clientMockRepository := &ClientRepositoryMock{}
+	addressMockRepository := &AddressRepositoryMock{}
+
+	cart := getMockedValidCart()
+	clientMockRepository.On("IsClientInDataBase", aValidClientId).Return(true)
+	cartMockRepository.On("GetCartByClient", aValidClientId).Return(&cart, nil)
+	cartMockRepository.On("GetCartItems", aValidCartId).Return(&items, nil)
+
+	address := getValidDbAddress()
+	canadian := getValidDbAddress()
+	canadian.Id = aValidAddressId + 1
+	canadian.Country = "CA"
+	addressMockRepository.On("FindDefaultShippingAddress", aValidClientId).Return(&address, nil)
+	addressMockRepository.On("FindAddress", aValidClientId, aValidAddressId).Return(&address, nil)
+	addressMockRepository.On("FindAddress", aValidClientId, canadian.Id).Return(&canadian, nil)
+
+	calculator, _ := shipping.NewCalculator(shipping.DefaultRateTables(), []string{"USD"})
+	return &services.ShippingServiceImpl{
+		CartRepository:    cartMockRepository,
+		ClientRepository:  clientMockRepository,
+		AddressRepository: addressMockRepository,
+		Calculator:        calculator,
+		Zones:             shipping.DefaultCountryZones(),
+	}
+}
+
+func getDigitalDbProduct() models.Product {
+	return models.Product{Id: 7, Label: "E-book", Type: models.ProductTypeDigital, DownloadUrl: "https://example.com/ebook.pdf",
+		Price: models.Money{Amount: 999, Currency: "USD"}}
+}
+
+func Test_GivenAPhysicalCart_ThenQuoteShippingFromItsWeight(t *testing.T) {
+	ss := getMockedShippingService([]models.ProductCart{
+		{CartId: aValidCartId, ProductId: aValidProductId, Product: getValidDbProduct(), Quantity: 2},
+		{CartId: aValidCartId, ProductId: 7, Product: getDigitalDbProduct(), Quantity: 1},
+	})
+
+	resp, err := ss.GetShippingQuotes(aValidClientId, request.ShippingQuotesRequest{Zone: "domestic"})
+
+	assert.Nil(t, err)
+	assert.True(t, resp.ShippingRequired)
+	assert.Equal(t, 7.0, resp.Weight)
+	assert.Len(t, resp.Quotes, 2)
+	assert.Equal(t, "standard", resp.Quotes[0].Option)
+	assert.Equal(t, "14.99 USD", resp.Quotes[0].Cost.Formatted)
+	assert.Equal(t, "express", resp.Quotes[1].Option)
+	assert.Equal(t, "34.99 USD", resp.Quotes[1].Cost.Formatted)
+}
+
+func Test_GivenADigitalOnlyCart_ThenSkipShipping(t *testing.T) {
+	ss := getMockedShippingService([]models.ProductCart{{CartId: aValidCartId, ProductId: 7, Product: getDigitalDbProduct(), Quantity: 1}})
+
+	resp, err := ss.GetShippingQuotes(aValidClientId, request.ShippingQuotesRequest{})
+
+	assert.Nil(t, err)
+	assert.False(t, resp.ShippingRequired)
+	assert.Empty(t, resp.Quotes)
+
+	orderShipping, err := ss.SelectShippingOption(aValidClientId, 0, "express")
+
+	assert.Nil(t, err)
+	assert.True(t, orderShipping.IsEmpty())
+}
+
+func Test_GivenAPhysicalCart_AndNoZone_ThenQuoteForTheDefaultAddress(t *testing.T) {
+	ss := getMockedShippingService([]models.ProductCart{{CartId: aValidCartId, ProductId: aValidProductId, Product: getValidDbProduct(), Quantity: 1}})
+
+	resp, err := ss.GetShippingQuotes(aValidClientId, request.ShippingQuotesRequest{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "domestic", resp.Zone)
+}
+
+func Test_GivenAPhysicalCart_AndNoAddress_ThenUnableToQuote(t *testing.T) {
+	ss := getMockedShippingService([]models.ProductCart{{CartId: aValidCartId, ProductId: aValidProductId, Product: getValidDbProduct(), Quantity: 1}})
+	addressMockRepository := &AddressRepositoryMock{}
+	addressMockRepository.On("FindDefaultShippingAddress", aValidClientId).Return((*models.Address)(nil), nil)
+	ss.AddressRepository = addressMockRepository
+
+	_, err := ss.GetShippingQuotes(aValidClientId, request.ShippingQuotesRequest{})
+
+	assert.EqualError(t, err, "a zone or a shipping address is required")
+}
+
+func Test_GivenAPhysicalCart_ThenSelectShippingOptionForTheAddressCountry(t *testing.T) {
+	ss := getMockedShippingService([]models.ProductCart{{CartId: aValidCartId, ProductId: aValidProductId, Product: getValidDbProduct(), Quantity: 1}})
+
+	orderShipping, err := ss.SelectShippingOption(aValidClientId, aValidAddressId+1, "express")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "regional", orderShipping.Zone)
+	assert.Equal(t, 3.5, orderShipping.Weight)
+	assert.Equal(t, models.Money{Amount: 3499, Currency: "USD"}, orderShipping.Cost)
+	assert.Equal(t, "CA", orderShipping.Address.Country)
+}
+
+func Test_GivenAPhysicalCart_AndNoShippingOption_ThenUnableToSelect(t *testing.T) {
+	ss := getMockedShippingService([]models.ProductCart{{CartId: aValidCartId, ProductId: aValidProductId, Product: getValidDbProduct(), Quantity: 1}})
+
+	_, err := ss.SelectShippingOption(aValidClientId, aValidAddressId, "")
+
+	assert.EqualError(t, err, "a shipping address and option are required for carts with physical products")
+}
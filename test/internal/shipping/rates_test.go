@@ -0,0 +1,108 @@
+package shipping
+
+import (
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/shipping"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func getTestRateTables() []shipping.RateTable {
+	usd := func(amount int64) models.Money { return models.Money{Amount: amount, Currency: "USD"} }
+	return []shipping.RateTable{
+		{Option: "standard", Label: "Standard", Zone: "domestic", Days: 5, Rates: []shipping.Rate{{MaxWeight: 5, Cost: usd(900)}, {MaxWeight: 1, Cost: usd(500)}}},
+		{Option: "express", Label: "Express", Zone: "domestic", Days: 2, Rates: []shipping.Rate{{MaxWeight: 2, Cost: usd(1500)}}},
+	}
+}
+
+func Test_GivenAWeight_ThenQuoteEveryOptionCheapestFirst(t *testing.T) {
+	calculator, _ := shipping.NewCalculator(getTestRateTables(), []string{"USD"})
+
+	quotes, err := calculator.Quote("domestic", 0.5)
+
+	assert.Nil(t, err)
+	assert.Len(t, quotes, 2)
+	assert.Equal(t, "standard", quotes[0].Option)
+	assert.Equal(t, int64(500), quotes[0].Cost.Amount)
+	assert.Equal(t, "express", quotes[1].Option)
+}
+
+func Test_GivenAWeightAboveAnOption_ThenOmitThatOption(t *testing.T) {
+	calculator, _ := shipping.NewCalculator(getTestRateTables(), []string{"USD"})
+
+	quotes, err := calculator.Quote("domestic", 3)
+
+	assert.Nil(t, err)
+	assert.Len(t, quotes, 1)
+	assert.Equal(t, int64(900), quotes[0].Cost.Amount)
+
+	_, err = calculator.QuoteOption("domestic", "express", 3)
+
+	assert.True(t, errors.Is(err, shipping.ErrUnavailableOption))
+}
+
+func Test_GivenAnUnknownZone_ThenUnableToQuote(t *testing.T) {
+	calculator, _ := shipping.NewCalculator(getTestRateTables(), []string{"USD"})
+
+	_, err := calculator.Quote("moon", 1)
+
+	assert.True(t, errors.Is(err, shipping.ErrUnknownZone))
+	assert.EqualError(t, err, "unsupported shipping zone: moon")
+}
+
+func Test_GivenATooHeavyParcel_ThenUnableToQuote(t *testing.T) {
+	calculator, _ := shipping.NewCalculator(getTestRateTables(), []string{"USD"})
+
+	_, err := calculator.Quote("domestic", 12)
+
+	assert.True(t, errors.Is(err, shipping.ErrUnavailableOption))
+}
+
+func Test_GivenDuplicatedRateTables_ThenUnableToCreateCalculator(t *testing.T) {
+	tables := append(getTestRateTables(), getTestRateTables()[0])
+
+	_, err := shipping.NewCalculator(tables, []string{"USD"})
+
+	assert.EqualError(t, err, "duplicated shipping rate table: domestic/standard")
+}
+
+func Test_GivenARateInAnotherCurrency_ThenUnableToCreateCalculator(t *testing.T) {
+	_, err := shipping.NewCalculator(shipping.DefaultRateTables(), []string{"EUR"})
+
+	assert.EqualError(t, err, "the shipping rate table: domestic/standard is priced in USD, which is not one of the currencies: EUR")
+}
+
+func Test_GivenALowerCaseRateCurrency_ThenQuoteItUpperCased(t *testing.T) {
+	tables := getTestRateTables()
+	tables[1].Rates[0].Cost.Currency = "usd"
+	calculator, err := shipping.NewCalculator(tables, []string{"USD"})
+	assert.Nil(t, err)
+
+	quote, err := calculator.QuoteOption("domestic", "express", 1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "USD", quote.Cost.Currency)
+}
+
+func Test_DefaultRateTablesAreValid(t *testing.T) {
+	_, err := shipping.NewCalculator(shipping.DefaultRateTables(), []string{"USD"})
+
+	assert.Nil(t, err)
+}
+
+func Test_GivenZonesWithRateTables_ThenCheckZonesPasses(t *testing.T) {
+	calculator, _ := shipping.NewCalculator(getTestRateTables(), []string{"USD"})
+
+	err := calculator.CheckZones(shipping.CountryZones{Countries: map[string]string{"US": "domestic"}, Fallback: "domestic"})
+
+	assert.Nil(t, err)
+}
+
+func Test_GivenAZoneWithoutRateTable_ThenCheckZonesFails(t *testing.T) {
+	calculator, _ := shipping.NewCalculator(getTestRateTables(), []string{"USD"})
+
+	err := calculator.CheckZones(shipping.DefaultCountryZones())
+
+	assert.True(t, errors.Is(err, shipping.ErrUnknownZone))
+}
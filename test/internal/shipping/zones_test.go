@@ -0,0 +1,38 @@
+package shipping
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/shipping"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_GivenAListedCountry_ThenZoneForReturnsItsZone(t *testing.T) {
+	zones := shipping.DefaultCountryZones()
+
+	assert.Equal(t, shipping.ZoneDomestic, zones.ZoneFor("US"))
+	assert.Equal(t, shipping.ZoneRegional, zones.ZoneFor("ca"))
+}
+
+func Test_GivenAnUnlistedCountry_ThenZoneForReturnsTheFallback(t *testing.T) {
+	zones := shipping.DefaultCountryZones()
+
+	assert.Equal(t, shipping.ZoneInternational, zones.ZoneFor("AR"))
+}
+
+func Test_GivenLowerCaseCountries_ThenNormalizedUpperCasesThem(t *testing.T) {
+	zones := shipping.CountryZones{Countries: map[string]string{" es ": shipping.ZoneRegional}, Fallback: shipping.ZoneInternational}
+
+	normalized, err := zones.Normalized()
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"ES": shipping.ZoneRegional}, normalized.Countries)
+	assert.Equal(t, shipping.ZoneRegional, normalized.ZoneFor("es"))
+}
+
+func Test_GivenACountryListedTwice_ThenUnableToNormalize(t *testing.T) {
+	zones := shipping.CountryZones{Countries: map[string]string{"ES": shipping.ZoneRegional, "es": shipping.ZoneDomestic}}
+
+	_, err := zones.Normalized()
+
+	assert.EqualError(t, err, "duplicated shipping zone country: ES")
+}
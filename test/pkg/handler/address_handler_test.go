@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const (
+	aValidAddressId = 4
+)
+
+func Test_CreateAddress_Successful(t *testing.T) {
+	addressServiceMock := &AddressServiceMock{}
+
+	req := request.AddressRequest{Recipient: "Ada Lovelace", Line1: "12 Analytical St", City: "Springfield", Region: "IL", PostalCode: "62701", Country: "US"}
+	created := response.AddressResponse{Id: aValidAddressId, DefaultShipping: true, DefaultBilling: true}
+	addressServiceMock.On("CreateAddress", aValidClientId, req).Return(created, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequestWithBody(http.MethodPost, "/api/clients/me/addresses",
+		`{"recipient": "Ada Lovelace", "line1": "12 Analytical St", "city": "Springfield", "region": "IL", "postalCode": "62701", "country": "US"}`)
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.AddressHandlerImpl{AddressService: addressServiceMock}
+
+	handl.HandleCreateAddress(context)
+
+	var resp response.AddressResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, created, resp)
+}
+
+func Test_CreateAddress_WithInvalidPostalCode_ThenBadRequest(t *testing.T) {
+	addressServiceMock := &AddressServiceMock{}
+
+	req := request.AddressRequest{Recipient: "Ada Lovelace", Line1: "12 Analytical St", City: "Springfield", Region: "IL", PostalCode: "ABC", Country: "US"}
+	addressServiceMock.On("CreateAddress", aValidClientId, req).Return(response.AddressResponse{}, errors.New("invalid postal code for US: ABC"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequestWithBody(http.MethodPost, "/api/clients/me/addresses",
+		`{"recipient": "Ada Lovelace", "line1": "12 Analytical St", "city": "Springfield", "region": "IL", "postalCode": "ABC", "country": "US"}`)
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.AddressHandlerImpl{AddressService: addressServiceMock}
+
+	handl.HandleCreateAddress(context)
+
+	var resp response.ErrorResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "invalid postal code for US: ABC", resp.Error)
+}
+
+func Test_GetAddress_NotFound(t *testing.T) {
+	addressServiceMock := &AddressServiceMock{}
+
+	addressServiceMock.On("GetAddress", aValidClientId, aValidAddressId).Return(response.AddressResponse{}, errors.New("address with id: 4 not found"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/clients/me/addresses/4")
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "addressId", Value: "4"}}
+
+	handl := handler.AddressHandlerImpl{AddressService: addressServiceMock}
+
+	handl.HandleGetAddress(context)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func Test_DeleteAddress_Successful(t *testing.T) {
+	addressServiceMock := &AddressServiceMock{}
+
+	addressServiceMock.On("DeleteAddress", aValidClientId, aValidAddressId).Return(nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodDelete, "/api/clients/me/addresses/4")
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "addressId", Value: "4"}}
+
+	handl := handler.AddressHandlerImpl{AddressService: addressServiceMock}
+
+	handl.HandleDeleteAddress(context)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	addressServiceMock.AssertExpectations(t)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type ProductAdminServiceMock struct{ mock.Mock }
+
+func (mock *ProductAdminServiceMock) CreateProduct(req request.ProductRequest) (response.ProductResponse, error) {
+	args := mock.Called(req)
+	return args.Get(0).(response.ProductResponse), args.Error(1)
+}
+
+func (mock *ProductAdminServiceMock) UpdateProduct(productId int, req request.ProductRequest) (response.ProductResponse, error) {
+	args := mock.Called(productId, req)
+	return args.Get(0).(response.ProductResponse), args.Error(1)
+}
+
+func (mock *ProductAdminServiceMock) PatchProduct(productId int, req request.PatchProductRequest) (response.ProductResponse, error) {
+	args := mock.Called(productId, req)
+	return args.Get(0).(response.ProductResponse), args.Error(1)
+}
+
+func (mock *ProductAdminServiceMock) ArchiveProduct(productId int) error {
+	args := mock.Called(productId)
+	return args.Error(0)
+}
+
+type InventoryServiceMock struct{ mock.Mock }
+
+func (mock *InventoryServiceMock) GetStock(productId int) (response.StockResponse, error) {
+	args := mock.Called(productId)
+	return args.Get(0).(response.StockResponse), args.Error(1)
+}
+
+func (mock *InventoryServiceMock) SetStock(productId int, req request.SetStockRequest) (response.StockResponse, error) {
+	args := mock.Called(productId, req)
+	return args.Get(0).(response.StockResponse), args.Error(1)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_CreateProduct_Successful(t *testing.T) {
+	productAdminServiceMock := &ProductAdminServiceMock{}
+
+	req := request.ProductRequest{CategoryId: 1, Label: "Keyboard", Type: models.ProductTypePhysical, Weight: 3.5, Price: request.MoneyRequest{Amount: 4550, Currency: "USD"}}
+	productAdminServiceMock.On("CreateProduct", req).Return(response.ProductResponse{Id: aValidProductId, Label: "Keyboard"}, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/admin/products",
+		strings.NewReader(`{"categoryId": 1, "label": "Keyboard", "type": "physical", "weight": 3.5, "price": {"amount": 4550, "currency": "USD"}}`))
+
+	handl := handler.AdminProductHandlerImpl{ProductAdminService: productAdminServiceMock}
+
+	handl.HandleCreateProduct(context)
+
+	var resp response.ProductResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, aValidProductId, resp.Id)
+}
+
+func Test_CreateProduct_InvalidBody_ThenBadRequest(t *testing.T) {
+	productAdminServiceMock := &ProductAdminServiceMock{}
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/admin/products", strings.NewReader(`{"label": `))
+
+	handl := handler.AdminProductHandlerImpl{ProductAdminService: productAdminServiceMock}
+
+	handl.HandleCreateProduct(context)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	productAdminServiceMock.AssertNotCalled(t, "CreateProduct")
+}
+
+func Test_PatchProduct_InvalidInvariant_ThenBadRequest(t *testing.T) {
+	productAdminServiceMock := &ProductAdminServiceMock{}
+
+	weight := 0.0
+	productAdminServiceMock.On("PatchProduct", aValidProductId, request.PatchProductRequest{Weight: &weight}).
+		Return(response.ProductResponse{}, errors.New("physical products require a positive weight"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPatch, "/api/admin/products/2", strings.NewReader(`{"weight": 0}`))
+	context.Params = gin.Params{{Key: "productId", Value: strconv.Itoa(aValidProductId)}}
+
+	handl := handler.AdminProductHandlerImpl{ProductAdminService: productAdminServiceMock}
+
+	handl.HandlePatchProduct(context)
+
+	var resp response.ErrorResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "physical products require a positive weight", resp.Error)
+}
+
+func Test_ArchiveProduct_Successful(t *testing.T) {
+	productAdminServiceMock := &ProductAdminServiceMock{}
+
+	productAdminServiceMock.On("ArchiveProduct", aValidProductId).Return(nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodDelete, "/api/admin/products/2", nil)
+	context.Params = gin.Params{{Key: "productId", Value: strconv.Itoa(aValidProductId)}}
+
+	handl := handler.AdminProductHandlerImpl{ProductAdminService: productAdminServiceMock}
+
+	handl.HandleArchiveProduct(context)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	productAdminServiceMock.AssertCalled(t, "ArchiveProduct", aValidProductId)
+}
+
+func Test_CreateProduct_UnknownType_ThenBadRequest(t *testing.T) {
+	productAdminServiceMock := &ProductAdminServiceMock{}
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/admin/products", strings.NewReader(`{"label": "Keyboard", "type": "gadget"}`))
+
+	handl := handler.AdminProductHandlerImpl{ProductAdminService: productAdminServiceMock}
+
+	handl.HandleCreateProduct(context)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	productAdminServiceMock.AssertNotCalled(t, "CreateProduct")
+}
+
+func Test_SetStock_Successful(t *testing.T) {
+	inventoryServiceMock := &InventoryServiceMock{}
+
+	onHand := 12
+	stock := response.StockResponse{ProductId: aValidProductId, OnHand: 12, Reserved: 2, Available: 10}
+	inventoryServiceMock.On("SetStock", aValidProductId, request.SetStockRequest{OnHand: &onHand}).Return(stock, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPut, "/api/admin/products/2/stock", strings.NewReader(`{"onHand": 12}`))
+	context.Params = gin.Params{{Key: "productId", Value: strconv.Itoa(aValidProductId)}}
+
+	handl := handler.AdminProductHandlerImpl{InventoryService: inventoryServiceMock}
+
+	handl.HandleSetStock(context)
+
+	var resp response.StockResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, stock, resp)
+}
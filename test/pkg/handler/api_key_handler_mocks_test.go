@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type ApiKeyServiceMock struct{ mock.Mock }
+
+func (mock *ApiKeyServiceMock) CreateApiKey(req request.ApiKeyRequest) (response.CreatedApiKeyResponse, error) {
+	args := mock.Called(req)
+	return args.Get(0).(response.CreatedApiKeyResponse), args.Error(1)
+}
+
+func (mock *ApiKeyServiceMock) ListApiKeys() ([]response.ApiKeyResponse, error) {
+	args := mock.Called()
+	return args.Get(0).([]response.ApiKeyResponse), args.Error(1)
+}
+
+func (mock *ApiKeyServiceMock) RevokeApiKey(apiKeyId int) error {
+	args := mock.Called(apiKeyId)
+	return args.Error(0)
+}
+
+func (mock *ApiKeyServiceMock) Authenticate(key string) (*models.ApiKey, error) {
+	args := mock.Called(key)
+	return args.Get(0).(*models.ApiKey), args.Error(1)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_CreateApiKey_Successful(t *testing.T) {
+	apiKeyServiceMock := &ApiKeyServiceMock{}
+
+	created := response.CreatedApiKeyResponse{
+		ApiKeyResponse: response.ApiKeyResponse{Id: 1, Name: "warehouse", Prefix: "a1b2c3d4", Scopes: []string{"inventory:write"}},
+		Key:            "osk_a1b2c3d4_secret",
+	}
+	apiKeyServiceMock.On("CreateApiKey", request.ApiKeyRequest{Name: "warehouse", Scopes: []string{"inventory:write"}}).Return(created, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/admin/api-keys", strings.NewReader(`{"name": "warehouse", "scopes": ["inventory:write"]}`))
+
+	handl := handler.ApiKeyHandlerImpl{ApiKeyService: apiKeyServiceMock}
+
+	handl.HandleCreateApiKey(context)
+
+	var resp response.CreatedApiKeyResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, created, resp)
+}
+
+func Test_RevokeApiKey_AlreadyRevoked_ThenNotFound(t *testing.T) {
+	apiKeyServiceMock := &ApiKeyServiceMock{}
+
+	apiKeyServiceMock.On("RevokeApiKey", 1).Return(errors.New("active api key with id: 1 not found"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodDelete, "/api/admin/api-keys/1", nil)
+	context.Params = gin.Params{{Key: "apiKeyId", Value: "1"}}
+
+	handl := handler.ApiKeyHandlerImpl{ApiKeyService: apiKeyServiceMock}
+
+	handl.HandleRevokeApiKey(context)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_Login_Successful(t *testing.T) {
+	authServiceMock := &AuthServiceMock{}
+
+	tokens := response.TokenResponse{AccessToken: "access", TokenType: "Bearer", ExpiresIn: 900, RefreshToken: "refresh"}
+	authServiceMock.On("Login", request.LoginRequest{Email: "ada@example.com", Password: "correct horse"}).Return(tokens, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{"email": "ada@example.com", "password": "correct horse"}`))
+
+	handl := handler.AuthHandlerImpl{AuthService: authServiceMock}
+
+	handl.HandleLogin(context)
+
+	var resp response.TokenResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, tokens, resp)
+}
+
+func Test_Login_WithWrongPassword_ThenUnauthorized(t *testing.T) {
+	authServiceMock := &AuthServiceMock{}
+
+	authServiceMock.On("Login", request.LoginRequest{Email: "ada@example.com", Password: "wrong"}).Return(response.TokenResponse{}, auth.ErrInvalidCredentials)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/auth/login", strings.NewReader(`{"email": "ada@example.com", "password": "wrong"}`))
+
+	handl := handler.AuthHandlerImpl{AuthService: authServiceMock}
+
+	handl.HandleLogin(context)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func Test_Refresh_WithRevokedToken_ThenUnauthorized(t *testing.T) {
+	authServiceMock := &AuthServiceMock{}
+
+	authServiceMock.On("Refresh", request.RefreshTokenRequest{RefreshToken: "used"}).Return(response.TokenResponse{}, auth.ErrInvalidToken)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/auth/refresh", strings.NewReader(`{"refreshToken": "used"}`))
+
+	handl := handler.AuthHandlerImpl{AuthService: authServiceMock}
+
+	handl.HandleRefresh(context)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func Test_ResetPassword_WithAUsedToken_ThenUnauthorized(t *testing.T) {
+	authServiceMock := &AuthServiceMock{}
+
+	authServiceMock.On("ResetPassword", request.ResetPasswordRequest{ResetToken: "used", Password: "correct horse"}).Return(auth.ErrInvalidToken)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/auth/password-reset", strings.NewReader(`{"resetToken": "used", "password": "correct horse"}`))
+
+	handl := handler.AuthHandlerImpl{AuthService: authServiceMock}
+
+	handl.HandleResetPassword(context)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func Test_IssuePasswordReset_Successful(t *testing.T) {
+	authServiceMock := &AuthServiceMock{}
+
+	reset := response.PasswordResetResponse{ClientId: 4, ResetToken: "reset"}
+	authServiceMock.On("IssuePasswordReset", 4).Return(reset, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/admin/clients/4/password-reset", nil)
+	context.Params = gin.Params{{Key: "clientId", Value: "4"}}
+
+	handl := handler.AuthHandlerImpl{AuthService: authServiceMock}
+
+	handl.HandleIssuePasswordReset(context)
+
+	var resp response.PasswordResetResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, reset, resp)
+}
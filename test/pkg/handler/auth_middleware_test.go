@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/auth"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func getMockedTokenIssuer() *auth.TokenIssuer {
+	tokens, _ := auth.NewTokenIssuer([]byte("0123456789abcdef0123456789abcdef"), time.Minute)
+	return tokens
+}
+
+func getAuthenticatedEngine(tokens *auth.TokenIssuer, cartServiceMock *CartServiceMock) *gin.Engine {
+	engine := gin.New()
+	handl := handler.CartHandlerImpl{CartService: cartServiceMock}
+	engine.DELETE("/api/cart", handler.Authenticate(tokens), handl.HandleClearCart)
+	return engine
+}
+
+func Test_Authenticate_WithValidToken_ThenHandlerGetsTheClient(t *testing.T) {
+	tokens := getMockedTokenIssuer()
+	cartServiceMock := &CartServiceMock{}
+	cartServiceMock.On("ClearCart", aValidClientId).Return(nil)
+
+	token, _, _ := tokens.IssueAccessToken(aValidClientId, models.RoleCustomer, time.Now())
+	request := httptest.NewRequest(http.MethodDelete, "/api/cart", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+
+	getAuthenticatedEngine(tokens, cartServiceMock).ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	cartServiceMock.AssertExpectations(t)
+}
+
+func Test_Authenticate_WithClientIdHeaderOnly_ThenUnauthorized(t *testing.T) {
+	cartServiceMock := &CartServiceMock{}
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/cart", nil)
+	request.Header.Set("clientId", "1")
+	recorder := httptest.NewRecorder()
+
+	getAuthenticatedEngine(getMockedTokenIssuer(), cartServiceMock).ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	cartServiceMock.AssertNotCalled(t, "ClearCart", aValidClientId)
+}
+
+func Test_Authenticate_WithForgedToken_ThenUnauthorized(t *testing.T) {
+	cartServiceMock := &CartServiceMock{}
+	forger, _ := auth.NewTokenIssuer([]byte("fedcba9876543210fedcba9876543210"), time.Minute)
+
+	token, _, _ := forger.IssueAccessToken(aValidClientId, models.RoleCustomer, time.Now())
+	request := httptest.NewRequest(http.MethodDelete, "/api/cart", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+
+	getAuthenticatedEngine(getMockedTokenIssuer(), cartServiceMock).ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	cartServiceMock.AssertNotCalled(t, "ClearCart", aValidClientId)
+}
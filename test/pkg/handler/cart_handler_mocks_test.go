@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
 	"github.com/emiliocc5/online-store-api/internal/models/response"
 	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 )
 
 type CartServiceMock struct{ mock.Mock }
@@ -12,30 +17,87 @@ func (mock *CartServiceMock) AddProductToCart(productId, clientId int) error {
 	return args.Error(0)
 }
 
+func (mock *CartServiceMock) SetProductQuantity(productId, clientId, quantity int) error {
+	args := mock.Called(productId, clientId, quantity)
+	return args.Error(0)
+}
+
+func (mock *CartServiceMock) RemoveProductFromCart(productId, clientId int) error {
+	args := mock.Called(productId, clientId)
+	return args.Error(0)
+}
+
+func (mock *CartServiceMock) ClearCart(clientId int) error {
+	args := mock.Called(clientId)
+	return args.Error(0)
+}
+
 func (mock *CartServiceMock) GetCart(clientId int) (response.GetCartResponse, error) {
 	args := mock.Called(clientId)
 	return args.Get(0).(response.GetCartResponse), args.Error(1)
 }
 
 func getMockedValidCartResponse() response.GetCartResponse {
-	return response.GetCartResponse{Products: *getValidListOfProducts()}
-}
-
-func getValidListOfProducts() *[]response.ProductResponse {
-	var products []response.ProductResponse
-
 	product := getValidProduct()
-
-	products = append(products, product)
-	return &products
+	return response.GetCartResponse{
+		Products: []response.ProductLineResponse{{ProductResponse: product, Quantity: 1, Subtotal: product.Price}},
+		Subtotal: product.Price,
+	}
 }
 
 func getValidProduct() response.ProductResponse {
 	return response.ProductResponse{
-		Id:          1,
-		Label:       "Keyboard",
-		Type:        1,
-		DownloadUrl: "",
-		Weight:      3.5,
+		Id:       1,
+		Label:    "Keyboard",
+		Type:     "physical",
+		Price:    response.MoneyResponse{Amount: 4550, Currency: "USD", Formatted: "45.50 USD"},
+		Shipping: &response.ShippingDetailsResponse{Weight: 3.5},
 	}
 }
+
+func getMockedRequest(method, url string) *http.Request {
+	return getMockedRequestWithBody(method, url, "")
+}
+
+func getMockedRequestWithBody(method, url, body string) *http.Request {
+	return httptest.NewRequest(method, url, strings.NewReader(body))
+}
+
+type ShippingServiceMock struct{ mock.Mock }
+
+func (mock *ShippingServiceMock) GetShippingQuotes(clientId int, req request.ShippingQuotesRequest) (response.ShippingQuotesResponse, error) {
+	args := mock.Called(clientId, req)
+	return args.Get(0).(response.ShippingQuotesResponse), args.Error(1)
+}
+
+func (mock *ShippingServiceMock) SelectShippingOption(clientId, addressId int, option string) (models.OrderShipping, error) {
+	args := mock.Called(clientId, addressId, option)
+	return args.Get(0).(models.OrderShipping), args.Error(1)
+}
+
+type AddressServiceMock struct{ mock.Mock }
+
+func (mock *AddressServiceMock) ListAddresses(clientId int) ([]response.AddressResponse, error) {
+	args := mock.Called(clientId)
+	return args.Get(0).([]response.AddressResponse), args.Error(1)
+}
+
+func (mock *AddressServiceMock) GetAddress(clientId, addressId int) (response.AddressResponse, error) {
+	args := mock.Called(clientId, addressId)
+	return args.Get(0).(response.AddressResponse), args.Error(1)
+}
+
+func (mock *AddressServiceMock) CreateAddress(clientId int, req request.AddressRequest) (response.AddressResponse, error) {
+	args := mock.Called(clientId, req)
+	return args.Get(0).(response.AddressResponse), args.Error(1)
+}
+
+func (mock *AddressServiceMock) UpdateAddress(clientId, addressId int, req request.AddressRequest) (response.AddressResponse, error) {
+	args := mock.Called(clientId, addressId, req)
+	return args.Get(0).(response.AddressResponse), args.Error(1)
+}
+
+func (mock *AddressServiceMock) DeleteAddress(clientId, addressId int) error {
+	args := mock.Called(clientId, addressId)
+	return args.Error(0)
+}
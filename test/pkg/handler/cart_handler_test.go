@@ -1,15 +1,23 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
 	"github.com/emiliocc5/online-store-api/pkg/handler"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 )
 
 const (
-	aValidClientId = 1
+	aValidClientId  = 1
+	aValidProductId = 2
 )
 
 func Test_GetCart_Successful(t *testing.T) {
@@ -17,17 +25,201 @@ func Test_GetCart_Successful(t *testing.T) {
 
 	cartServiceMock.On("GetCart", aValidClientId).Return(getMockedValidCartResponse(), nil)
 
-	x := []string{"1"}
-	var header = make(map[string][]string)
-	header["clientId"] = x
-	request := http.Request{Header: header}
-	context := gin.Context{
-		Request: &request,
-	}
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/cart")
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.CartHandlerImpl{CartService: cartServiceMock}
+
+	handl.HandleGetCart(context)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func Test_GetClientCart_Successful(t *testing.T) {
+	cartServiceMock := &CartServiceMock{}
+
+	cartServiceMock.On("GetCart", aValidClientId).Return(getMockedValidCartResponse(), nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/admin/clients/1/cart")
+	context.Params = []gin.Param{{Key: "clientId", Value: "1"}}
+
+	handl := handler.CartHandlerImpl{CartService: cartServiceMock}
+
+	handl.HandleGetClientCart(context)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func Test_GetClientCart_WithInvalidClientId_ThenBadRequest(t *testing.T) {
+	cartServiceMock := &CartServiceMock{}
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/admin/clients/abc/cart")
+	context.Params = []gin.Param{{Key: "clientId", Value: "abc"}}
+
+	handl := handler.CartHandlerImpl{CartService: cartServiceMock}
+
+	handl.HandleGetClientCart(context)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	cartServiceMock.AssertNotCalled(t, "GetCart", aValidClientId)
+}
+
+func Test_SetProductQuantity_Successful(t *testing.T) {
+	cartServiceMock := &CartServiceMock{}
+
+	cartServiceMock.On("SetProductQuantity", aValidProductId, aValidClientId, 3).Return(nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequestWithBody(http.MethodPut, "/api/cart/products/2", `{"quantity": 3}`)
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "productId", Value: strconv.Itoa(aValidProductId)}}
+
+	handl := handler.CartHandlerImpl{CartService: cartServiceMock}
+
+	handl.HandleSetProductQuantity(context)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	cartServiceMock.AssertCalled(t, "SetProductQuantity", aValidProductId, aValidClientId, 3)
+}
+
+func Test_AddProduct_OutOfStock_ThenConflict(t *testing.T) {
+	cartServiceMock := &CartServiceMock{}
+
+	cartServiceMock.On("AddProductToCart", aValidProductId, aValidClientId).Return(models.NewInsufficientStockError(aValidProductId, 0))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodPost, "/api/cart/products/2")
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "productId", Value: strconv.Itoa(aValidProductId)}}
 
 	handl := handler.CartHandlerImpl{CartService: cartServiceMock}
 
-	handl.HandleGetCart(&context)
+	handl.HandleAddProduct(context)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func Test_SetProductQuantity_WithInvalidBody_ThenBadRequest(t *testing.T) {
+	cartServiceMock := &CartServiceMock{}
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequestWithBody(http.MethodPut, "/api/cart/products/2", `{"quantity": "many"}`)
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "productId", Value: strconv.Itoa(aValidProductId)}}
+
+	handl := handler.CartHandlerImpl{CartService: cartServiceMock}
+
+	handl.HandleSetProductQuantity(context)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	cartServiceMock.AssertNotCalled(t, "SetProductQuantity")
+}
+
+func Test_RemoveProduct_Successful(t *testing.T) {
+	cartServiceMock := &CartServiceMock{}
+
+	cartServiceMock.On("RemoveProductFromCart", aValidProductId, aValidClientId).Return(nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodDelete, "/api/cart/products/2")
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "productId", Value: strconv.Itoa(aValidProductId)}}
+
+	handl := handler.CartHandlerImpl{CartService: cartServiceMock}
+
+	handl.HandleRemoveProduct(context)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	cartServiceMock.AssertCalled(t, "RemoveProductFromCart", aValidProductId, aValidClientId)
+}
+
+func Test_RemoveProduct_NotInCart_ThenBadRequest(t *testing.T) {
+	cartServiceMock := &CartServiceMock{}
+
+	cartServiceMock.On("RemoveProductFromCart", aValidProductId, aValidClientId).Return(errors.New("product with id: 2 is not in the cart"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodDelete, "/api/cart/products/2")
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "productId", Value: strconv.Itoa(aValidProductId)}}
+
+	handl := handler.CartHandlerImpl{CartService: cartServiceMock}
+
+	handl.HandleRemoveProduct(context)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func Test_ClearCart_Successful(t *testing.T) {
+	cartServiceMock := &CartServiceMock{}
+
+	cartServiceMock.On("ClearCart", aValidClientId).Return(nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodDelete, "/api/cart")
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.CartHandlerImpl{CartService: cartServiceMock}
+
+	handl.HandleClearCart(context)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	cartServiceMock.AssertCalled(t, "ClearCart", aValidClientId)
+}
+
+func Test_GetShippingQuotes_Successful(t *testing.T) {
+	shippingServiceMock := &ShippingServiceMock{}
+
+	quotes := response.ShippingQuotesResponse{
+		ShippingRequired: true,
+		Zone:             "domestic",
+		Weight:           3.5,
+		Quotes: []response.ShippingQuoteResponse{
+			{Option: "standard", Label: "Standard", EstimatedDays: 5, Cost: response.MoneyResponse{Amount: 899, Currency: "USD", Formatted: "8.99 USD"}},
+		},
+	}
+	shippingServiceMock.On("GetShippingQuotes", aValidClientId, request.ShippingQuotesRequest{Zone: "domestic"}).Return(quotes, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/cart/shipping-quotes?zone=domestic")
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.CartHandlerImpl{ShippingService: shippingServiceMock}
+
+	handl.HandleGetShippingQuotes(context)
+
+	var resp response.ShippingQuotesResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, quotes, resp)
+}
+
+func Test_GetShippingQuotes_WithUnknownZone_ThenBadRequest(t *testing.T) {
+	shippingServiceMock := &ShippingServiceMock{}
+
+	shippingServiceMock.On("GetShippingQuotes", aValidClientId, request.ShippingQuotesRequest{Zone: "moon"}).Return(response.ShippingQuotesResponse{}, errors.New("unsupported shipping zone: moon"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/cart/shipping-quotes?zone=moon")
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.CartHandlerImpl{ShippingService: shippingServiceMock}
+
+	handl.HandleGetShippingQuotes(context)
 
-	assert.Equal(t, http.StatusOK, context.Status)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
 }
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	aValidCategoryId = 7
+)
+
+type CategoryServiceMock struct{ mock.Mock }
+
+func (mock *CategoryServiceMock) ListCategoryTree() ([]response.CategoryResponse, error) {
+	args := mock.Called()
+	return args.Get(0).([]response.CategoryResponse), args.Error(1)
+}
+
+func (mock *CategoryServiceMock) GetCategory(categoryId int) (response.CategoryResponse, error) {
+	args := mock.Called(categoryId)
+	return args.Get(0).(response.CategoryResponse), args.Error(1)
+}
+
+func (mock *CategoryServiceMock) CreateCategory(req request.CategoryRequest) (response.CategoryResponse, error) {
+	args := mock.Called(req)
+	return args.Get(0).(response.CategoryResponse), args.Error(1)
+}
+
+func (mock *CategoryServiceMock) UpdateCategory(categoryId int, req request.CategoryRequest) (response.CategoryResponse, error) {
+	args := mock.Called(categoryId, req)
+	return args.Get(0).(response.CategoryResponse), args.Error(1)
+}
+
+func (mock *CategoryServiceMock) DeleteCategory(categoryId int) error {
+	args := mock.Called(categoryId)
+	return args.Error(0)
+}
+
+func (mock *CategoryServiceMock) ListCategoryProducts(categoryId int, filter models.ProductFilter) (response.ListProductsResponse, error) {
+	args := mock.Called(categoryId, filter)
+	return args.Get(0).(response.ListProductsResponse), args.Error(1)
+}
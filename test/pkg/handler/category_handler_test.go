@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_ListCategories_Successful(t *testing.T) {
+	categoryServiceMock := &CategoryServiceMock{}
+
+	tree := []response.CategoryResponse{{Id: 1, Label: "Electronics", Children: []response.CategoryResponse{{Id: 2, Label: "Computers"}}}}
+	categoryServiceMock.On("ListCategoryTree").Return(tree, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/categories", nil)
+
+	handl := handler.CategoryHandlerImpl{CategoryService: categoryServiceMock}
+
+	handl.HandleListCategories(context)
+
+	var resp []response.CategoryResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, tree, resp)
+}
+
+func Test_GetCategory_NotFound(t *testing.T) {
+	categoryServiceMock := &CategoryServiceMock{}
+
+	categoryServiceMock.On("GetCategory", aValidCategoryId).Return(response.CategoryResponse{}, errors.New("category with id: 7 not found"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/categories/7", nil)
+	context.Params = gin.Params{{Key: "categoryId", Value: strconv.Itoa(aValidCategoryId)}}
+
+	handl := handler.CategoryHandlerImpl{CategoryService: categoryServiceMock}
+
+	handl.HandleGetCategory(context)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func Test_CreateCategory_Successful(t *testing.T) {
+	categoryServiceMock := &CategoryServiceMock{}
+
+	categoryServiceMock.On("CreateCategory", request.CategoryRequest{Label: "Books"}).Return(response.CategoryResponse{Id: aValidCategoryId, Label: "Books"}, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/admin/categories", strings.NewReader(`{"label": "Books"}`))
+
+	handl := handler.CategoryHandlerImpl{CategoryService: categoryServiceMock}
+
+	handl.HandleCreateCategory(context)
+
+	var resp response.CategoryResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, aValidCategoryId, resp.Id)
+}
+
+func Test_DeleteCategory_WithSubcategories_ThenBadRequest(t *testing.T) {
+	categoryServiceMock := &CategoryServiceMock{}
+
+	categoryServiceMock.On("DeleteCategory", aValidCategoryId).Return(errors.New("category with id: 7 has subcategories"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodDelete, "/api/admin/categories/7", nil)
+	context.Params = gin.Params{{Key: "categoryId", Value: strconv.Itoa(aValidCategoryId)}}
+
+	handl := handler.CategoryHandlerImpl{CategoryService: categoryServiceMock}
+
+	handl.HandleDeleteCategory(context)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func Test_ListCategoryProducts_Successful(t *testing.T) {
+	categoryServiceMock := &CategoryServiceMock{}
+
+	listResponse := response.ListProductsResponse{Products: []response.ProductResponse{getValidProduct()}}
+	categoryServiceMock.On("ListCategoryProducts", aValidCategoryId, models.ProductFilter{Sort: "label"}).Return(listResponse, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/categories/7/products?sort=label", nil)
+	context.Params = gin.Params{{Key: "categoryId", Value: strconv.Itoa(aValidCategoryId)}}
+
+	handl := handler.CategoryHandlerImpl{CategoryService: categoryServiceMock}
+
+	handl.HandleListCategoryProducts(context)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	categoryServiceMock.AssertCalled(t, "ListCategoryProducts", aValidCategoryId, models.ProductFilter{Sort: "label"})
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type ClientServiceMock struct{ mock.Mock }
+
+func (mock *ClientServiceMock) SignUp(req request.SignUpRequest) (response.ClientResponse, error) {
+	args := mock.Called(req)
+	return args.Get(0).(response.ClientResponse), args.Error(1)
+}
+
+func (mock *ClientServiceMock) GetProfile(clientId int) (response.ClientResponse, error) {
+	args := mock.Called(clientId)
+	return args.Get(0).(response.ClientResponse), args.Error(1)
+}
+
+func (mock *ClientServiceMock) UpdateProfile(clientId int, req request.ClientRequest) (response.ClientResponse, error) {
+	args := mock.Called(clientId, req)
+	return args.Get(0).(response.ClientResponse), args.Error(1)
+}
+
+func (mock *ClientServiceMock) DeleteAccount(clientId int) error {
+	args := mock.Called(clientId)
+	return args.Error(0)
+}
+
+type AuthServiceMock struct{ mock.Mock }
+
+func (mock *AuthServiceMock) Login(req request.LoginRequest) (response.TokenResponse, error) {
+	args := mock.Called(req)
+	return args.Get(0).(response.TokenResponse), args.Error(1)
+}
+
+func (mock *AuthServiceMock) Refresh(req request.RefreshTokenRequest) (response.TokenResponse, error) {
+	args := mock.Called(req)
+	return args.Get(0).(response.TokenResponse), args.Error(1)
+}
+
+func (mock *AuthServiceMock) Logout(req request.RefreshTokenRequest) error {
+	args := mock.Called(req)
+	return args.Error(0)
+}
+
+func (mock *AuthServiceMock) IssuePasswordReset(clientId int) (response.PasswordResetResponse, error) {
+	args := mock.Called(clientId)
+	return args.Get(0).(response.PasswordResetResponse), args.Error(1)
+}
+
+func (mock *AuthServiceMock) ResetPassword(req request.ResetPasswordRequest) error {
+	args := mock.Called(req)
+	return args.Error(0)
+}
+
+func (mock *ClientServiceMock) SetRole(clientId int, req request.SetRoleRequest) (response.ClientResponse, error) {
+	args := mock.Called(clientId, req)
+	return args.Get(0).(response.ClientResponse), args.Error(1)
+}
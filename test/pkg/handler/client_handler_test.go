@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_SignUp_Successful(t *testing.T) {
+	clientServiceMock := &ClientServiceMock{}
+
+	req := request.SignUpRequest{ClientRequest: request.ClientRequest{Name: "Ada Lovelace", Email: "ada@example.com"}, Password: "correct horse"}
+	created := response.ClientResponse{Id: aValidClientId, Name: "Ada Lovelace", Email: "ada@example.com"}
+	clientServiceMock.On("SignUp", req).Return(created, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/clients", strings.NewReader(`{"name": "Ada Lovelace", "email": "ada@example.com", "password": "correct horse"}`))
+
+	handl := handler.ClientHandlerImpl{ClientService: clientServiceMock}
+
+	handl.HandleSignUp(context)
+
+	var resp response.ClientResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, created, resp)
+}
+
+func Test_SignUp_WithRegisteredEmail_ThenConflict(t *testing.T) {
+	clientServiceMock := &ClientServiceMock{}
+
+	req := request.SignUpRequest{ClientRequest: request.ClientRequest{Name: "Ada Lovelace", Email: "ada@example.com"}, Password: "correct horse"}
+	clientServiceMock.On("SignUp", req).Return(response.ClientResponse{}, models.NewEmailTakenError("ada@example.com"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/clients", strings.NewReader(`{"name": "Ada Lovelace", "email": "ada@example.com", "password": "correct horse"}`))
+
+	handl := handler.ClientHandlerImpl{ClientService: clientServiceMock}
+
+	handl.HandleSignUp(context)
+
+	var resp response.ErrorResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "email already registered: ada@example.com", resp.Error)
+}
+
+func Test_GetProfile_NotFound(t *testing.T) {
+	clientServiceMock := &ClientServiceMock{}
+
+	clientServiceMock.On("GetProfile", aValidClientId).Return(response.ClientResponse{}, errors.New("client with id: 1 not found"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/clients/me")
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.ClientHandlerImpl{ClientService: clientServiceMock}
+
+	handl.HandleGetProfile(context)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func Test_DeleteAccount_Successful(t *testing.T) {
+	clientServiceMock := &ClientServiceMock{}
+
+	clientServiceMock.On("DeleteAccount", aValidClientId).Return(nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodDelete, "/api/clients/me")
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.ClientHandlerImpl{ClientService: clientServiceMock}
+
+	handl.HandleDeleteAccount(context)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	clientServiceMock.AssertExpectations(t)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/downloads"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func getDownloadParams() gin.Params {
+	return gin.Params{{Key: "orderId", Value: strconv.Itoa(aValidOrderId)}, {Key: "productId", Value: strconv.Itoa(aValidProductId)}}
+}
+
+func Test_IssueDownloadLink_Successful(t *testing.T) {
+	downloadServiceMock := &DownloadServiceMock{}
+
+	link := response.DownloadLinkResponse{OrderId: aValidOrderId, ProductId: aValidProductId, Url: "/api/downloads/10/2?expires=1&signature=abc", RemainingDownloads: 5}
+	downloadServiceMock.On("IssueDownloadLink", aValidClientId, aValidOrderId, aValidProductId).Return(link, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/orders/10/downloads/2")
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = getDownloadParams()
+
+	handl := handler.DownloadHandlerImpl{DownloadService: downloadServiceMock}
+
+	handl.HandleIssueDownloadLink(context)
+
+	var resp response.DownloadLinkResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, link.Url, resp.Url)
+}
+
+func Test_IssueDownloadLink_ForUnpaidOrder_ThenForbidden(t *testing.T) {
+	downloadServiceMock := &DownloadServiceMock{}
+
+	downloadServiceMock.On("IssueDownloadLink", aValidClientId, aValidOrderId, aValidProductId).
+		Return(response.DownloadLinkResponse{}, fmt.Errorf("%w: the order: 10 is pending", downloads.ErrDownloadNotAllowed))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/orders/10/downloads/2")
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = getDownloadParams()
+
+	handl := handler.DownloadHandlerImpl{DownloadService: downloadServiceMock}
+
+	handl.HandleIssueDownloadLink(context)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func Test_Download_ThenRedirectToFile(t *testing.T) {
+	downloadServiceMock := &DownloadServiceMock{}
+
+	req := request.RedeemDownloadRequest{OrderId: aValidOrderId, ProductId: aValidProductId, Expires: 1646129700, Signature: "abc",
+		IpAddress: "192.0.2.1", UserAgent: "curl"}
+	downloadServiceMock.On("RedeemDownloadLink", req).Return("https://downloads.example.com/editor.zip", nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/downloads/10/2?expires=1646129700&signature=abc", nil)
+	context.Request.Header.Set("User-Agent", "curl")
+	context.Params = getDownloadParams()
+
+	handl := handler.DownloadHandlerImpl{DownloadService: downloadServiceMock}
+
+	handl.HandleDownload(context)
+
+	assert.Equal(t, http.StatusFound, recorder.Code)
+	assert.Equal(t, "https://downloads.example.com/editor.zip", recorder.Header().Get("Location"))
+}
+
+func Test_Download_OverTheLimit_ThenForbidden(t *testing.T) {
+	downloadServiceMock := &DownloadServiceMock{}
+
+	downloadServiceMock.On("RedeemDownloadLink", request.RedeemDownloadRequest{OrderId: aValidOrderId, ProductId: aValidProductId,
+		Expires: 1646129700, Signature: "abc", IpAddress: "192.0.2.1"}).
+		Return("", fmt.Errorf("%w: the product: 2 of the order: 10 can be downloaded 5 times", models.ErrDownloadLimitReached))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/downloads/10/2?expires=1646129700&signature=abc", nil)
+	context.Params = getDownloadParams()
+
+	handl := handler.DownloadHandlerImpl{DownloadService: downloadServiceMock}
+
+	handl.HandleDownload(context)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func Test_Download_WithoutExpiry_ThenForbidden(t *testing.T) {
+	downloadServiceMock := &DownloadServiceMock{}
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/downloads/10/2?signature=abc", nil)
+	context.Params = getDownloadParams()
+
+	handl := handler.DownloadHandlerImpl{DownloadService: downloadServiceMock}
+
+	handl.HandleDownload(context)
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	downloadServiceMock.AssertNotCalled(t, "RedeemDownloadLink")
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	aValidOrderId = 10
+)
+
+type OrderServiceMock struct{ mock.Mock }
+
+func (mock *OrderServiceMock) CreateOrder(clientId int, req request.CreateOrderRequest) (response.CreateOrderResponse, error) {
+	args := mock.Called(clientId, req)
+	return args.Get(0).(response.CreateOrderResponse), args.Error(1)
+}
+
+func (mock *OrderServiceMock) GetProductsFromOrder(clientId, orderId int) (response.GetOrderProductsResponse, error) {
+	args := mock.Called(clientId, orderId)
+	return args.Get(0).(response.GetOrderProductsResponse), args.Error(1)
+}
+
+func getMockedValidOrderProductsResponse() response.GetOrderProductsResponse {
+	product := getValidProduct()
+	return response.GetOrderProductsResponse{
+		Products: []response.ProductLineResponse{{ProductResponse: product, Quantity: 1, Subtotal: product.Price}},
+		Total:    product.Price,
+	}
+}
+
+func (mock *OrderServiceMock) ListOrdersForClient(clientId, page, pageSize int) (response.ListOrdersResponse, error) {
+	args := mock.Called(clientId, page, pageSize)
+	return args.Get(0).(response.ListOrdersResponse), args.Error(1)
+}
+
+func (mock *OrderServiceMock) ReleaseAbandonedOrders() (int, error) {
+	args := mock.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (mock *OrderServiceMock) TransitionOrder(orderId int, actor string, req request.OrderTransitionRequest) (response.OrderTransitionResponse, error) {
+	args := mock.Called(orderId, actor, req)
+	return args.Get(0).(response.OrderTransitionResponse), args.Error(1)
+}
+
+func (mock *OrderServiceMock) ListOrderTransitions(orderId int) ([]response.OrderTransitionResponse, error) {
+	args := mock.Called(orderId)
+	return args.Get(0).([]response.OrderTransitionResponse), args.Error(1)
+}
+
+func (mock *OrderServiceMock) CancelOrder(clientId, orderId int, req request.CancelOrderRequest) (response.CancelOrderResponse, error) {
+	args := mock.Called(clientId, orderId, req)
+	return args.Get(0).(response.CancelOrderResponse), args.Error(1)
+}
+
+type PaymentServiceMock struct{ mock.Mock }
+
+func (mock *PaymentServiceMock) PayOrder(clientId, orderId int) (response.PaymentResponse, error) {
+	args := mock.Called(clientId, orderId)
+	return args.Get(0).(response.PaymentResponse), args.Error(1)
+}
+
+func (mock *PaymentServiceMock) ConfirmPayment(clientId, orderId int) (response.PaymentResponse, error) {
+	args := mock.Called(clientId, orderId)
+	return args.Get(0).(response.PaymentResponse), args.Error(1)
+}
+
+func (mock *PaymentServiceMock) ReversePayment(orderId int) error {
+	args := mock.Called(orderId)
+	return args.Error(0)
+}
+
+func (mock *PaymentServiceMock) RefundPayment(orderId int) error {
+	args := mock.Called(orderId)
+	return args.Error(0)
+}
+
+type DownloadServiceMock struct{ mock.Mock }
+
+func (mock *DownloadServiceMock) IssueDownloadLink(clientId, orderId, productId int) (response.DownloadLinkResponse, error) {
+	args := mock.Called(clientId, orderId, productId)
+	return args.Get(0).(response.DownloadLinkResponse), args.Error(1)
+}
+
+func (mock *DownloadServiceMock) RedeemDownloadLink(req request.RedeemDownloadRequest) (string, error) {
+	args := mock.Called(req)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/request"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/internal/payment"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_CreateOrder_Successful(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	orderServiceMock.On("CreateOrder", aValidClientId, request.CreateOrderRequest{}).Return(response.CreateOrderResponse{OrderId: aValidOrderId}, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodPost, "/api/orders")
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleCreateOrder(context)
+
+	var resp response.CreateOrderResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, aValidOrderId, resp.OrderId)
+	orderServiceMock.AssertNumberOfCalls(t, "CreateOrder", 1)
+}
+
+func Test_CreateOrder_WithShippingOption_Successful(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	req := request.CreateOrderRequest{ShippingAddressId: 3, ShippingOption: "express"}
+	orderServiceMock.On("CreateOrder", aValidClientId, req).Return(response.CreateOrderResponse{OrderId: aValidOrderId}, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequestWithBody(http.MethodPost, "/api/orders", `{"shippingAddressId": 3, "shippingOption": "express"}`)
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleCreateOrder(context)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	orderServiceMock.AssertCalled(t, "CreateOrder", aValidClientId, req)
+}
+
+func Test_CreateOrder_WithInvalidBody_ThenBadRequest(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequestWithBody(http.MethodPost, "/api/orders", `{"shippingAddressId": "home"}`)
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleCreateOrder(context)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	orderServiceMock.AssertNotCalled(t, "CreateOrder")
+}
+
+func Test_CreateOrder_WithEmptyCart_ThenBadRequest(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	orderServiceMock.On("CreateOrder", aValidClientId, request.CreateOrderRequest{}).Return(response.CreateOrderResponse{}, errors.New("the cart has no products"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodPost, "/api/orders")
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleCreateOrder(context)
+
+	var resp response.ErrorResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "the cart has no products", resp.Error)
+}
+
+func Test_CreateOrder_WithoutEnoughStock_ThenConflict(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	orderServiceMock.On("CreateOrder", aValidClientId, request.CreateOrderRequest{}).Return(response.CreateOrderResponse{}, models.NewInsufficientStockError(aValidProductId, 0))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodPost, "/api/orders")
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleCreateOrder(context)
+
+	var resp response.ErrorResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+	assert.Equal(t, "insufficient stock: the product: 2 has 0 units available", resp.Error)
+}
+
+func Test_GetOrder_Successful(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	orderServiceMock.On("GetProductsFromOrder", aValidClientId, aValidOrderId).Return(getMockedValidOrderProductsResponse(), nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/orders/"+strconv.Itoa(aValidOrderId))
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "orderId", Value: strconv.Itoa(aValidOrderId)}}
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleGetOrder(context)
+
+	var resp response.GetOrderProductsResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, getMockedValidOrderProductsResponse(), resp)
+	orderServiceMock.AssertCalled(t, "GetProductsFromOrder", aValidClientId, aValidOrderId)
+}
+
+func Test_GetOrder_NotFound_ThenBadRequest(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	orderServiceMock.On("GetProductsFromOrder", aValidClientId, aValidOrderId).Return(response.GetOrderProductsResponse{}, errors.New("order not found"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/orders/"+strconv.Itoa(aValidOrderId))
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "orderId", Value: strconv.Itoa(aValidOrderId)}}
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleGetOrder(context)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func Test_ListOrders_Successful(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	listResponse := response.ListOrdersResponse{
+		Orders:     []response.OrderSummaryResponse{{Id: aValidOrderId, ItemCount: 1}},
+		Pagination: response.PaginationResponse{Page: 2, PageSize: 5, TotalItems: 6, TotalPages: 2},
+	}
+	orderServiceMock.On("ListOrdersForClient", aValidClientId, 2, 5).Return(listResponse, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/orders?page=2&pageSize=5")
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleListOrders(context)
+
+	var resp response.ListOrdersResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, listResponse.Pagination, resp.Pagination)
+	assert.Equal(t, aValidOrderId, resp.Orders[0].Id)
+}
+
+func Test_ListOrders_WithInvalidPage_ThenBadRequest(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodGet, "/api/orders?page=first")
+	context.Set(handler.ClientIdKey, aValidClientId)
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleListOrders(context)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	orderServiceMock.AssertNotCalled(t, "ListOrdersForClient")
+}
+
+func Test_TransitionOrder_Successful(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	req := request.OrderTransitionRequest{Status: models.OrderStatusShipped}
+	transition := response.OrderTransitionResponse{OrderId: aValidOrderId, From: "fulfilled", To: "shipped", Actor: "client:7 (support)"}
+	orderServiceMock.On("TransitionOrder", aValidOrderId, "client:7 (support)", req).Return(transition, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/orders/10/transitions", strings.NewReader(`{"status": "shipped", "actor": "someone else"}`))
+	context.Params = gin.Params{{Key: "orderId", Value: strconv.Itoa(aValidOrderId)}}
+	context.Set(handler.ClientIdKey, 7)
+	context.Set(handler.RoleKey, models.RoleSupport)
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleTransitionOrder(context)
+
+	var resp response.OrderTransitionResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, transition, resp)
+}
+
+func Test_TransitionOrder_NotAllowed_ThenConflict(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	req := request.OrderTransitionRequest{Status: models.OrderStatusPaid}
+	orderServiceMock.On("TransitionOrder", aValidOrderId, "api-key:osk_abcd", req).
+		Return(response.OrderTransitionResponse{}, models.NewInvalidOrderTransitionError(models.OrderStatusDelivered, models.OrderStatusPaid))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/orders/10/transitions", strings.NewReader(`{"status": "paid"}`))
+	context.Params = gin.Params{{Key: "orderId", Value: strconv.Itoa(aValidOrderId)}}
+	context.Set(handler.ApiKeyPrefixKey, "osk_abcd")
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleTransitionOrder(context)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func Test_CancelOrder_Successful(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	cancelled := response.CancelOrderResponse{OrderId: aValidOrderId, Status: "cancelled", Reason: "changed my mind"}
+	orderServiceMock.On("CancelOrder", aValidClientId, aValidOrderId, request.CancelOrderRequest{Reason: "changed my mind"}).Return(cancelled, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequestWithBody(http.MethodPost, "/api/orders/10/cancel", `{"reason": "changed my mind"}`)
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "orderId", Value: strconv.Itoa(aValidOrderId)}}
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleCancelOrder(context)
+
+	var resp response.CancelOrderResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, cancelled, resp)
+}
+
+func Test_CancelOrder_AlreadyShipped_ThenConflict(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	orderServiceMock.On("CancelOrder", aValidClientId, aValidOrderId, request.CancelOrderRequest{Reason: "too late"}).
+		Return(response.CancelOrderResponse{}, models.NewInvalidOrderTransitionError(models.OrderStatusShipped, models.OrderStatusCancelled))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequestWithBody(http.MethodPost, "/api/orders/10/cancel", `{"reason": "too late"}`)
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "orderId", Value: strconv.Itoa(aValidOrderId)}}
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleCancelOrder(context)
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func Test_PayOrder_Successful(t *testing.T) {
+	paymentServiceMock := &PaymentServiceMock{}
+
+	paid := response.PaymentResponse{OrderId: aValidOrderId, OrderStatus: "paid", Status: "captured", AuthorizationId: "fake_auth_000001"}
+	paymentServiceMock.On("PayOrder", aValidClientId, aValidOrderId).Return(paid, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodPost, "/api/orders/10/payment")
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "orderId", Value: strconv.Itoa(aValidOrderId)}}
+
+	handl := handler.OrderHandlerImpl{PaymentService: paymentServiceMock}
+
+	handl.HandlePayOrder(context)
+
+	var resp response.PaymentResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, paid, resp)
+}
+
+func Test_PayOrder_Declined_ThenPaymentRequired(t *testing.T) {
+	paymentServiceMock := &PaymentServiceMock{}
+
+	paymentServiceMock.On("PayOrder", aValidClientId, aValidOrderId).
+		Return(response.PaymentResponse{}, fmt.Errorf("%w: the card was declined for order: %v", payment.ErrPaymentDeclined, aValidOrderId))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodPost, "/api/orders/10/payment")
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "orderId", Value: strconv.Itoa(aValidOrderId)}}
+
+	handl := handler.OrderHandlerImpl{PaymentService: paymentServiceMock}
+
+	handl.HandlePayOrder(context)
+
+	assert.Equal(t, http.StatusPaymentRequired, recorder.Code)
+}
+
+func Test_ConfirmPayment_TimedOut_ThenGatewayTimeout(t *testing.T) {
+	paymentServiceMock := &PaymentServiceMock{}
+
+	paymentServiceMock.On("ConfirmPayment", aValidClientId, aValidOrderId).
+		Return(response.PaymentResponse{}, fmt.Errorf("%w: no answer for order: %v", payment.ErrPaymentTimeout, aValidOrderId))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = getMockedRequest(http.MethodPost, "/api/orders/10/payment/confirm")
+	context.Set(handler.ClientIdKey, aValidClientId)
+	context.Params = gin.Params{{Key: "orderId", Value: strconv.Itoa(aValidOrderId)}}
+
+	handl := handler.OrderHandlerImpl{PaymentService: paymentServiceMock}
+
+	handl.HandleConfirmPayment(context)
+
+	assert.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+}
+
+func Test_TransitionOrder_WithoutPrincipal_ThenUnauthorized(t *testing.T) {
+	orderServiceMock := &OrderServiceMock{}
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodPost, "/api/orders/10/transitions", strings.NewReader(`{"status": "paid", "actor": "system"}`))
+	context.Params = gin.Params{{Key: "orderId", Value: strconv.Itoa(aValidOrderId)}}
+
+	handl := handler.OrderHandlerImpl{OrderService: orderServiceMock}
+
+	handl.HandleTransitionOrder(context)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	orderServiceMock.AssertNotCalled(t, "TransitionOrder")
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/stretchr/testify/mock"
+)
+
+type CatalogServiceMock struct{ mock.Mock }
+
+func (mock *CatalogServiceMock) ListProducts(filter models.ProductFilter) (response.ListProductsResponse, error) {
+	args := mock.Called(filter)
+	return args.Get(0).(response.ListProductsResponse), args.Error(1)
+}
+
+func (mock *CatalogServiceMock) GetProduct(productId int) (response.ProductResponse, error) {
+	args := mock.Called(productId)
+	return args.Get(0).(response.ProductResponse), args.Error(1)
+}
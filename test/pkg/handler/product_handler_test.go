@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/emiliocc5/online-store-api/internal/models"
+	"github.com/emiliocc5/online-store-api/internal/models/response"
+	"github.com/emiliocc5/online-store-api/pkg/handler"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func Test_ListProducts_WithFilters_Successful(t *testing.T) {
+	catalogServiceMock := &CatalogServiceMock{}
+
+	productType := models.ProductTypeDigital
+	expectedFilter := models.ProductFilter{CategoryIds: []int{3}, Type: &productType, Label: "book", Sort: "-price", Page: 2, PageSize: 10}
+	listResponse := response.ListProductsResponse{
+		Products:   []response.ProductResponse{getValidProduct()},
+		Pagination: response.PaginationResponse{Page: 2, PageSize: 10, TotalItems: 11, TotalPages: 2},
+	}
+	catalogServiceMock.On("ListProducts", expectedFilter).Return(listResponse, nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/products?categoryId=3&type=digital&label=book&sort=-price&page=2&pageSize=10", nil)
+
+	handl := handler.ProductHandlerImpl{CatalogService: catalogServiceMock}
+
+	handl.HandleListProducts(context)
+
+	var resp response.ListProductsResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, listResponse, resp)
+}
+
+func Test_ListProducts_WithInvalidCategory_ThenBadRequest(t *testing.T) {
+	catalogServiceMock := &CatalogServiceMock{}
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/products?categoryId=books", nil)
+
+	handl := handler.ProductHandlerImpl{CatalogService: catalogServiceMock}
+
+	handl.HandleListProducts(context)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	catalogServiceMock.AssertNotCalled(t, "ListProducts")
+}
+
+func Test_ListProducts_WithUnknownType_ThenBadRequest(t *testing.T) {
+	catalogServiceMock := &CatalogServiceMock{}
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/products?type=2", nil)
+
+	handl := handler.ProductHandlerImpl{CatalogService: catalogServiceMock}
+
+	handl.HandleListProducts(context)
+
+	var resp response.ErrorResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "unsupported product type: 2", resp.Error)
+	catalogServiceMock.AssertNotCalled(t, "ListProducts")
+}
+
+func Test_GetProduct_Successful(t *testing.T) {
+	catalogServiceMock := &CatalogServiceMock{}
+
+	catalogServiceMock.On("GetProduct", aValidProductId).Return(getValidProduct(), nil)
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/products/2", nil)
+	context.Params = gin.Params{{Key: "productId", Value: strconv.Itoa(aValidProductId)}}
+
+	handl := handler.ProductHandlerImpl{CatalogService: catalogServiceMock}
+
+	handl.HandleGetProduct(context)
+
+	var resp response.ProductResponse
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, getValidProduct(), resp)
+}
+
+func Test_GetProduct_NotFound(t *testing.T) {
+	catalogServiceMock := &CatalogServiceMock{}
+
+	catalogServiceMock.On("GetProduct", aValidProductId).Return(response.ProductResponse{}, errors.New("product with id: 2 not found"))
+
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/products/2", nil)
+	context.Params = gin.Params{{Key: "productId", Value: strconv.Itoa(aValidProductId)}}
+
+	handl := handler.ProductHandlerImpl{CatalogService: catalogServiceMock}
+
+	handl.HandleGetProduct(context)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}